@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/screwyprof/delegator/pkg/logger"
+	"github.com/screwyprof/delegator/pkg/pgxdb"
+	"github.com/screwyprof/delegator/web/export"
+	"github.com/screwyprof/delegator/web/export/config"
+	"github.com/screwyprof/delegator/web/store/pgxstore"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+// These values are overridden at build time using -ldflags
+var (
+	version = "dev"
+	date    = "unknown"
+)
+
+func main() {
+	// Load configuration from environment
+	cfg := config.New()
+
+	// Log to stderr: stdout carries the CSV
+	log := logger.NewFromConfig(logger.Config{
+		LogLevel:         cfg.LogLevel,
+		LogHumanFriendly: cfg.LogHumanFriendly,
+		Output:           os.Stderr,
+	})
+	slog.SetDefault(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	year, err := tezos.ParseYearFromUint64(cfg.Year)
+	if err != nil {
+		log.ErrorContext(ctx, "Invalid export year", slog.Uint64("year", cfg.Year), slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	log.InfoContext(ctx, "Delegations export starting",
+		slog.Uint64("year", year.Uint64()),
+		slog.Uint64("batchSize", cfg.BatchSize),
+		slog.String("version", version),
+		slog.String("date", date),
+	)
+
+	// A single connection is enough for a sequential keyset scan
+	db, err := pgxdb.NewConnection(ctx, cfg.DatabaseURL, pgxdb.WithMinConns(1), pgxdb.WithMaxConns(1))
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to connect to database", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	finder, finderCloser := pgxstore.New(db)
+	defer finderCloser()
+
+	out := bufio.NewWriter(os.Stdout)
+	written, err := export.WriteCSV(ctx, out, finder, year, cfg.BatchSize)
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "Export failed", slog.Int64("written", written), slog.Any("error", err))
+		finderCloser()
+		os.Exit(1)
+	}
+
+	log.InfoContext(ctx, "Delegations export completed", slog.Int64("written", written))
+}
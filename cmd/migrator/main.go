@@ -32,6 +32,7 @@ func main() {
 
 	log.Info("Starting database migrator service",
 		slog.String("migrationsDir", cfg.MigrationsDir),
+		slog.String("tableName", cfg.TableName),
 		slog.Uint64("initialCheckpoint", cfg.InitialCheckpoint),
 		slog.String("version", version),
 		slog.String("date", date),
@@ -54,7 +55,7 @@ func main() {
 
 	// Apply migrations
 	log.Info("Applying database migrations")
-	if err := migrator.ApplyMigrations(db, cfg.MigrationsDir); err != nil {
+	if err := migrator.ApplyMigrations(db, cfg.MigrationsDir, migrator.WithTableName(cfg.TableName)); err != nil {
 		log.Error("Failed to apply migrations", slog.Any("error", err))
 		os.Exit(1)
 	}
@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
 	"github.com/screwyprof/delegator/pkg/logger"
 	"github.com/screwyprof/delegator/pkg/pgxdb"
 	"github.com/screwyprof/delegator/pkg/tzkt"
@@ -47,20 +51,45 @@ func main() {
 	// Database setup is now handled by the migrator service
 
 	// Initialize store
-	store, storeCloser := pgxstore.New(db)
+	store, storeCloser := newStore(db, cfg)
 	defer storeCloser()
 
 	// HTTP client & tzkt client
 	httpClient := &http.Client{Timeout: cfg.HttpClientTimeout}
-	tzktClient := tzkt.NewClient(httpClient, cfg.TzktAPIURL)
+	tzktOpts := []tzkt.Option{tzkt.WithStrictDecoding(cfg.TzktStrictDecoding)}
+	if cfg.TzktMaxRetries > 0 {
+		tzktOpts = append(tzktOpts, tzkt.WithRetry(httpkit.WithMaxRetries(cfg.TzktMaxRetries)))
+	}
+	tzktClient := tzkt.NewClient(httpClient, cfg.TzktAPIURL, tzktOpts...)
 
 	// Create scraper service
-	scraperService := scraper.NewService(
-		tzktClient,
-		store,
+	opts := []scraper.Option{
 		scraper.WithChunkSize(cfg.ChunkSize),
 		scraper.WithPollInterval(cfg.PollInterval),
-	)
+		scraper.WithBackfillTimeout(cfg.BackfillTimeout),
+		scraper.WithDryRun(cfg.DryRun),
+		scraper.WithCheckpointReconciliation(cfg.ReconcileCheckpoint),
+		scraper.WithSuppressEmptyPollEvents(cfg.SuppressEmptyPolls),
+		scraper.WithMaxPollCycles(cfg.MaxPollCycles),
+		scraper.WithMaxConsecutivePollErrors(cfg.MaxPollErrors),
+		scraper.WithTimestampCheckpoint(cfg.TimestampCheckpoint),
+		scraper.WithIdleBackoff(cfg.IdleBackoffMax, cfg.IdleBackoffFactor),
+		scraper.WithSkipExistingBatches(cfg.SkipExistingBatches),
+		scraper.WithConfirmationLag(cfg.ConfirmationLag),
+		scraper.WithConcurrentPolling(cfg.ConcurrentPolling),
+		scraper.WithHeartbeat(cfg.HeartbeatInterval),
+		scraper.WithCatchUpBurst(cfg.CatchUpBurst),
+		scraper.WithStartupProbe(cfg.StartupProbe),
+	}
+	if cfg.BlockHashEnrichment {
+		opts = append(opts, scraper.WithBlockHashEnrichment(tzktClient))
+	}
+	scraperService := scraper.NewService(tzktClient, store, opts...)
+
+	// Optionally repair ID gaps once before backfill
+	if cfg.RepairGaps {
+		repairGaps(ctx, scraperService, log)
+	}
 
 	// Start service
 	log.InfoContext(ctx, "Starting delegation scraper service",
@@ -79,6 +108,49 @@ func main() {
 	log.InfoContext(ctx, "Scraper service stopped gracefully")
 }
 
+// newStore builds the delegation store on db. With a reconnect threshold configured, the
+// pool is recreated from the database URL after that many failed transactions in a row.
+func newStore(db *pgxpool.Pool, cfg config.Config) (scraper.Store, func()) {
+	storeOpts := []pgxstore.Option{
+		pgxstore.WithSmallBatchThreshold(cfg.SmallBatchThreshold),
+		pgxstore.WithStreamingCopy(cfg.StreamingCopy),
+		pgxstore.WithSortBeforeInsert(cfg.SortBeforeInsert),
+	}
+	if cfg.ReconnectThreshold <= 0 {
+		return pgxstore.New(db, storeOpts...)
+	}
+
+	connect := func(ctx context.Context) (*pgxpool.Pool, error) {
+		return pgxdb.NewConnection(ctx, cfg.DatabaseURL)
+	}
+	return pgxstore.NewReconnecting(db, connect,
+		pgxstore.WithStoreOptions(storeOpts...),
+		pgxstore.WithReconnectThreshold(cfg.ReconnectThreshold),
+	)
+}
+
+// repairGaps re-fetches missing delegation ranges and logs the outcome; failures are logged, not fatal
+func repairGaps(ctx context.Context, svc *scraper.Service, log *slog.Logger) {
+	log.InfoContext(ctx, "Repairing delegation ID gaps")
+
+	result, err := svc.RepairGaps(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Gap repair failed",
+			slog.Int("gapsFound", result.Gaps),
+			slog.Int("windowsProcessed", result.Windows),
+			slog.Int("recovered", result.Recovered),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	log.InfoContext(ctx, "Gap repair completed",
+		slog.Int("gaps", result.Gaps),
+		slog.Int("windows", result.Windows),
+		slog.Int("recovered", result.Recovered),
+	)
+}
+
 // setupEventLogging configures event handlers using slog directly
 func setupEventLogging(ctx context.Context, events <-chan scraper.Event, log *slog.Logger) func() {
 	return scraper.NewSubscriber(events,
@@ -86,6 +158,7 @@ func setupEventLogging(ctx context.Context, events <-chan scraper.Event, log *sl
 			log.InfoContext(ctx, "Backfill started",
 				slog.String("startedAt", event.StartedAt.Format(logger.BritishTimeFormat)),
 				slog.Int64("checkpointID", event.CheckpointID),
+				slog.Bool("coldStart", event.ColdStart),
 			)
 		}),
 		scraper.OnBackfillSyncCompleted(func(event scraper.BackfillSyncCompleted) {
@@ -117,10 +190,16 @@ func setupEventLogging(ctx context.Context, events <-chan scraper.Event, log *sl
 					slog.Uint64("chunkSize", event.ChunkSize),
 				)
 			} else {
-				log.InfoContext(ctx, "Polling cycle completed, no new records")
+				log.InfoContext(ctx, "Polling cycle completed, no new records",
+					slog.Duration("nextInterval", event.NextInterval),
+				)
 			}
 		}),
 		scraper.OnPollingShutdown(func(event scraper.PollingShutdown) {
+			if errors.Is(event.Reason, scraper.ErrTooManyPollErrors) {
+				log.ErrorContext(ctx, "Polling gave up", slog.Any("error", event.Reason))
+				return
+			}
 			log.InfoContext(ctx, "Polling stopped",
 				slog.String("reason", event.Reason.Error()),
 			)
@@ -128,5 +207,11 @@ func setupEventLogging(ctx context.Context, events <-chan scraper.Event, log *sl
 		scraper.OnPollingError(func(event scraper.PollingError) {
 			log.ErrorContext(ctx, "Polling failed", slog.Any("error", event.Err))
 		}),
+		scraper.OnHeartbeat(func(event scraper.Heartbeat) {
+			log.InfoContext(ctx, "Scraper alive",
+				slog.String("at", event.At.Format(logger.BritishTimeFormat)),
+				slog.Int64("checkpointID", event.CheckpointID),
+			)
+		}),
 	)
 }
@@ -2,19 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
 	"github.com/screwyprof/delegator/pkg/logger"
 	"github.com/screwyprof/delegator/pkg/pgxdb"
 	"github.com/screwyprof/delegator/web/config"
 	"github.com/screwyprof/delegator/web/handler"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/store/countcache"
+	"github.com/screwyprof/delegator/web/store/dedup"
 	"github.com/screwyprof/delegator/web/store/pgxstore"
+	"github.com/screwyprof/delegator/web/store/swrcache"
+	"github.com/screwyprof/delegator/web/tezos"
 )
 
 var (
@@ -22,6 +33,9 @@ var (
 	date    = "unknown"
 )
 
+// writeListenRetry is how long to wait before listening again after losing the connection
+const writeListenRetry = 5 * time.Second
+
 func main() {
 	// Load configuration
 	cfg := config.New()
@@ -43,7 +57,7 @@ func main() {
 	)
 
 	// Initialize database connection
-	db, err := pgxdb.NewConnection(ctx, cfg.DatabaseURL)
+	db, err := pgxdb.NewConnection(ctx, cfg.DatabaseURL, pgxdb.WithTracer(pgxstore.NewQueryTracer(log)))
 	if err != nil {
 		log.ErrorContext(ctx, "Failed to connect to database", slog.Any("error", err))
 		os.Exit(1)
@@ -57,20 +71,104 @@ func main() {
 	// Create HTTP server
 	mux := http.NewServeMux()
 
-	// Register handlers with real store
-	tezosHandler := handler.NewTezosGetDelegations(store)
+	// Optionally let identical concurrent listing requests share one query
+	var listFinder tezos.DelegationsFinder = store
+	if cfg.DedupQueries {
+		listFinder = dedup.New(store)
+	}
+
+	// Optionally serve the first listing page from a cache, refreshing stale pages in the background
+	var listCache *swrcache.Finder
+	if cfg.ListCacheFreshness > 0 {
+		listCache = swrcache.New(listFinder, cfg.ListCacheFreshness, cfg.ListCacheStaleness)
+		listFinder = listCache
+	}
+
+	// Liveness and readiness probes; readiness waits for the first successful database ping
+	handler.NewHealth(db).AddRoutes(mux)
+
+	// Register handlers with real store; listing pages are numbered from 0 or 1 as configured,
+	// and JSONP callbacks are only honoured when enabled
+	pageBase := bind.OneBasedPages
+	if cfg.ZeroBasedPages {
+		pageBase = bind.ZeroBasedPages
+	}
+	tezosHandler := handler.NewTezosGetDelegations(listFinder, handler.WithPageBase(pageBase), handler.WithJSONP(cfg.JSONP))
 	tezosHandler.AddRoutes(mux)
 
-	// Wrap with logging middleware
-	loggedMux := logger.NewMiddleware(log)(mux)
+	// Optionally cache counts per filter. The scraper notifies every committed batch, which
+	// drops the cached counts; the TTL bounds staleness while the listener reconnects.
+	var countFinder tezos.DelegationsFinder = store
+	if cfg.CountCacheTTL > 0 {
+		countCache := countcache.New(store, cfg.CountCacheTTL)
+		go listenWrites(ctx, log, db, countCache.NotifyWrite)
+		countFinder = countCache
+	}
+
+	// Optional endpoints are only routed when enabled; disabled ones answer 404
+	for _, feature := range cfg.EnabledFeatures {
+		if !slices.Contains(handler.Features, strings.ToLower(strings.TrimSpace(feature))) {
+			log.WarnContext(ctx, "Ignoring unknown feature", slog.String("feature", feature))
+		}
+	}
+	streamHandler := handler.NewTezosStreamDelegations(store, handler.WithStreamPollInterval(cfg.StreamPoll))
+	handler.FeatureRoutes{
+		handler.FeatureSince:     handler.NewTezosGetDelegationsSince(store),
+		handler.FeatureCount:     handler.NewTezosCountDelegators(countFinder),
+		handler.FeatureRecent:    handler.NewTezosGetRecentDelegations(store),
+		handler.FeatureHistogram: handler.NewTezosGetAmountHistogram(store),
+		handler.FeatureYears:     handler.NewTezosGetDelegationYears(store),
+		handler.FeatureCompare:   handler.NewTezosCompareYears(store),
+		handler.FeatureTop:       handler.NewTezosGetTopDelegations(store),
+		handler.FeatureStream:    streamHandler,
+		handler.FeatureDaily:     handler.NewTezosGetDailyCounts(store),
+		handler.FeatureTVL:       handler.NewTezosGetCumulativeAmounts(store),
+	}.AddRoutes(mux, cfg.FeatureEnabled)
+
+	// Answer 503 instead of hammering the database once queries keep failing. Health probes
+	// bypass the breaker: an open breaker must neither fail liveness nor be closed by it.
+	var apiHandler http.Handler = mux
+	if cfg.BreakerThreshold > 0 {
+		apiHandler = httpkit.NewCircuitBreaker(
+			httpkit.WithFailureThreshold(cfg.BreakerThreshold),
+			httpkit.WithCooldown(cfg.BreakerCooldown),
+			httpkit.WithTripOn(isQueryFailure),
+			httpkit.WithExemptPaths(handler.HealthPath, handler.ReadyPath),
+		)(mux)
+	}
+
+	// Reject over-long URIs inside the logging middleware so rejections are still logged
+	limitedMux := httpkit.MaxURILength(cfg.MaxURILength)(apiHandler)
+
+	// Redirect trailing-slash paths such as /xtz/delegations/ to their registered route
+	limitedMux = httpkit.NormalizePath()(limitedMux)
+
+	// Add security headers to every response, including rejections and redirects
+	if cfg.SecurityHeaders {
+		limitedMux = httpkit.SecurityHeaders(
+			httpkit.WithContentSecurityPolicy(cfg.ContentSecurity),
+			httpkit.WithHSTS(cfg.HSTSMaxAge, false),
+		)(limitedMux)
+	}
+
+	// Wrap with logging middleware, assigning request ids first so logs can be correlated
+	loggedMux := httpkit.NewRequestIDMiddleware()(logger.NewMiddleware(log,
+		logger.WithBodySampling(cfg.LogBodySampleSize),
+		logger.WithExcludePaths(cfg.LogExcludePaths...),
+	)(limitedMux))
 
 	// Create server address
 	addr := net.JoinHostPort(cfg.HTTPHost, cfg.HTTPPort)
 
-	server := &http.Server{
-		Addr:    addr,
-		Handler: loggedMux,
-	}
+	server := httpkit.NewServer(addr, loggedMux,
+		httpkit.WithReadHeaderTimeout(cfg.ReadHeaderTimeout),
+		httpkit.WithReadTimeout(cfg.ReadTimeout),
+		httpkit.WithWriteTimeout(cfg.WriteTimeout),
+		httpkit.WithIdleTimeout(cfg.IdleTimeout),
+	)
+
+	// Long-lived event streams would otherwise hold up graceful shutdown until it times out
+	server.RegisterOnShutdown(streamHandler.Close)
 
 	// Start server in a goroutine
 	go func() {
@@ -95,5 +193,34 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Let background cache refreshes finish before the database pool closes
+	if listCache != nil {
+		listCache.Wait()
+	}
+
 	log.InfoContext(ctx, "Server exited gracefully")
 }
+
+// isQueryFailure reports whether a request failed because the database query failed.
+// Queries aborted by the client going away say nothing about database health.
+func isQueryFailure(err error) bool {
+	return errors.Is(err, pgxstore.ErrQueryFailed) && !errors.Is(err, context.Canceled)
+}
+
+// listenWrites calls onWrite for every batch the scraper commits until ctx is done,
+// listening again after the connection fails
+func listenWrites(ctx context.Context, log *slog.Logger, db *pgxpool.Pool, onWrite func()) {
+	for {
+		err := pgxstore.ListenWrites(ctx, db, onWrite)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WarnContext(ctx, "Lost write notifications, listening again", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(writeListenRetry):
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"os"
 	"time"
 
 	"github.com/caarlos0/env/v11"
+
+	"github.com/screwyprof/delegator/pkg/configfile"
 )
 
 // Config holds configuration for the migrator service
@@ -14,6 +17,9 @@ type Config struct {
 	// Migration configuration
 	MigrationsDir string `env:"MIGRATOR_MIGRATIONS_DIR" envDefault:"migrator/migrations"`
 
+	// Table recording applied migrations; set a distinct one per app sharing the database
+	TableName string `env:"MIGRATOR_TABLE_NAME" envDefault:"schema_migrations"`
+
 	// Initial checkpoint configuration (optional)
 	InitialCheckpoint uint64 `env:"MIGRATOR_INITIAL_CHECKPOINT" envDefault:"0"`
 
@@ -32,7 +38,24 @@ func parseConfig() (Config, error) {
 	return cfg, err
 }
 
-// New loads all configuration from environment variables
+// New loads all configuration from environment variables, or from the file named by
+// MIGRATOR_CONFIG_FILE when it is set, with environment variables taking precedence
 func New() Config {
+	if path := os.Getenv("MIGRATOR_CONFIG_FILE"); path != "" {
+		return env.Must(LoadFromFile(path))
+	}
 	return env.Must(parseConfig())
 }
+
+// LoadFromFile loads configuration from the JSON or YAML file at path, whose keys are
+// environment variable names; variables set in the environment take precedence
+func LoadFromFile(path string) (Config, error) {
+	environment, err := configfile.Environment(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	err = env.ParseWithOptions(&cfg, env.Options{Environment: environment})
+	return cfg, err
+}
@@ -0,0 +1,3 @@
+-- +migrate Up
+-- Add optional block hash, populated only when the scraper runs with block hash enrichment
+ALTER TABLE delegations ADD COLUMN IF NOT EXISTS block_hash TEXT;
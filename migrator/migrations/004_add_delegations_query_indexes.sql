@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Year-only lookups such as counting distinct delegators for a year
+CREATE INDEX IF NOT EXISTS idx_delegations_year ON delegations (year);
+
+-- Newest-first listing with a stable tie-break for delegations sharing a timestamp
+CREATE INDEX IF NOT EXISTS idx_delegations_timestamp_id ON delegations (timestamp DESC, id DESC);
+
+-- Per-delegator lookups and distinct delegator counts
+CREATE INDEX IF NOT EXISTS idx_delegations_delegator ON delegations (delegator);
@@ -1,12 +1,15 @@
 package migrator
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -23,11 +26,13 @@ import (
 	"github.com/screwyprof/delegator/scraper/store/pgxstore"
 )
 
+// DefaultMigrationsTableName is the table applied migrations are recorded in unless configured otherwise
+const DefaultMigrationsTableName = "schema_migrations"
+
 // Migration constants
 const (
-	migrationsTableName = "schema_migrations"
-	schemaHashPrefix    = "schema_only_"
-	seededHashPrefix    = "seeded_demo_"
+	schemaHashPrefix = "schema_only_"
+	seededHashPrefix = "seeded_demo_"
 )
 
 // SQL queries
@@ -41,42 +46,98 @@ const (
 		INSERT INTO scraper_checkpoint (single_row, last_id) 
 		VALUES (TRUE, $1)
 		ON CONFLICT (single_row) DO UPDATE SET last_id = EXCLUDED.last_id`
+
+	truncateDelegationsSQL = `TRUNCATE delegations`
 )
 
+// seedCleanupTimeout bounds the cleanup after a failed seed, which runs even if seeding was cancelled
+const seedCleanupTimeout = 10 * time.Second
+
 // Migration-related errors
 var (
-	ErrMigrationExecution  = errors.New("migration execution failed")
-	ErrCheckpointOperation = errors.New("checkpoint operation failed")
+	ErrMigrationExecution    = errors.New("migration execution failed")
+	ErrMigrationsDirNotFound = errors.New("migrations directory not found")
+	ErrCheckpointOperation   = errors.New("checkpoint operation failed")
+	ErrSeedFailed            = errors.New("demo data seeding failed")
+	ErrSeedTimeout           = errors.New("seeding timed out")
+	ErrSeedIncomplete        = errors.New("seeding stopped without a result")
+	ErrSeedCleanup           = errors.New("failed seed cleanup failed")
 )
 
+// SchemaOption configures the SchemaMigrator and ApplyMigrations
+type SchemaOption func(*SchemaMigrator)
+
+// WithTableName records applied migrations in the named table instead of
+// DefaultMigrationsTableName, so several apps can share one database.
+// An empty name keeps the default.
+func WithTableName(name string) SchemaOption {
+	return func(m *SchemaMigrator) { m.tableName = cmp.Or(name, m.tableName) }
+}
+
 // SchemaMigrator applies only database schema migrations
 // Used for production and tests that need schema-only setup
 type SchemaMigrator struct {
 	migrationsDir string
+	tableName     string
 }
 
 // NewSchemaMigrator creates a migrator that applies schema migrations only
-func NewSchemaMigrator(migrationsDir string) *SchemaMigrator {
-	return &SchemaMigrator{
+func NewSchemaMigrator(migrationsDir string, opts ...SchemaOption) *SchemaMigrator {
+	m := &SchemaMigrator{
 		migrationsDir: migrationsDir,
+		tableName:     DefaultMigrationsTableName,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *SchemaMigrator) Hash() (string, error) {
 	source := &migrate.FileMigrationSource{Dir: m.migrationsDir}
-	migrationSet := &migrate.MigrationSet{TableName: migrationsTableName}
+	migrationSet := &migrate.MigrationSet{TableName: m.tableName}
 	sqlMigrator := sqlmigrator.New(source, migrationSet)
 
+	if err := checkMigrationsDir(m.migrationsDir); err != nil {
+		return "", err
+	}
 	baseHash, err := sqlMigrator.Hash()
 	if err != nil {
 		return "", fmt.Errorf("failed to calculate migration hash for %s: %w", m.migrationsDir, err)
 	}
 
-	return schemaHashPrefix + baseHash, nil
+	return schemaHashPrefix + baseHash + tableHashSuffix(m.tableName), nil
 }
 
 func (m *SchemaMigrator) Migrate(ctx context.Context, db *sql.DB, conf pgtestdb.Config) error {
-	return applyMigrations(db, m.migrationsDir)
+	return applyMigrations(db, m.migrationsDir, m.tableName)
+}
+
+// SeededOption configures the SeededMigrator
+type SeededOption func(*SeededMigrator)
+
+// WithSeedPoolSize limits the connection pool used for one-shot seeding.
+// Without it the seeding pool uses pgxdb production defaults.
+func WithSeedPoolSize(minConns, maxConns int32) SeededOption {
+	return func(m *SeededMigrator) {
+		m.poolOptions = append(m.poolOptions, pgxdb.WithMinConns(minConns), pgxdb.WithMaxConns(maxConns))
+	}
+}
+
+// WithSeedAPIURL overrides the TzKT API URL used for seeding
+func WithSeedAPIURL(url string) SeededOption {
+	return func(m *SeededMigrator) { m.apiURL = url }
+}
+
+// WithSeedTableName is WithTableName for the SeededMigrator
+func WithSeedTableName(name string) SeededOption {
+	return func(m *SeededMigrator) { m.tableName = cmp.Or(name, m.tableName) }
+}
+
+// WithSeedMaxRecords stops seeding once n delegations are stored, so seeded databases
+// have a small, deterministic size. Zero (the default) seeds until the API runs dry.
+func WithSeedMaxRecords(n uint64) SeededOption {
+	return func(m *SeededMigrator) { m.maxRecords = n }
 }
 
 // SeededMigrator applies schema migrations + seeds with demo delegation data
@@ -86,34 +147,53 @@ type SeededMigrator struct {
 	demoCheckpoint int64
 	chunkSize      uint64
 	seedTimeout    time.Duration
+	maxRecords     uint64
+	poolOptions    []pgxdb.Option
+	apiURL         string
+	tableName      string
 }
 
 // NewSeededMigrator creates a migrator that applies schema + seeds demo data
-func NewSeededMigrator(migrationsDir string, demoCheckpoint int64, chunkSize uint64, seedTimeout time.Duration) *SeededMigrator {
-	return &SeededMigrator{
+func NewSeededMigrator(migrationsDir string, demoCheckpoint int64, chunkSize uint64, seedTimeout time.Duration, opts ...SeededOption) *SeededMigrator {
+	m := &SeededMigrator{
 		migrationsDir:  migrationsDir,
 		demoCheckpoint: demoCheckpoint,
 		chunkSize:      chunkSize,
 		seedTimeout:    seedTimeout,
+		apiURL:         config.New().TzktAPIURL,
+		tableName:      DefaultMigrationsTableName,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// SeedPoolConfig returns the connection pool configuration used for seeding
+func (m *SeededMigrator) SeedPoolConfig(dbURL string) (*pgxpool.Config, error) {
+	return pgxdb.NewConfig(dbURL, m.poolOptions...)
 }
 
 func (m *SeededMigrator) Hash() (string, error) {
 	source := &migrate.FileMigrationSource{Dir: m.migrationsDir}
-	migrationSet := &migrate.MigrationSet{TableName: migrationsTableName}
+	migrationSet := &migrate.MigrationSet{TableName: m.tableName}
 	sqlMigrator := sqlmigrator.New(source, migrationSet)
 
+	if err := checkMigrationsDir(m.migrationsDir); err != nil {
+		return "", err
+	}
 	baseHash, err := sqlMigrator.Hash()
 	if err != nil {
 		return "", fmt.Errorf("failed to calculate migration hash for %s: %w", m.migrationsDir, err)
 	}
 
-	return seededHashPrefix + baseHash + "_" + strconv.FormatInt(m.demoCheckpoint, 10) + "_" + strconv.FormatUint(m.chunkSize, 10), nil
+	return seededHashPrefix + baseHash + tableHashSuffix(m.tableName) + "_" + strconv.FormatInt(m.demoCheckpoint, 10) + "_" + strconv.FormatUint(m.chunkSize, 10) +
+		"_" + strconv.FormatUint(m.maxRecords, 10), nil
 }
 
 func (m *SeededMigrator) Migrate(ctx context.Context, db *sql.DB, conf pgtestdb.Config) error {
 	// Apply schema migrations using common function
-	if err := applyMigrations(db, m.migrationsDir); err != nil {
+	if err := applyMigrations(db, m.migrationsDir, m.tableName); err != nil {
 		return err
 	}
 
@@ -121,11 +201,14 @@ func (m *SeededMigrator) Migrate(ctx context.Context, db *sql.DB, conf pgtestdb.
 	return m.seedDemoData(ctx, conf.URL())
 }
 
-// seedDemoData seeds the template database with demo delegation data
+// seedDemoData seeds the template database with demo delegation data.
+// If seeding fails, partially seeded delegations are removed and the checkpoint
+// is reset, leaving the database schema-only with the demo checkpoint.
 func (m *SeededMigrator) seedDemoData(ctx context.Context, dbURL string) error {
 	slog.InfoContext(ctx, "🌱 Seeding demo database with delegation data",
 		"checkpoint", m.demoCheckpoint,
 		"chunkSize", m.chunkSize,
+		"maxRecords", m.maxRecords,
 		"timeout", m.seedTimeout)
 
 	// Create context with timeout for seeding
@@ -133,7 +216,7 @@ func (m *SeededMigrator) seedDemoData(ctx context.Context, dbURL string) error {
 	defer cancel()
 
 	// Create database connection for seeding
-	pool, err := pgxdb.NewConnection(seedCtx, dbURL)
+	pool, err := pgxdb.NewConnection(seedCtx, dbURL, m.poolOptions...)
 	if err != nil {
 		return err
 	}
@@ -152,54 +235,77 @@ func (m *SeededMigrator) seedDemoData(ctx context.Context, dbURL string) error {
 	cfg.ChunkSize = m.chunkSize // Use the configured chunk size, not default
 
 	httpClient := &http.Client{Timeout: cfg.HttpClientTimeout}
-	client := tzkt.NewClient(httpClient, cfg.TzktAPIURL)
+	client := tzkt.NewClient(httpClient, m.apiURL)
 
 	service := scraper.NewService(
 		client,
 		store,
 		scraper.WithChunkSize(cfg.ChunkSize),
 		scraper.WithPollInterval(cfg.PollInterval),
+		scraper.WithMaxBackfillRecords(m.maxRecords),
 	)
 
 	// Run scraper to seed data
 	events, done := service.Start(seedCtx)
 
-	// Use channel for safe communication between goroutines
-	resultChan := make(chan error, 1)
-
-	// Use subscriber pattern for cleaner event handling
+	// Every run ends with exactly one BackfillDone or BackfillError; the result
+	// stays ErrSeedIncomplete only if the service stopped without reporting either.
+	result := ErrSeedIncomplete
 	subscriberCloser := scraper.NewSubscriber(events,
 		scraper.OnBackfillDone(func(e scraper.BackfillDone) {
 			slog.InfoContext(seedCtx, "✅ Demo database seeding completed successfully")
-			resultChan <- nil // Signal success
-			cancel()          // Stop seeding
+			result = nil
+			cancel() // Stop seeding
 		}),
 		scraper.OnBackfillError(func(e scraper.BackfillError) {
-			resultChan <- e.Err // Signal error
-			cancel()            // Stop seeding on error
+			result = e.Err
+			cancel() // Stop seeding on error
 		}),
 	)
-	defer subscriberCloser()
 
-	// Wait for completion or timeout (handled by context)
+	// Wait for the service to stop, then for every event to be handled,
+	// after which result is final
 	<-done
+	subscriberCloser()
 
-	// Get result from channel (non-blocking since we know service finished)
-	select {
-	case err := <-resultChan:
-		return err
-	default:
-		return nil // No result received, assume success
+	if result == nil {
+		return nil
+	}
+
+	seedErr := fmt.Errorf("%w: %w", ErrSeedFailed, result)
+	if errors.Is(seedCtx.Err(), context.DeadlineExceeded) {
+		seedErr = fmt.Errorf("%w: %w: after %v", ErrSeedFailed, ErrSeedTimeout, m.seedTimeout)
+	}
+
+	if err := m.cleanupFailedSeed(ctx, pool); err != nil {
+		return errors.Join(seedErr, err)
+	}
+	return seedErr
+}
+
+// cleanupFailedSeed removes partially seeded delegations and resets the checkpoint.
+// It runs detached from ctx cancellation, since seeding usually fails because ctx ended.
+func (m *SeededMigrator) cleanupFailedSeed(ctx context.Context, pool *pgxpool.Pool) error {
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), seedCleanupTimeout)
+	defer cancel()
+
+	if _, err := pool.Exec(cleanupCtx, truncateDelegationsSQL); err != nil {
+		return fmt.Errorf("%w: %w", ErrSeedCleanup, err)
+	}
+	if err := SetCheckpoint(cleanupCtx, pool, uint64(m.demoCheckpoint)); err != nil {
+		return fmt.Errorf("%w: %w", ErrSeedCleanup, err)
 	}
+	return nil
 }
 
 // ApplyMigrations applies database migrations using sql-migrate with the provided pgx pool
-func ApplyMigrations(pool *pgxpool.Pool, migrationsDir string) error {
+func ApplyMigrations(pool *pgxpool.Pool, migrationsDir string, opts ...SchemaOption) error {
 	// Create sql.DB from the pgx pool for sql-migrate
 	db := stdlib.OpenDBFromPool(pool)
 	defer db.Close()
 
-	return applyMigrations(db, migrationsDir)
+	m := NewSchemaMigrator(migrationsDir, opts...)
+	return applyMigrations(db, m.migrationsDir, m.tableName)
 }
 
 // InitializeCheckpoint initializes the scraper checkpoint if not already set
@@ -220,10 +326,14 @@ func SetCheckpoint(ctx context.Context, pool *pgxpool.Pool, checkpoint uint64) e
 	return nil
 }
 
-// applyMigrations applies database migrations using sql-migrate
-func applyMigrations(db *sql.DB, migrationsDir string) error {
+// applyMigrations applies database migrations using sql-migrate, recording them in tableName
+func applyMigrations(db *sql.DB, migrationsDir, tableName string) error {
+	if err := checkMigrationsDir(migrationsDir); err != nil {
+		return err
+	}
+
 	source := &migrate.FileMigrationSource{Dir: migrationsDir}
-	migrationSet := &migrate.MigrationSet{TableName: migrationsTableName}
+	migrationSet := &migrate.MigrationSet{TableName: tableName}
 
 	_, err := migrationSet.Exec(db, "postgres", source, migrate.Up)
 	if err != nil {
@@ -231,3 +341,25 @@ func applyMigrations(db *sql.DB, migrationsDir string) error {
 	}
 	return nil
 }
+
+// tableHashSuffix tells template databases tracking migrations in a custom table apart,
+// keeping the hashes of the default table unchanged
+func tableHashSuffix(tableName string) string {
+	if tableName == DefaultMigrationsTableName {
+		return ""
+	}
+	return "_" + tableName
+}
+
+// checkMigrationsDir reports a missing migrations directory up front, since sql-migrate
+// fails on it with an error that does not name the path
+func checkMigrationsDir(migrationsDir string) error {
+	info, err := os.Stat(migrationsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrMigrationsDirNotFound, migrationsDir)
+	}
+	if err == nil && !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrMigrationsDirNotFound, migrationsDir)
+	}
+	return nil
+}
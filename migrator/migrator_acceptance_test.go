@@ -0,0 +1,270 @@
+//go:build acceptance
+
+package migrator_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/peterldowns/pgtestdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/migrator"
+	"github.com/screwyprof/delegator/migrator/migratortest"
+	"github.com/screwyprof/delegator/scraper"
+)
+
+const (
+	migrationsDir  = "migrations"
+	demoCheckpoint = 1
+)
+
+// TestSchemaMigratorAcceptanceBehavior tests migration tracking against a real PostgreSQL database
+func TestSchemaMigratorAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it tracks applied migrations in a custom table", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		migrationFiles, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+		require.NoError(t, err)
+
+		// Act
+		pool := migratortest.CreateSchemaTestDatabase(t, migrationsDir, migrator.WithTableName("app_migrations"))
+		defer pool.Close()
+
+		// Assert
+		var tracked int
+		err = pool.QueryRow(t.Context(), "SELECT COUNT(*) FROM app_migrations").Scan(&tracked)
+		require.NoError(t, err)
+		assert.Equal(t, len(migrationFiles), tracked, "Every migration should be recorded in the custom table")
+		assertTableMissing(t, pool, migrator.DefaultMigrationsTableName)
+	})
+}
+
+// TestSeededMigratorAcceptanceBehavior tests seeding against a real PostgreSQL database
+func TestSeededMigratorAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the seed error and removes partially seeded data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiFailingAfterFirstBatch()
+		defer server.Close()
+
+		pool := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer pool.Close()
+
+		m := migrator.NewSeededMigrator(migrationsDir, demoCheckpoint, 1, 5*time.Second,
+			migrator.WithSeedAPIURL(server.URL),
+			migrator.WithSeedPoolSize(1, 2),
+		)
+
+		// Act
+		err := runMigrate(t, m, pool)
+
+		// Assert
+		assert.ErrorIs(t, err, migrator.ErrSeedFailed)
+		assert.ErrorIs(t, err, scraper.ErrAPIRequestFailed)
+		assertNoDelegationsStored(t, pool)
+		assertCheckpointIs(t, pool, demoCheckpoint)
+	})
+
+	t.Run("it seeds delegations until backfill completes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithSingleBatch()
+		defer server.Close()
+
+		pool := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer pool.Close()
+
+		m := migrator.NewSeededMigrator(migrationsDir, demoCheckpoint, 1, 5*time.Second,
+			migrator.WithSeedAPIURL(server.URL),
+			migrator.WithSeedPoolSize(1, 2),
+		)
+
+		// Act
+		err := runMigrate(t, m, pool)
+
+		// Assert
+		require.NoError(t, err)
+		assertDelegationsStored(t, pool, 1)
+		assertCheckpointIs(t, pool, demoCheckpoint+1)
+	})
+
+	t.Run("it stops seeding at the configured max records", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithEndlessDelegations()
+		defer server.Close()
+
+		pool := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer pool.Close()
+
+		m := migrator.NewSeededMigrator(migrationsDir, demoCheckpoint, 2, 5*time.Second,
+			migrator.WithSeedAPIURL(server.URL),
+			migrator.WithSeedPoolSize(1, 2),
+			migrator.WithSeedMaxRecords(5),
+		)
+
+		// Act
+		err := runMigrate(t, m, pool)
+
+		// Assert
+		require.NoError(t, err)
+		assertDelegationsStored(t, pool, 5)
+		assertCheckpointIs(t, pool, demoCheckpoint+5)
+	})
+
+	t.Run("it reports a timeout when seeding does not finish in time", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiHangingUntilCancelled()
+		defer server.Close()
+
+		pool := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer pool.Close()
+
+		m := migrator.NewSeededMigrator(migrationsDir, demoCheckpoint, 1, 100*time.Millisecond,
+			migrator.WithSeedAPIURL(server.URL),
+			migrator.WithSeedPoolSize(1, 2),
+		)
+
+		// Act
+		err := runMigrate(t, m, pool)
+
+		// Assert
+		assert.ErrorIs(t, err, migrator.ErrSeedFailed)
+		assert.ErrorIs(t, err, migrator.ErrSeedTimeout)
+		assertNoDelegationsStored(t, pool)
+	})
+}
+
+// apiFailingAfterFirstBatch serves one delegation, then fails every further request
+func apiFailingAfterFirstBatch() *httptest.Server {
+	var calls atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "server error"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `[{"id":%d,"timestamp":"2024-01-01T00:00:00Z","amount":1000000,"sender":{"address":"tz1abc"},"level":100}]`, demoCheckpoint+1)
+	}))
+}
+
+// apiWithSingleBatch serves one delegation, then reports no more data
+func apiWithSingleBatch() *httptest.Server {
+	var calls atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) > 1 {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `[{"id":%d,"timestamp":"2024-01-01T00:00:00Z","amount":1000000,"sender":{"address":"tz1abc"},"level":100}]`, demoCheckpoint+1)
+	}))
+}
+
+// apiWithEndlessDelegations always serves the next delegations after id.gt, honouring limit
+func apiWithEndlessDelegations() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after, _ := strconv.ParseInt(r.URL.Query().Get("id.gt"), 10, 64)
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+
+		batch := make([]string, 0, limit)
+		for id := after + 1; id <= after+limit; id++ {
+			batch = append(batch, fmt.Sprintf(`{"id":%d,"timestamp":"2024-01-01T00:00:00Z","amount":1000000,"sender":{"address":"tz1abc"},"level":100}`, id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(batch, ",") + "]"))
+	}))
+}
+
+// apiHangingUntilCancelled never answers before the client gives up
+func apiHangingUntilCancelled() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+}
+
+// runMigrate runs the migrator against the database behind pool
+func runMigrate(t *testing.T, m *migrator.SeededMigrator, pool *pgxpool.Pool) error {
+	t.Helper()
+
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+
+	return m.Migrate(t.Context(), db, testDBConfig(pool))
+}
+
+// testDBConfig describes the database behind pool as a pgtestdb config
+func testDBConfig(pool *pgxpool.Pool) pgtestdb.Config {
+	cc := pool.Config().ConnConfig
+	return pgtestdb.Config{
+		DriverName: "pgx",
+		Host:       cc.Host,
+		Port:       strconv.Itoa(int(cc.Port)),
+		User:       cc.User,
+		Password:   cc.Password,
+		Database:   cc.Database,
+		Options:    "sslmode=disable",
+	}
+}
+
+// assertTableMissing verifies no table with the given name exists
+func assertTableMissing(t *testing.T, pool *pgxpool.Pool, table string) {
+	t.Helper()
+
+	var exists bool
+	err := pool.QueryRow(t.Context(), "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists)
+	require.NoError(t, err)
+	assert.False(t, exists, "Table %s should not exist", table)
+}
+
+// assertNoDelegationsStored verifies the failed seed left no delegations behind
+func assertNoDelegationsStored(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	var count int64
+	err := pool.QueryRow(t.Context(), "SELECT COUNT(*) FROM delegations").Scan(&count)
+	require.NoError(t, err)
+	assert.Zero(t, count, "Failed seed should not leave delegations behind")
+}
+
+// assertDelegationsStored verifies the number of seeded delegations
+func assertDelegationsStored(t *testing.T, pool *pgxpool.Pool, expected int64) {
+	t.Helper()
+
+	var count int64
+	err := pool.QueryRow(t.Context(), "SELECT COUNT(*) FROM delegations").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, expected, count, "Seed should store the fetched delegations")
+}
+
+// assertCheckpointIs verifies the stored scraper checkpoint
+func assertCheckpointIs(t *testing.T, pool *pgxpool.Pool, expected int64) {
+	t.Helper()
+
+	var checkpoint int64
+	err := pool.QueryRow(t.Context(), "SELECT last_id FROM scraper_checkpoint").Scan(&checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, expected, checkpoint, "Checkpoint should match the seed outcome")
+}
@@ -12,9 +12,15 @@ import (
 	"github.com/screwyprof/delegator/scraper/testcfg"
 )
 
+// Seeding runs once per template database, so a tiny pool is enough
+const (
+	seedPoolMinConns = 1
+	seedPoolMaxConns = 2
+)
+
 // CreateScraperTestDatabase creates a test database with migrations applied + scraper checkpoint initialized.
 // Returns the connection pool ready for use.
-func CreateScraperTestDatabase(t *testing.T, migrationsDir string, initialCheckpoint uint64) *pgxpool.Pool {
+func CreateScraperTestDatabase(t testing.TB, migrationsDir string, initialCheckpoint uint64) *pgxpool.Pool {
 	t.Helper()
 
 	// Apply schema migrations first
@@ -28,19 +34,30 @@ func CreateScraperTestDatabase(t *testing.T, migrationsDir string, initialCheckp
 	return pool
 }
 
+// CreateSchemaTestDatabase creates a test database with only the schema migrations applied,
+// configured by opts, e.g. to track them in a custom table
+func CreateSchemaTestDatabase(t testing.TB, migrationsDir string, opts ...migrator.SchemaOption) *pgxpool.Pool {
+	t.Helper()
+
+	return createTestDatabaseWithMigrator(t, migrator.NewSchemaMigrator(migrationsDir, opts...))
+}
+
 // CreateSeededTestDatabase creates a test database with migrations and demo data seeded.
 // Returns the connection pool ready for use.
-func CreateSeededTestDatabase(t *testing.T, migrationsDir string) *pgxpool.Pool {
+func CreateSeededTestDatabase(t testing.TB, migrationsDir string) *pgxpool.Pool {
 	t.Helper()
 
 	scraperCfg := testcfg.New()
 
-	migratorInstance := migrator.NewSeededMigrator(migrationsDir, scraperCfg.Checkpoint, scraperCfg.ChunkSize, scraperCfg.SeedTimeout)
+	migratorInstance := migrator.NewSeededMigrator(migrationsDir, scraperCfg.Checkpoint, scraperCfg.ChunkSize, scraperCfg.SeedTimeout,
+		migrator.WithSeedPoolSize(seedPoolMinConns, seedPoolMaxConns),
+		migrator.WithSeedMaxRecords(scraperCfg.SeedMaxRecords),
+	)
 	return createTestDatabaseWithMigrator(t, migratorInstance)
 }
 
 // createTestDatabaseWithMigrator creates a test database using the provided migrator
-func createTestDatabaseWithMigrator(t *testing.T, migratorInstance pgtestdb.Migrator) *pgxpool.Pool {
+func createTestDatabaseWithMigrator(t testing.TB, migratorInstance pgtestdb.Migrator) *pgxpool.Pool {
 	t.Helper()
 
 	config := createTestDatabaseConfig()
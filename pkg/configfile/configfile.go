@@ -0,0 +1,95 @@
+// Package configfile reads configuration files keyed by environment variable name, so
+// services configured through env struct tags can load them in place of a long list
+// of exported variables
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files that are neither JSON nor YAML
+	ErrUnsupportedFormat = errors.New("unsupported config file format")
+	// ErrInvalidFile is returned when the file cannot be read or decoded
+	ErrInvalidFile = errors.New("invalid config file")
+)
+
+// Environment reads the file at path and returns its values overlaid with the process
+// environment, which takes precedence. The file is a flat object mapping variable names
+// to values, e.g. {"SCRAPER_CHUNK_SIZE": 500}; .json files are decoded as JSON and
+// .yaml or .yml files as YAML. Lists are joined with commas.
+func Environment(path string) (map[string]string, error) {
+	values, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := make(map[string]string, len(values))
+	for name, value := range values {
+		s, err := format(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidFile, path, name, err)
+		}
+		environment[name] = s
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		environment[name] = value
+	}
+	return environment, nil
+}
+
+func read(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	var values map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFile, path, err)
+	}
+	return values, nil
+}
+
+// format renders a decoded value the way it would be written in an environment variable
+func format(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := format(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", errors.New("nested objects are not supported")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
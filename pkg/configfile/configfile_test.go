@@ -0,0 +1,99 @@
+package configfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/configfile"
+)
+
+func TestEnvironment(t *testing.T) {
+	t.Run("it reads JSON values as environment variable strings", func(t *testing.T) {
+		// Arrange
+		path := writeFile(t, "config.json", `{
+			"CONFIGFILE_TEST_SIZE": 10000,
+			"CONFIGFILE_TEST_INTERVAL": "10s",
+			"CONFIGFILE_TEST_ENABLED": true,
+			"CONFIGFILE_TEST_FEATURES": ["since", "count"]
+		}`)
+
+		// Act
+		environment, err := configfile.Environment(path)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "10000", environment["CONFIGFILE_TEST_SIZE"])
+		assert.Equal(t, "10s", environment["CONFIGFILE_TEST_INTERVAL"])
+		assert.Equal(t, "true", environment["CONFIGFILE_TEST_ENABLED"])
+		assert.Equal(t, "since,count", environment["CONFIGFILE_TEST_FEATURES"])
+	})
+
+	t.Run("it reads YAML values", func(t *testing.T) {
+		// Arrange
+		path := writeFile(t, "config.yaml", "CONFIGFILE_TEST_SIZE: 500\nCONFIGFILE_TEST_INTERVAL: 1m\n")
+
+		// Act
+		environment, err := configfile.Environment(path)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "500", environment["CONFIGFILE_TEST_SIZE"])
+		assert.Equal(t, "1m", environment["CONFIGFILE_TEST_INTERVAL"])
+	})
+
+	t.Run("it lets the process environment override the file", func(t *testing.T) {
+		// Arrange
+		path := writeFile(t, "config.json", `{"CONFIGFILE_TEST_SIZE": 500, "CONFIGFILE_TEST_INTERVAL": "10s"}`)
+		t.Setenv("CONFIGFILE_TEST_SIZE", "42")
+
+		// Act
+		environment, err := configfile.Environment(path)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "42", environment["CONFIGFILE_TEST_SIZE"])
+		assert.Equal(t, "10s", environment["CONFIGFILE_TEST_INTERVAL"])
+	})
+
+	t.Run("it rejects files of other formats", func(t *testing.T) {
+		// Arrange
+		path := writeFile(t, "config.toml", `CONFIGFILE_TEST_SIZE = 500`)
+
+		// Act
+		_, err := configfile.Environment(path)
+
+		// Assert
+		require.ErrorIs(t, err, configfile.ErrUnsupportedFormat)
+	})
+
+	t.Run("it rejects nested objects", func(t *testing.T) {
+		// Arrange
+		path := writeFile(t, "config.json", `{"CONFIGFILE_TEST_DB": {"host": "localhost"}}`)
+
+		// Act
+		_, err := configfile.Environment(path)
+
+		// Assert
+		require.ErrorIs(t, err, configfile.ErrInvalidFile)
+	})
+
+	t.Run("it reports a missing file", func(t *testing.T) {
+		// Act
+		_, err := configfile.Environment(filepath.Join(t.TempDir(), "missing.json"))
+
+		// Assert
+		require.ErrorIs(t, err, configfile.ErrInvalidFile)
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,105 @@
+// Package ctxkit provides typed, collision-free context values
+package ctxkit
+
+import "context"
+
+// Key identifies a context value of type T. Keys are compared by identity,
+// so two keys never collide even when they share a name and a type.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a distinct key; name is only used for debugging
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// With returns a context carrying v under the key
+func (k *Key[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Value gets the value stored under the key, reporting whether it was present
+func (k *Key[T]) Value(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// String returns the key name, as shown when printing a context
+func (k *Key[T]) String() string {
+	return "ctxkit.Key(" + k.name + ")"
+}
+
+// Well-known request-scoped keys
+var (
+	requestIDKey = NewKey[string]("request-id")
+	errorKey     = NewKey[*errorHolder]("error")
+	routeKey     = NewKey[*routeHolder]("route")
+)
+
+// WithRequestID returns a context carrying the given request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return requestIDKey.With(ctx, id)
+}
+
+// RequestID gets the request id from context, or an empty string if absent
+func RequestID(ctx context.Context) string {
+	id, _ := requestIDKey.Value(ctx)
+	return id
+}
+
+// errorHolder is a mutable slot, so errors set deep in a handler are visible to outer middleware
+type errorHolder struct {
+	err error
+}
+
+// WithErrorTracking creates context with error tracking capability, or returns existing context if already present
+func WithErrorTracking(ctx context.Context) context.Context {
+	if _, ok := errorKey.Value(ctx); ok {
+		return ctx // Already has error tracking
+	}
+	return errorKey.With(ctx, &errorHolder{})
+}
+
+// SetError sets error in the context; it is a no-op without error tracking
+func SetError(ctx context.Context, err error) {
+	if holder, ok := errorKey.Value(ctx); ok {
+		holder.err = err
+	}
+}
+
+// Error gets error from context
+func Error(ctx context.Context) error {
+	if holder, ok := errorKey.Value(ctx); ok {
+		return holder.err
+	}
+	return nil
+}
+
+// routeHolder is a mutable slot, so the route matched by an inner mux is visible to outer middleware
+type routeHolder struct {
+	pattern string
+}
+
+// WithRouteTracking creates context with route tracking capability, or returns existing context if already present
+func WithRouteTracking(ctx context.Context) context.Context {
+	if _, ok := routeKey.Value(ctx); ok {
+		return ctx // Already has route tracking
+	}
+	return routeKey.With(ctx, &routeHolder{})
+}
+
+// SetRoute records the matched route pattern in the context; it is a no-op without route tracking
+func SetRoute(ctx context.Context, pattern string) {
+	if holder, ok := routeKey.Value(ctx); ok {
+		holder.pattern = pattern
+	}
+}
+
+// Route gets the matched route pattern from context, or an empty string if none was recorded
+func Route(ctx context.Context) string {
+	if holder, ok := routeKey.Value(ctx); ok {
+		return holder.pattern
+	}
+	return ""
+}
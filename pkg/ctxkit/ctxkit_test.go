@@ -0,0 +1,173 @@
+package ctxkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/ctxkit"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it round-trips a value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		key := ctxkit.NewKey[int]("answer")
+
+		// Act
+		v, ok := key.Value(key.With(t.Context(), 42))
+
+		// Assert
+		assert.True(t, ok)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("it reports a missing value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		key := ctxkit.NewKey[string]("missing")
+
+		// Act
+		v, ok := key.Value(t.Context())
+
+		// Assert
+		assert.False(t, ok)
+		assert.Empty(t, v)
+	})
+
+	t.Run("it keeps keys with the same name and type apart", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		first := ctxkit.NewKey[string]("id")
+		second := ctxkit.NewKey[string]("id")
+		ctx := second.With(first.With(t.Context(), "first"), "second")
+
+		// Act
+		firstValue, _ := first.Value(ctx)
+		secondValue, _ := second.Value(ctx)
+
+		// Assert
+		assert.Equal(t, "first", firstValue)
+		assert.Equal(t, "second", secondValue)
+	})
+
+	t.Run("it does not collide with foreign keys of the same name", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		key := ctxkit.NewKey[string]("request-id")
+		ctx := context.WithValue(t.Context(), foreignKey("request-id"), "foreign")
+
+		// Act
+		_, ok := key.Value(ctx)
+
+		// Assert
+		assert.False(t, ok)
+		assert.Empty(t, ctxkit.RequestID(ctx))
+	})
+}
+
+// foreignKey is a conventional string-based key defined outside ctxkit
+type foreignKey string
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it round-trips the request id", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		ctx := ctxkit.WithRequestID(t.Context(), "abc123")
+
+		// Assert
+		assert.Equal(t, "abc123", ctxkit.RequestID(ctx))
+	})
+
+	t.Run("it returns an empty string when absent", func(t *testing.T) {
+		t.Parallel()
+
+		// Assert
+		assert.Empty(t, ctxkit.RequestID(t.Context()))
+	})
+}
+
+func TestErrorTracking(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it exposes an error set through a derived context", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		errBoom := errors.New("boom")
+		ctx := ctxkit.WithErrorTracking(t.Context())
+		derived := ctxkit.WithRequestID(ctx, "abc123")
+
+		// Act
+		ctxkit.SetError(derived, errBoom)
+
+		// Assert
+		assert.ErrorIs(t, ctxkit.Error(ctx), errBoom)
+		assert.Equal(t, "abc123", ctxkit.RequestID(derived), "Request id should be unaffected")
+	})
+
+	t.Run("it reuses existing tracking", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		errBoom := errors.New("boom")
+		outer := ctxkit.WithErrorTracking(t.Context())
+
+		// Act
+		inner := ctxkit.WithErrorTracking(outer)
+		ctxkit.SetError(inner, errBoom)
+
+		// Assert
+		assert.Equal(t, outer, inner)
+		assert.ErrorIs(t, ctxkit.Error(outer), errBoom)
+	})
+
+	t.Run("it ignores errors without tracking", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		ctxkit.SetError(t.Context(), errors.New("boom"))
+
+		// Assert
+		assert.NoError(t, ctxkit.Error(t.Context()))
+	})
+}
+
+func TestRouteTracking(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it exposes a route set through a derived context", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx := ctxkit.WithRouteTracking(t.Context())
+		derived := ctxkit.WithErrorTracking(ctx)
+
+		// Act
+		ctxkit.SetRoute(derived, "GET /xtz/delegations/{id}")
+
+		// Assert
+		assert.Equal(t, "GET /xtz/delegations/{id}", ctxkit.Route(ctx))
+	})
+
+	t.Run("it ignores routes without tracking", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		ctxkit.SetRoute(t.Context(), "GET /xtz/delegations")
+
+		// Assert
+		assert.Empty(t, ctxkit.Route(t.Context()))
+	})
+}
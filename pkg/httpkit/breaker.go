@@ -0,0 +1,183 @@
+package httpkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/clock"
+)
+
+// Circuit breaker defaults
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerCooldown         = 10 * time.Second
+)
+
+// ErrCircuitOpen is the cause recorded when a request is short-circuited by an open breaker
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerClock provides the current time to the circuit breaker
+type BreakerClock interface {
+	Now() time.Time
+}
+
+// BreakerOption configures the circuit breaker middleware
+type BreakerOption func(*circuitBreaker)
+
+// WithFailureThreshold sets how many consecutive tripping failures open the breaker
+func WithFailureThreshold(n int) BreakerOption {
+	return func(b *circuitBreaker) {
+		if n > 0 {
+			b.threshold = n
+		}
+	}
+}
+
+// WithCooldown sets how long the breaker stays open before letting a probe request through
+func WithCooldown(d time.Duration) BreakerOption {
+	return func(b *circuitBreaker) {
+		if d > 0 {
+			b.cooldown = d
+		}
+	}
+}
+
+// WithTripOn sets which request errors (as recorded with SetError) count as failures.
+// By default every 5xx HTTPError does.
+func WithTripOn(trips func(error) bool) BreakerOption {
+	return func(b *circuitBreaker) {
+		if trips != nil {
+			b.trips = trips
+		}
+	}
+}
+
+// WithExemptPaths lets requests for the given URL paths, e.g. health probes, bypass the
+// breaker: they are served while it is open and their outcome is not recorded, so a
+// probe that never touches the dependency can neither fail nor close the breaker.
+func WithExemptPaths(paths ...string) BreakerOption {
+	return func(b *circuitBreaker) {
+		for _, path := range paths {
+			b.exempt[path] = true
+		}
+	}
+}
+
+// WithBreakerClock injects a custom clock (e.g., for testing)
+func WithBreakerClock(c BreakerClock) BreakerOption {
+	return func(b *circuitBreaker) { b.clock = c }
+}
+
+// NewCircuitBreaker creates middleware that stops calling a failing dependency.
+// After the configured number of consecutive tripping errors, requests are answered
+// with 503 Service Unavailable and a Retry-After header without reaching the handler.
+// Once the cooldown elapses a single probe request is let through: success closes
+// the breaker, another failure re-opens it for a fresh cooldown.
+func NewCircuitBreaker(opts ...BreakerOption) func(http.Handler) http.Handler {
+	b := &circuitBreaker{
+		threshold: DefaultBreakerFailureThreshold,
+		cooldown:  DefaultBreakerCooldown,
+		trips:     isServerError,
+		clock:     clock.SystemClock{},
+		exempt:    make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if b.exempt[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			probe, retryAfter, ok := b.allow()
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				JsonError(circuitOpen(retryAfter))(w, r)
+				return
+			}
+
+			r = r.WithContext(WithErrorTracking(r.Context()))
+			next.ServeHTTP(w, r)
+			b.record(probe, b.trips(Error(r.Context())))
+		})
+	}
+}
+
+// circuitBreaker tracks consecutive failures; it is open while openedAt is set
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	trips     func(error) bool
+	clock     BreakerClock
+	exempt    map[string]bool
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a request may proceed and whether it is the recovery probe,
+// or else the seconds to advertise in Retry-After
+func (b *circuitBreaker) allow() (probe bool, retryAfter int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return false, 0, true
+	}
+
+	remaining := b.cooldown - b.clock.Now().Sub(b.openedAt)
+	if remaining <= 0 && !b.probing {
+		b.probing = true
+		return true, 0, true
+	}
+
+	return false, retryAfterSeconds(remaining), false
+}
+
+// record updates the breaker with the outcome of a request that was let through
+func (b *circuitBreaker) record(probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	if !failed {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	if probe || b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+	}
+}
+
+// retryAfterSeconds rounds the remaining cooldown up to whole seconds, at least one
+func retryAfterSeconds(remaining time.Duration) int {
+	seconds := int((remaining + time.Second - 1) / time.Second)
+	return max(seconds, 1)
+}
+
+func isServerError(err error) bool {
+	var httpErr HTTPError
+	return errors.As(err, &httpErr) && httpErr.HTTPCode() >= http.StatusInternalServerError
+}
+
+func circuitOpen(retryAfter int) *statusError {
+	return &statusError{
+		cause: fmt.Errorf("%w: retry after %ds", ErrCircuitOpen, retryAfter),
+		code:  http.StatusServiceUnavailable,
+	}
+}
@@ -0,0 +1,288 @@
+package httpkit_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+var errQueryFailed = errors.New("delegation query failed")
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes requests through while the dependency is healthy", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{}
+		breaker := newTestBreaker(&stoppedClock{}, dep)
+
+		// Act
+		codes := serveBreakerTimes(breaker, 10)
+
+		// Assert
+		assert.Equal(t, repeatCode(http.StatusOK, 10), codes)
+		assert.Equal(t, int32(10), dep.calls.Load())
+	})
+
+	t.Run("it opens after repeated failures and short-circuits with 503", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(&stoppedClock{}, dep)
+
+		// Act
+		codes := serveBreakerTimes(breaker, 3)
+		w := serveBreaker(breaker)
+
+		// Assert
+		assert.Equal(t, repeatCode(http.StatusInternalServerError, 3), codes)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "10", w.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"code":503,"message":"Service Unavailable"}`, w.Body.String())
+		assert.Equal(t, int32(3), dep.calls.Load(), "Should not call the dependency while open")
+	})
+
+	t.Run("it resets the failure count on success", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{}
+		breaker := newTestBreaker(&stoppedClock{}, dep)
+
+		// Act
+		dep.failing.Store(true)
+		serveBreakerTimes(breaker, 2)
+		dep.failing.Store(false)
+		serveBreakerTimes(breaker, 1)
+		dep.failing.Store(true)
+		codes := serveBreakerTimes(breaker, 2)
+
+		// Assert
+		assert.Equal(t, repeatCode(http.StatusInternalServerError, 2), codes, "Should still be closed")
+	})
+
+	t.Run("it ignores errors that do not trip it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{cause: errors.New("bad year")}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(&stoppedClock{}, dep)
+
+		// Act
+		codes := serveBreakerTimes(breaker, 5)
+
+		// Assert
+		assert.Equal(t, repeatCode(http.StatusInternalServerError, 5), codes)
+		assert.Equal(t, int32(5), dep.calls.Load())
+	})
+
+	t.Run("it shortens Retry-After as the cooldown elapses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		clock := &stoppedClock{}
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(clock, dep)
+		serveBreakerTimes(breaker, 3)
+
+		// Act
+		clock.advance(7500 * time.Millisecond)
+		w := serveBreaker(breaker)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "3", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("it recovers once a probe after the cooldown succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		clock := &stoppedClock{}
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(clock, dep)
+		serveBreakerTimes(breaker, 3)
+
+		// Act
+		dep.failing.Store(false)
+		clock.advance(10 * time.Second)
+		codes := serveBreakerTimes(breaker, 3)
+
+		// Assert
+		assert.Equal(t, repeatCode(http.StatusOK, 3), codes)
+		assert.Equal(t, int32(6), dep.calls.Load())
+	})
+
+	t.Run("it re-opens when the probe fails", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		clock := &stoppedClock{}
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(clock, dep)
+		serveBreakerTimes(breaker, 3)
+
+		// Act
+		clock.advance(10 * time.Second)
+		probe := serveBreaker(breaker)
+		w := serveBreaker(breaker)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, probe.Code)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "10", w.Header().Get("Retry-After"), "Should start a fresh cooldown")
+		assert.Equal(t, int32(4), dep.calls.Load())
+	})
+
+	t.Run("it serves exempt paths while open without recording them", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+
+		mux := http.NewServeMux()
+		mux.Handle("/xtz/delegations", dep.handler())
+		mux.Handle("/healthz", httpkit.HandlerFunc(func(http.ResponseWriter, *http.Request) http.HandlerFunc {
+			return httpkit.JSON(map[string]string{"status": "ok"})
+		}))
+
+		clock := &stoppedClock{}
+		breaker := httpkit.NewCircuitBreaker(
+			httpkit.WithFailureThreshold(3),
+			httpkit.WithCooldown(10*time.Second),
+			httpkit.WithTripOn(func(err error) bool { return errors.Is(err, errQueryFailed) }),
+			httpkit.WithBreakerClock(clock),
+			httpkit.WithExemptPaths("/healthz"),
+		)(mux)
+		serveBreakerTimes(breaker, 3)
+
+		// Act
+		whileOpen := serveHealth(breaker)
+		clock.advance(10 * time.Second)
+		afterCooldown := serveHealth(breaker)
+		codes := serveBreakerTimes(breaker, 2)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, whileOpen.Code, "Liveness should not fail while the breaker is open")
+		assert.Equal(t, http.StatusOK, afterCooldown.Code)
+		assert.Equal(t, []int{http.StatusInternalServerError, http.StatusServiceUnavailable}, codes,
+			"A health probe should neither take nor close the recovery probe")
+	})
+
+	t.Run("it records the open circuit as the request error", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		dep := &flakyDependency{}
+		dep.failing.Store(true)
+		breaker := newTestBreaker(&stoppedClock{}, dep)
+		serveBreakerTimes(breaker, 3)
+
+		var recorded error
+		tracking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(httpkit.WithErrorTracking(r.Context()))
+			breaker.ServeHTTP(w, r)
+			recorded = httpkit.Error(r.Context())
+		})
+
+		// Act
+		tracking.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+
+		// Assert
+		assert.ErrorIs(t, recorded, httpkit.ErrCircuitOpen)
+	})
+}
+
+// flakyDependency fails with a 500 wrapping cause (errQueryFailed by default) while failing is set
+type flakyDependency struct {
+	failing atomic.Bool
+	calls   atomic.Int32
+	cause   error
+}
+
+func (d *flakyDependency) handler() http.Handler {
+	return httpkit.HandlerFunc(func(http.ResponseWriter, *http.Request) http.HandlerFunc {
+		d.calls.Add(1)
+		if d.failing.Load() {
+			cause := d.cause
+			if cause == nil {
+				cause = fmt.Errorf("%w: connection refused", errQueryFailed)
+			}
+			return httpkit.JsonError(internalError{cause: cause})
+		}
+		return httpkit.JSON(map[string]string{"status": "ok"})
+	})
+}
+
+// internalError is a minimal 500 HTTPError
+type internalError struct {
+	cause error
+}
+
+func (e internalError) HTTPCode() int { return http.StatusInternalServerError }
+func (e internalError) Cause() error  { return e.cause }
+func (e internalError) Error() string { return http.StatusText(http.StatusInternalServerError) }
+func (e internalError) Unwrap() error { return e.cause }
+
+// stoppedClock only moves when advanced
+type stoppedClock struct {
+	now atomic.Int64
+}
+
+func (c *stoppedClock) Now() time.Time { return time.Unix(0, c.now.Load()) }
+
+func (c *stoppedClock) advance(d time.Duration) { c.now.Add(int64(d)) }
+
+func newTestBreaker(clock httpkit.BreakerClock, dep *flakyDependency) http.Handler {
+	return httpkit.NewCircuitBreaker(
+		httpkit.WithFailureThreshold(3),
+		httpkit.WithCooldown(10*time.Second),
+		httpkit.WithTripOn(func(err error) bool { return errors.Is(err, errQueryFailed) }),
+		httpkit.WithBreakerClock(clock),
+	)(dep.handler())
+}
+
+func serveBreaker(h http.Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+	return w
+}
+
+func serveHealth(h http.Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	return w
+}
+
+func serveBreakerTimes(h http.Handler, n int) []int {
+	codes := make([]int, 0, n)
+	for range n {
+		codes = append(codes, serveBreaker(h).Code)
+	}
+	return codes
+}
+
+func repeatCode(code, n int) []int {
+	codes := make([]int, n)
+	for i := range codes {
+		codes[i] = code
+	}
+	return codes
+}
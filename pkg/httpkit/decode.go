@@ -0,0 +1,71 @@
+package httpkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// DefaultMaxJSONBodySize is the largest request body DecodeJSON accepts unless configured otherwise
+const DefaultMaxJSONBodySize = 1 << 20
+
+// Request body decoding errors. Every DecodeJSON failure wraps ErrInvalidJSONBody, and
+// its message is safe to show the client, so callers can answer 400 Bad Request with it.
+var (
+	ErrInvalidJSONBody        = errors.New("invalid JSON request body")
+	ErrUnsupportedContentType = errors.New("content type must be application/json")
+	ErrBodyTooLarge           = errors.New("request body too large")
+)
+
+// DecodeOption configures DecodeJSON
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	maxBodySize int64
+}
+
+// WithMaxBodySize sets the largest body, in bytes, DecodeJSON accepts
+func WithMaxBodySize(n int64) DecodeOption {
+	return func(c *decodeConfig) { c.maxBodySize = n }
+}
+
+// DecodeJSON decodes the request body as a single JSON value of type T. The request
+// must be sent as application/json (any charset parameter is ignored), the body may not
+// exceed DefaultMaxJSONBodySize, and fields unknown to T are rejected.
+func DecodeJSON[T any](r *http.Request, opts ...DecodeOption) (T, error) {
+	cfg := decodeConfig{maxBodySize: DefaultMaxJSONBodySize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var v T
+	contentType := r.Header.Get(contentTypeHeader)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+		return v, fmt.Errorf("%w: %w, got %q", ErrInvalidJSONBody, ErrUnsupportedContentType, contentType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, cfg.maxBodySize+1))
+	if err != nil {
+		return v, fmt.Errorf("%w: %w", ErrInvalidJSONBody, err)
+	}
+	if int64(len(body)) > cfg.maxBodySize {
+		return v, fmt.Errorf("%w: %w: limit is %d bytes", ErrInvalidJSONBody, ErrBodyTooLarge, cfg.maxBodySize)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&v); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("empty body")
+		}
+		return v, fmt.Errorf("%w: %w", ErrInvalidJSONBody, err)
+	}
+	if decoder.More() {
+		return v, fmt.Errorf("%w: unexpected data after the JSON value", ErrInvalidJSONBody)
+	}
+	return v, nil
+}
@@ -0,0 +1,128 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+type payload struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it decodes a valid payload", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := jsonRequest(`{"id": 1, "name": "tz1a"}`, "application/json; charset=utf-8")
+
+		// Act
+		got, err := httpkit.DecodeJSON[payload](r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, payload{ID: 1, Name: "tz1a"}, got)
+	})
+
+	t.Run("it rejects a wrong content type", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := jsonRequest(`{"id": 1}`, "text/plain")
+
+		// Act
+		_, err := httpkit.DecodeJSON[payload](r)
+
+		// Assert
+		require.ErrorIs(t, err, httpkit.ErrInvalidJSONBody)
+		assert.ErrorIs(t, err, httpkit.ErrUnsupportedContentType)
+	})
+
+	t.Run("it rejects a missing content type", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := jsonRequest(`{"id": 1}`, "")
+
+		// Act
+		_, err := httpkit.DecodeJSON[payload](r)
+
+		// Assert
+		assert.ErrorIs(t, err, httpkit.ErrUnsupportedContentType)
+	})
+
+	t.Run("it rejects an oversize body", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := jsonRequest(`{"name": "`+strings.Repeat("a", 64)+`"}`, "application/json")
+
+		// Act
+		_, err := httpkit.DecodeJSON[payload](r, httpkit.WithMaxBodySize(32))
+
+		// Assert
+		require.ErrorIs(t, err, httpkit.ErrInvalidJSONBody)
+		assert.ErrorIs(t, err, httpkit.ErrBodyTooLarge)
+	})
+
+	t.Run("it accepts a body of exactly the maximum size", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		body := `{"id": 1}`
+		r := jsonRequest(body, "application/json")
+
+		// Act
+		_, err := httpkit.DecodeJSON[payload](r, httpkit.WithMaxBodySize(int64(len(body))))
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("it rejects an unknown field", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := jsonRequest(`{"id": 1, "amount": 5}`, "application/json")
+
+		// Act
+		_, err := httpkit.DecodeJSON[payload](r)
+
+		// Assert
+		require.ErrorIs(t, err, httpkit.ErrInvalidJSONBody)
+		assert.Contains(t, err.Error(), `"amount"`)
+	})
+
+	t.Run("it rejects malformed, empty and trailing content", func(t *testing.T) {
+		t.Parallel()
+
+		for _, body := range []string{`{"id": `, ``, `{"id": 1} {"id": 2}`} {
+			// Arrange
+			r := jsonRequest(body, "application/json")
+
+			// Act
+			_, err := httpkit.DecodeJSON[payload](r)
+
+			// Assert
+			assert.ErrorIs(t, err, httpkit.ErrInvalidJSONBody, body)
+		}
+	})
+}
+
+func jsonRequest(body, contentType string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
@@ -0,0 +1,65 @@
+package httpkit
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Header constants for compressed request bodies
+const (
+	contentEncodingHeader = "Content-Encoding"
+	contentLengthHeader   = "Content-Length"
+	gzipEncoding          = "gzip"
+)
+
+// ErrCorruptGzip is the cause recorded when a gzip request body cannot be decompressed
+var ErrCorruptGzip = errors.New("corrupt gzip request body")
+
+// DecompressRequest creates middleware that transparently decompresses request bodies
+// sent with Content-Encoding: gzip, so handlers read plain content. The encoding and
+// length headers are removed once the body is replaced. A body that is not gzip at all
+// is rejected with 400 Bad Request before the handler runs; corruption further into the
+// stream surfaces as a read error in the handler. Other encodings pass through untouched.
+func DecompressRequest() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.EqualFold(strings.TrimSpace(r.Header.Get(contentEncodingHeader)), gzipEncoding) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				JsonError(corruptGzip(err))(w, r)
+				return
+			}
+
+			r.Body = &gzipBody{Reader: zr, compressed: r.Body}
+			r.Header.Del(contentEncodingHeader)
+			r.Header.Del(contentLengthHeader)
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func corruptGzip(err error) *statusError {
+	return &statusError{
+		cause: fmt.Errorf("%w: %w", ErrCorruptGzip, err),
+		code:  http.StatusBadRequest,
+	}
+}
+
+// gzipBody reads decompressed content and closes both the gzip reader and the original body
+type gzipBody struct {
+	*gzip.Reader
+	compressed io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	return errors.Join(b.Reader.Close(), b.compressed.Close())
+}
@@ -0,0 +1,121 @@
+package httpkit_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestDecompressRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it hands the handler the decompressed body", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewReader(gzipped(t, `{"id":1}`)))
+		r.Header.Set("Content-Encoding", "gzip")
+
+		// Act
+		w, received := serveDecompressed(t, r)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"id":1}`, received.body)
+		assert.Empty(t, received.encoding, "The encoding no longer applies to the body")
+		assert.Equal(t, int64(-1), received.length)
+	})
+
+	t.Run("it matches the encoding case-insensitively", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewReader(gzipped(t, "hello")))
+		r.Header.Set("Content-Encoding", "GZIP")
+
+		// Act
+		_, received := serveDecompressed(t, r)
+
+		// Assert
+		assert.Equal(t, "hello", received.body)
+	})
+
+	t.Run("it passes uncompressed bodies through", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader("plain"))
+
+		// Act
+		w, received := serveDecompressed(t, r)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "plain", received.body)
+	})
+
+	t.Run("it rejects a corrupt gzip body with 400", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader("not gzip at all"))
+		r.Header.Set("Content-Encoding", "gzip")
+
+		// Act
+		w, received := serveDecompressed(t, r)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"code":400,"message":"Bad Request"}`, w.Body.String())
+		assert.False(t, received.called, "The handler should not run for a corrupt body")
+	})
+}
+
+// receivedRequest records what the wrapped handler saw
+type receivedRequest struct {
+	called   bool
+	body     string
+	encoding string
+	length   int64
+}
+
+func serveDecompressed(t *testing.T, r *http.Request) (*httptest.ResponseRecorder, *receivedRequest) {
+	t.Helper()
+
+	received := &receivedRequest{}
+	handler := httpkit.DecompressRequest()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, r.Body.Close())
+
+		received.called = true
+		received.body = string(body)
+		received.encoding = r.Header.Get("Content-Encoding")
+		received.length = r.ContentLength
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w, received
+}
+
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,68 @@
+package httpkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header constants for conditional requests
+const (
+	etagHeader        = "ETag"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+// ErrETagEncode is the cause recorded when a conditional response body cannot be encoded
+var ErrETagEncode = errors.New("failed to encode response for ETag")
+
+// ETag returns a strong entity tag derived from the SHA-256 of the body
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ConditionalJSON creates a handler that returns a JSON response tagged with an ETag
+// computed from the encoded body. When the request's If-None-Match matches the tag,
+// it replies 304 Not Modified without a body, so clients can cheaply revalidate results.
+func ConditionalJSON(data any, opts ...ResponseOption) http.HandlerFunc {
+	cfg := newResponseConfig(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(data); err != nil {
+			JsonErrorWithOptions(&statusError{
+				cause: fmt.Errorf("%w: %w", ErrETagEncode, err),
+				code:  http.StatusInternalServerError,
+			}, opts...)(w, r)
+			return
+		}
+
+		etag := ETag(buf.Bytes())
+		w.Header().Set(etagHeader, etag)
+
+		if matchesETag(r.Header.Get(ifNoneMatchHeader), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		cfg.addJSONHeaders(w)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// matchesETag reports whether an If-None-Match header value matches etag using the
+// weak comparison required by RFC 9110: "*" matches anything and W/ prefixes are ignored
+func matchesETag(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
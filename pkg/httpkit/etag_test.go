@@ -0,0 +1,117 @@
+package httpkit_test
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+type countBody struct {
+	Count int64 `json:"count"`
+}
+
+func TestConditionalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the body with an ETag", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveConditional(t, countBody{Count: 3}, "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"count":3}`, w.Body.String())
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("it returns 304 without a body on a matching ETag", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		etag := serveConditional(t, countBody{Count: 3}, "").Header().Get("ETag")
+
+		// Act
+		w := serveConditional(t, countBody{Count: 3}, etag)
+
+		// Assert
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("it returns 200 with a fresh ETag after the data changes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		staleETag := serveConditional(t, countBody{Count: 3}, "").Header().Get("ETag")
+
+		// Act
+		w := serveConditional(t, countBody{Count: 4}, staleETag)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"count":4}`, w.Body.String())
+		assert.NotEqual(t, staleETag, w.Header().Get("ETag"))
+	})
+
+	t.Run("it matches weak tags, lists and wildcards", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		etag := serveConditional(t, countBody{Count: 3}, "").Header().Get("ETag")
+
+		for _, ifNoneMatch := range []string{"W/" + etag, `"other", ` + etag, "*"} {
+			// Act
+			w := serveConditional(t, countBody{Count: 3}, ifNoneMatch)
+
+			// Assert
+			assert.Equal(t, http.StatusNotModified, w.Code, "If-None-Match: %s", ifNoneMatch)
+		}
+	})
+
+	t.Run("it reports unencodable data as an internal error", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.HandlerFunc(func(http.ResponseWriter, *http.Request) http.HandlerFunc {
+			return httpkit.ConditionalJSON(math.Inf(1))
+		})
+		var recorded error
+		tracking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(httpkit.WithErrorTracking(r.Context()))
+			handler.ServeHTTP(w, r)
+			recorded = httpkit.Error(r.Context())
+		})
+		w := httptest.NewRecorder()
+
+		// Act
+		tracking.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xtz/delegators/count", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		require.ErrorIs(t, recorded, httpkit.ErrETagEncode)
+	})
+}
+
+func serveConditional(t *testing.T, data any, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, "/xtz/delegators/count", nil)
+	if ifNoneMatch != "" {
+		r.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+
+	httpkit.ConditionalJSON(data)(w, r)
+
+	return w
+}
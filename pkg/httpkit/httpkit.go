@@ -1,9 +1,14 @@
 package httpkit
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/ctxkit"
 )
 
 // HTTPError interface for HTTP-aware errors with detailed causes
@@ -19,6 +24,9 @@ const (
 	contentTypeOptions = "X-Content-Type-Options"
 )
 
+// ErrJSONEncode is the cause recorded when a JSON response body cannot be encoded
+var ErrJSONEncode = errors.New("failed to encode JSON response")
+
 var (
 	jsonContentType           = []string{"application/json; charset=utf-8"}
 	nosniffContentTypeOptions = []string{"nosniff"}
@@ -31,35 +39,32 @@ func addHeaderIfNotSet(w http.ResponseWriter, key string, value []string) {
 	}
 }
 
-// Context helpers for request-scoped error tracking
-type ctxKeyError struct{}
-
-type errorHolder struct {
-	err error
-}
+// Context helpers for request-scoped error tracking, backed by ctxkit
 
 // WithErrorTracking creates context with error tracking capability, or returns existing context if already present
 func WithErrorTracking(ctx context.Context) context.Context {
-	if _, ok := ctx.Value(ctxKeyError{}).(*errorHolder); ok {
-		return ctx // Already has error tracking
-	}
-	holder := &errorHolder{}
-	return context.WithValue(ctx, ctxKeyError{}, holder)
+	return ctxkit.WithErrorTracking(ctx)
 }
 
 // SetError sets error in the context
 func SetError(ctx context.Context, err error) {
-	if holder, ok := ctx.Value(ctxKeyError{}).(*errorHolder); ok {
-		holder.err = err
-	}
+	ctxkit.SetError(ctx, err)
 }
 
 // Error gets error from context
 func Error(ctx context.Context) error {
-	if holder, ok := ctx.Value(ctxKeyError{}).(*errorHolder); ok {
-		return holder.err
-	}
-	return nil
+	return ctxkit.Error(ctx)
+}
+
+// WithRouteTracking creates context with route tracking capability, or returns existing context if already present
+func WithRouteTracking(ctx context.Context) context.Context {
+	return ctxkit.WithRouteTracking(ctx)
+}
+
+// Route gets the route pattern (e.g. "GET /xtz/delegations/{id}") matched for the request,
+// as recorded by HandlerFunc, or an empty string if no route matched
+func Route(ctx context.Context) string {
+	return ctxkit.Route(ctx)
 }
 
 // HTTP handler utilities
@@ -69,30 +74,90 @@ func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := WithErrorTracking(r.Context())
 	r = r.WithContext(ctx)
 
+	// The mux set Pattern on its own copy of the request; share it with outer middleware
+	ctxkit.SetRoute(ctx, r.Pattern)
+
 	if handler := h(w, r); handler != nil {
 		handler(w, r)
 	}
 }
 
+// ResponseOption configures how JSON responses are written
+type ResponseOption func(*responseConfig)
+
+type responseConfig struct {
+	nosniff        bool
+	problemDetails bool
+}
+
+// WithNosniff toggles the X-Content-Type-Options: nosniff header (on by default).
+// Disable it when a gateway in front of the API already manages security headers.
+func WithNosniff(enabled bool) ResponseOption {
+	return func(c *responseConfig) { c.nosniff = enabled }
+}
+
+func newResponseConfig(opts []ResponseOption) responseConfig {
+	cfg := responseConfig{nosniff: true, problemDetails: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// addJSONHeaders sets the JSON content type and, unless disabled, the nosniff header
+func (c responseConfig) addJSONHeaders(w http.ResponseWriter) {
+	addHeaderIfNotSet(w, contentTypeHeader, jsonContentType)
+	if c.nosniff {
+		addHeaderIfNotSet(w, contentTypeOptions, nosniffContentTypeOptions)
+	}
+}
+
 // JSON creates a handler that returns JSON response
 func JSON(data any) http.HandlerFunc {
+	return JSONWithOptions(data)
+}
+
+// JSONWithOptions is JSON with configurable response headers.
+// The body is encoded before anything is written, so data that cannot be encoded
+// is answered with a 500 error instead of a truncated 200.
+func JSONWithOptions(data any, opts ...ResponseOption) http.HandlerFunc {
+	cfg := newResponseConfig(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
-		addHeaderIfNotSet(w, contentTypeHeader, jsonContentType)
-		addHeaderIfNotSet(w, contentTypeOptions, nosniffContentTypeOptions)
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(data); err != nil {
+			JsonErrorWithOptions(&statusError{
+				cause: fmt.Errorf("%w: %w", ErrJSONEncode, err),
+				code:  http.StatusInternalServerError,
+			}, opts...)(w, r)
+			return
+		}
+
+		cfg.addJSONHeaders(w)
 		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(data)
+		_, _ = w.Write(buf.Bytes())
 	}
 }
 
 // JsonError creates a handler that sets an error in context and writes the error response
 func JsonError(err HTTPError) http.HandlerFunc {
+	return JsonErrorWithOptions(err)
+}
+
+// JsonErrorWithOptions is JsonError with configurable response headers.
+// Clients asking for application/problem+json get RFC 7807 problem details instead.
+func JsonErrorWithOptions(err HTTPError, opts ...ResponseOption) http.HandlerFunc {
+	cfg := newResponseConfig(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set error in context for middleware (if available)
 		SetError(r.Context(), err)
 
+		if cfg.wantsProblemDetails(r) {
+			cfg.writeProblemDetails(w, err)
+			return
+		}
+
 		// Add headers
-		addHeaderIfNotSet(w, contentTypeHeader, jsonContentType)
-		addHeaderIfNotSet(w, contentTypeOptions, nosniffContentTypeOptions)
+		cfg.addJSONHeaders(w)
 
 		// Write the status code and response
 		w.WriteHeader(err.HTTPCode())
@@ -0,0 +1,136 @@
+package httpkit_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestNosniffOption(t *testing.T) {
+	t.Parallel()
+
+	errCause := errors.New("boom")
+
+	testCases := []struct {
+		name    string
+		handler func(opts ...httpkit.ResponseOption) http.HandlerFunc
+	}{
+		{
+			name: "JSON",
+			handler: func(opts ...httpkit.ResponseOption) http.HandlerFunc {
+				return httpkit.JSONWithOptions(map[string]int{"count": 1}, opts...)
+			},
+		},
+		{
+			name: "JsonError",
+			handler: func(opts ...httpkit.ResponseOption) http.HandlerFunc {
+				return httpkit.JsonErrorWithOptions(internalError{cause: errCause}, opts...)
+			},
+		},
+		{
+			name: "ConditionalJSON",
+			handler: func(opts ...httpkit.ResponseOption) http.HandlerFunc {
+				return httpkit.ConditionalJSON(map[string]int{"count": 1}, opts...)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it sets nosniff by default", func(t *testing.T) {
+				t.Parallel()
+
+				// Act
+				w := serveJSONHandler(tc.handler())
+
+				// Assert
+				assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+				assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			})
+
+			t.Run("it omits nosniff when disabled", func(t *testing.T) {
+				t.Parallel()
+
+				// Act
+				w := serveJSONHandler(tc.handler(httpkit.WithNosniff(false)))
+
+				// Assert
+				assert.NotContains(t, w.Header(), "X-Content-Type-Options")
+				assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			})
+		})
+	}
+
+	t.Run("it keeps nosniff on for the plain helpers", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		jsonResp := serveJSONHandler(httpkit.JSON(map[string]int{"count": 1}))
+		errorResp := serveJSONHandler(httpkit.JsonError(internalError{cause: errCause}))
+
+		// Assert
+		assert.Equal(t, "nosniff", jsonResp.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "nosniff", errorResp.Header().Get("X-Content-Type-Options"))
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it writes the encoded body with 200", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveJSONHandler(httpkit.JSON(map[string]int{"count": 1}))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"count":1}`, w.Body.String())
+	})
+
+	t.Run("it answers unencodable data with 500 instead of a truncated 200", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.HandlerFunc(func(http.ResponseWriter, *http.Request) http.HandlerFunc {
+			return httpkit.JSON(map[string]any{"count": 1, "broken": unmarshalable{}})
+		})
+		var recorded error
+		tracking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(httpkit.WithErrorTracking(r.Context()))
+			handler.ServeHTTP(w, r)
+			recorded = httpkit.Error(r.Context())
+		})
+		w := httptest.NewRecorder()
+
+		// Act
+		tracking.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"code":500,"message":"Internal Server Error"}`, w.Body.String())
+		require.ErrorIs(t, recorded, httpkit.ErrJSONEncode)
+		require.ErrorIs(t, recorded, errUnmarshalable)
+	})
+}
+
+var errUnmarshalable = errors.New("cannot marshal")
+
+// unmarshalable always fails to encode as JSON
+type unmarshalable struct{}
+
+func (unmarshalable) MarshalJSON() ([]byte, error) { return nil, errUnmarshalable }
+
+func serveJSONHandler(h http.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+	return w
+}
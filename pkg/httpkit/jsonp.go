@@ -0,0 +1,54 @@
+package httpkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// JavaScriptContentType is the media type of JSONP responses
+const JavaScriptContentType = "application/javascript; charset=utf-8"
+
+// maxJSONPCallbackLength bounds callback names; real ones are short
+const maxJSONPCallbackLength = 128
+
+var (
+	javaScriptContentType = []string{JavaScriptContentType}
+
+	// jsonpCallbackPattern allows plain identifiers and dotted paths such as app.onData,
+	// which cannot smuggle script into the response
+	jsonpCallbackPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+)
+
+// ValidJSONPCallback reports whether name is safe to use as a JSONP callback: a
+// JavaScript identifier or dotted path of identifiers, at most 128 characters long
+func ValidJSONPCallback(name string) bool {
+	return len(name) <= maxJSONPCallbackLength && jsonpCallbackPattern.MatchString(name)
+}
+
+// JSONP creates a handler that returns data as a JSONP script calling callback, for
+// legacy browser clients that cannot use CORS. The callback must have been checked with
+// ValidJSONPCallback. The leading comment keeps the response from being read as
+// another file type, and nosniff is set unless disabled.
+func JSONP(callback string, data any, opts ...ResponseOption) http.HandlerFunc {
+	cfg := newResponseConfig(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(data); err != nil {
+			JsonErrorWithOptions(&statusError{
+				cause: fmt.Errorf("%w: %w", ErrJSONEncode, err),
+				code:  http.StatusInternalServerError,
+			}, opts...)(w, r)
+			return
+		}
+
+		addHeaderIfNotSet(w, contentTypeHeader, javaScriptContentType)
+		if cfg.nosniff {
+			addHeaderIfNotSet(w, contentTypeOptions, nosniffContentTypeOptions)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "/**/%s(%s);", callback, bytes.TrimRight(buf.Bytes(), "\n"))
+	}
+}
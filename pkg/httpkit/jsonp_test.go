@@ -0,0 +1,44 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestJSONP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it wraps the JSON body in the callback", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		httpkit.JSONP("app.onData", map[string]string{"note": "</script>"})(w, r)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.JavaScriptContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, `/**/app.onData({"note":"\u003c/script\u003e"});`, w.Body.String(), "HTML-significant characters should stay escaped")
+	})
+}
+
+func TestValidJSONPCallback(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"cb", "_cb1", "$", "jQuery123_456", "app.handlers.onData"} {
+		assert.True(t, httpkit.ValidJSONPCallback(name), name)
+	}
+	for _, name := range []string{"", "1cb", "alert(1)", "cb;alert(1)", "a..b", "a.", "<script>", "cb//", strings.Repeat("a", 129)} {
+		assert.False(t, httpkit.ValidJSONPCallback(name), name)
+	}
+}
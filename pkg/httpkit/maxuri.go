@@ -0,0 +1,62 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrURITooLong is the cause recorded when a request URI exceeds the configured limit
+var ErrURITooLong = errors.New(http.StatusText(http.StatusRequestURITooLong))
+
+// MaxURILength creates middleware rejecting requests whose RequestURI (path and
+// query) is longer than n bytes with 414 URI Too Long, before they reach the handler.
+// Guards against abusive query strings such as huge id lists. n <= 0 disables the check.
+func MaxURILength(n int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if n <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.RequestURI) > n {
+				JsonError(uriTooLong(len(r.RequestURI), n))(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// statusError is an HTTPError rendered with the same JSON shape as the web API errors
+type statusError struct {
+	cause error
+	code  int
+}
+
+func uriTooLong(length, limit int) *statusError {
+	return &statusError{
+		cause: fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrURITooLong, length, limit),
+		code:  http.StatusRequestURITooLong,
+	}
+}
+
+// HTTPCode returns the HTTP status code for this error
+func (e *statusError) HTTPCode() int { return e.code }
+
+// Cause returns the original error for logging purposes
+func (e *statusError) Cause() error { return e.cause }
+
+// Error returns the user-facing status text
+func (e *statusError) Error() string { return http.StatusText(e.code) }
+
+// Unwrap returns the underlying cause for error unwrapping
+func (e *statusError) Unwrap() error { return e.cause }
+
+// MarshalJSON implements json.Marshaler interface
+func (e *statusError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"code":    e.code,
+		"message": e.Error(),
+	})
+}
@@ -0,0 +1,82 @@
+package httpkit_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestMaxURILength(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes requests within the limit to the handler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.MaxURILength(64)(okHandler())
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?year=2025", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(w, r)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("it rejects an over-long URI with a JSON error", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.MaxURILength(64)(okHandler())
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?fields="+strings.Repeat("amount,", 20), nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(w, r)
+
+		// Assert
+		assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var body struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, http.StatusRequestURITooLong, body.Code)
+		assert.Equal(t, http.StatusText(http.StatusRequestURITooLong), body.Message)
+	})
+
+	t.Run("it records the cause for logging middleware", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var recorded error
+		handler := httpkit.MaxURILength(8)(okHandler())
+		tracking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(httpkit.WithErrorTracking(r.Context()))
+			handler.ServeHTTP(w, r)
+			recorded = httpkit.Error(r.Context())
+		})
+
+		// Act
+		tracking.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+
+		// Assert
+		assert.ErrorIs(t, recorded, httpkit.ErrURITooLong)
+	})
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
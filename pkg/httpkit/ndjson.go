@@ -0,0 +1,82 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON streams
+const NDJSONContentType = "application/x-ndjson"
+
+var ndjsonContentType = []string{NDJSONContentType}
+
+// Accepts reports whether the request's Accept header explicitly lists mediaType.
+// Wildcards are ignored, so clients must opt in to alternative representations.
+func Accepts(r *http.Request, mediaType string) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for part := range strings.SplitSeq(accept, ",") {
+			parsed, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && parsed == mediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NDJSONStream writes values as newline-delimited JSON, one value per line.
+// Headers and the 200 status are sent with the first value, so an error hit before
+// anything was written can still be answered with a regular JSON error.
+type NDJSONStream struct {
+	w       http.ResponseWriter
+	cfg     responseConfig
+	enc     *json.Encoder
+	started bool
+	written int
+}
+
+// NewNDJSONStream creates a stream writing to w
+func NewNDJSONStream(w http.ResponseWriter, opts ...ResponseOption) *NDJSONStream {
+	return &NDJSONStream{
+		w:   w,
+		cfg: newResponseConfig(opts),
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Write encodes v as the next line, sending the response headers first if needed
+func (s *NDJSONStream) Write(v any) error {
+	if !s.started {
+		s.Start()
+	}
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+	s.written++
+	return nil
+}
+
+// Start sends the response headers and 200 status; an empty stream is a valid response
+func (s *NDJSONStream) Start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	addHeaderIfNotSet(s.w, contentTypeHeader, ndjsonContentType)
+	if s.cfg.nosniff {
+		addHeaderIfNotSet(s.w, contentTypeOptions, nosniffContentTypeOptions)
+	}
+	s.w.WriteHeader(http.StatusOK)
+}
+
+// Started reports whether the response headers have been sent
+func (s *NDJSONStream) Started() bool {
+	return s.started
+}
+
+// Written returns the number of values written so far
+func (s *NDJSONStream) Written() int {
+	return s.written
+}
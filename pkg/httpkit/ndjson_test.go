@@ -0,0 +1,138 @@
+package httpkit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestAccepts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		accept []string
+		want   bool
+	}{
+		{name: "it accepts the exact media type", accept: []string{"application/x-ndjson"}, want: true},
+		{name: "it accepts the media type within a list", accept: []string{"application/json, application/x-ndjson;q=0.9"}, want: true},
+		{name: "it accepts the media type in a repeated header", accept: []string{"application/json", "application/x-ndjson"}, want: true},
+		{name: "it ignores wildcards", accept: []string{"*/*"}, want: false},
+		{name: "it rejects other media types", accept: []string{"application/json"}, want: false},
+		{name: "it rejects a missing header", accept: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, accept := range tc.accept {
+				r.Header.Add("Accept", accept)
+			}
+
+			// Act
+			got := httpkit.Accepts(r, httpkit.NDJSONContentType)
+
+			// Assert
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it writes one JSON value per line", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewNDJSONStream(w)
+
+		// Act
+		for i := range 3 {
+			require.NoError(t, stream.Write(map[string]int{"n": i}))
+		}
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.NDJSONContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, 3, stream.Written())
+
+		lines := scanLines(t, w.Body.String())
+		require.Len(t, lines, 3)
+		for i, line := range lines {
+			var got map[string]int
+			require.NoError(t, json.Unmarshal([]byte(line), &got))
+			assert.Equal(t, map[string]int{"n": i}, got)
+		}
+	})
+
+	t.Run("it sends nothing until the first value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+
+		// Act
+		stream := httpkit.NewNDJSONStream(w)
+
+		// Assert
+		assert.False(t, stream.Started())
+		assert.Empty(t, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("it answers an empty stream with 200 and no body", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewNDJSONStream(w)
+
+		// Act
+		stream.Start()
+
+		// Assert
+		assert.True(t, stream.Started())
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.NDJSONContentType, w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("it omits nosniff when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewNDJSONStream(w, httpkit.WithNosniff(false))
+
+		// Act
+		require.NoError(t, stream.Write(1))
+
+		// Assert
+		assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+	})
+}
+
+func scanLines(t *testing.T, body string) []string {
+	t.Helper()
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
@@ -0,0 +1,50 @@
+package httpkit
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NormalizePath creates middleware that maps trailing-slash paths to their canonical
+// form, so "/xtz/delegations/" reaches the route registered as "/xtz/delegations".
+// GET and HEAD requests are answered with a 308 Permanent Redirect, letting clients and
+// caches learn the canonical URL; other methods are rewritten in place, since not every
+// client replays a request body on redirect. The root path "/" is left untouched.
+func NormalizePath() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			canonical, ok := canonicalPath(r.URL.EscapedPath())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				target := canonical
+				if r.URL.RawQuery != "" {
+					target += "?" + r.URL.RawQuery
+				}
+				http.Redirect(w, r, target, http.StatusPermanentRedirect)
+				return
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = strings.TrimRight(r.URL.Path, "/")
+			r2.URL.RawPath = ""
+			if r2.URL.Path == "" {
+				r2.URL.Path = "/"
+			}
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
+
+// canonicalPath strips trailing slashes from an escaped path, reporting whether it
+// changed. Leading slashes are collapsed so the result can never become a
+// protocol-relative URL such as "//evil.example" when used as a redirect target.
+func canonicalPath(escaped string) (string, bool) {
+	if len(escaped) <= 1 || !strings.HasSuffix(escaped, "/") {
+		return escaped, false
+	}
+	return "/" + strings.Trim(escaped, "/"), true
+}
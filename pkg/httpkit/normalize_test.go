@@ -0,0 +1,119 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it redirects a trailing-slash GET to the canonical path", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := normalizedMux()
+
+		// Act
+		w := serveNormalized(handler, http.MethodGet, "/xtz/delegations/?year=2025&page=2")
+
+		// Assert
+		assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+		assert.Equal(t, "/xtz/delegations?year=2025&page=2", w.Header().Get("Location"))
+	})
+
+	t.Run("it reaches the handler by following the redirect", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := httptest.NewServer(normalizedMux())
+		t.Cleanup(server.Close)
+
+		// Act
+		resp, err := server.Client().Get(server.URL + "/xtz/delegations/")
+
+		// Assert
+		if assert.NoError(t, err) {
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "/xtz/delegations", resp.Request.URL.Path)
+		}
+	})
+
+	t.Run("it leaves the canonical path unaffected", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := normalizedMux()
+
+		// Act
+		w := serveNormalized(handler, http.MethodGet, "/xtz/delegations?year=2025")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+	})
+
+	t.Run("it leaves the root path unaffected", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := normalizedMux()
+
+		// Act
+		w := serveNormalized(handler, http.MethodGet, "/")
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+	})
+
+	t.Run("it rewrites non-GET requests in place", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := normalizedMux()
+
+		// Act
+		w := serveNormalized(handler, http.MethodPost, "/xtz/delegations/")
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("it never redirects off-site", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := normalizedMux()
+
+		// Act
+		w := serveNormalized(handler, http.MethodGet, "//evil.example/")
+
+		// Assert
+		assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+		assert.Equal(t, "/evil.example", w.Header().Get("Location"))
+		assert.False(t, strings.HasPrefix(w.Header().Get("Location"), "//"))
+	})
+}
+
+func normalizedMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /xtz/delegations", okHandler())
+	mux.HandleFunc("POST /xtz/delegations", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	return httpkit.NormalizePath()(mux)
+}
+
+func serveNormalized(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
@@ -0,0 +1,37 @@
+package httpkit
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Header constants for RFC 7240 preferences
+const (
+	preferHeader            = "Prefer"
+	preferenceAppliedHeader = "Preference-Applied"
+	returnMinimal           = "return=minimal"
+)
+
+// PrefersMinimal reports whether the request carries Prefer: return=minimal,
+// i.e. the client only cares about the response headers
+func PrefersMinimal(r *http.Request) bool {
+	for _, prefer := range r.Header.Values(preferHeader) {
+		for part := range strings.SplitSeq(prefer, ",") {
+			// Parameters after ';' do not change the preference itself
+			token, _, _ := strings.Cut(part, ";")
+			if strings.EqualFold(strings.Join(strings.Fields(token), ""), returnMinimal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Minimal creates a handler that honours Prefer: return=minimal with 204 No Content.
+// Headers already set on the response, such as Link or ETag, are kept.
+func Minimal() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(preferenceAppliedHeader, returnMinimal)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,68 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestPrefersMinimal(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		prefer []string
+		want   bool
+	}{
+		{name: "it detects return=minimal", prefer: []string{"return=minimal"}, want: true},
+		{name: "it detects the preference within a list", prefer: []string{"respond-async, return=minimal"}, want: true},
+		{name: "it detects the preference in a repeated header", prefer: []string{"respond-async", "return=minimal"}, want: true},
+		{name: "it ignores case, spacing and parameters", prefer: []string{"Return = Minimal; foo=bar"}, want: true},
+		{name: "it rejects return=representation", prefer: []string{"return=representation"}, want: false},
+		{name: "it rejects a missing header", prefer: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, prefer := range tc.prefer {
+				r.Header.Add("Prefer", prefer)
+			}
+
+			// Act
+			got := httpkit.PrefersMinimal(r)
+
+			// Assert
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMinimal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it answers 204 without a body and keeps headers already set", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		w.Header().Set("Link", `</?page=2>; rel="next"`)
+
+		// Act
+		httpkit.Minimal()(w, r)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, `</?page=2>; rel="next"`, w.Header().Get("Link"))
+		assert.Equal(t, "return=minimal", w.Header().Get("Preference-Applied"))
+	})
+}
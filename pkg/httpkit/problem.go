@@ -0,0 +1,56 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemJSONContentType is the RFC 7807 media type for error responses
+const ProblemJSONContentType = "application/problem+json"
+
+var problemJSONContentType = []string{ProblemJSONContentType}
+
+// ProblemDetails is an RFC 7807 error body. The web API has no per-error documentation,
+// so Type is always "about:blank" and Title is the status text.
+type ProblemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewProblemDetails describes err as RFC 7807 problem details. The detail is the
+// user-facing error message, omitted when it merely repeats the title.
+func NewProblemDetails(err HTTPError) ProblemDetails {
+	code := err.HTTPCode()
+	problem := ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+	}
+	if msg := err.Error(); msg != problem.Title {
+		problem.Detail = msg
+	}
+	return problem
+}
+
+// WithProblemDetails toggles answering errors as application/problem+json to clients
+// listing it in Accept (on by default). Other clients always get {code,message}.
+func WithProblemDetails(enabled bool) ResponseOption {
+	return func(c *responseConfig) { c.problemDetails = enabled }
+}
+
+// wantsProblemDetails reports whether the error response should use RFC 7807
+func (c responseConfig) wantsProblemDetails(r *http.Request) bool {
+	return c.problemDetails && Accepts(r, ProblemJSONContentType)
+}
+
+// writeProblemDetails writes err as application/problem+json
+func (c responseConfig) writeProblemDetails(w http.ResponseWriter, err HTTPError) {
+	addHeaderIfNotSet(w, contentTypeHeader, problemJSONContentType)
+	if c.nosniff {
+		addHeaderIfNotSet(w, contentTypeOptions, nosniffContentTypeOptions)
+	}
+	w.WriteHeader(err.HTTPCode())
+	_ = json.NewEncoder(w).Encode(NewProblemDetails(err))
+}
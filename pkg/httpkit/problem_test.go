@@ -0,0 +1,97 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestJsonErrorProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	badRequest := clientError{msg: "invalid page"}
+
+	t.Run("it answers problem details when the client asks for them", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveError(httpkit.JsonError(badRequest), "application/problem+json")
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, httpkit.ProblemJSONContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.JSONEq(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid page"}`, w.Body.String())
+	})
+
+	t.Run("it negotiates problem details within an Accept list", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveError(httpkit.JsonError(badRequest), "application/json;q=0.5, application/problem+json")
+
+		// Assert
+		assert.Equal(t, httpkit.ProblemJSONContentType, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("it omits a detail that repeats the title", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveError(httpkit.JsonError(internalError{}), "application/problem+json")
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500}`, w.Body.String())
+	})
+
+	t.Run("it keeps the default format for other clients", func(t *testing.T) {
+		t.Parallel()
+
+		for _, accept := range []string{"", "*/*", "application/json"} {
+			// Act
+			w := serveError(httpkit.JsonError(badRequest), accept)
+
+			// Assert
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+			assert.JSONEq(t, `{"code":400,"message":"invalid page"}`, w.Body.String(), accept)
+		}
+	})
+
+	t.Run("it keeps the default format when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveError(httpkit.JsonErrorWithOptions(badRequest, httpkit.WithProblemDetails(false)), "application/problem+json")
+
+		// Assert
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"code":400,"message":"invalid page"}`, w.Body.String())
+	})
+}
+
+// clientError is a 400 HTTPError rendered like the web API errors
+type clientError struct {
+	msg string
+}
+
+func (e clientError) HTTPCode() int { return http.StatusBadRequest }
+func (e clientError) Cause() error  { return nil }
+func (e clientError) Error() string { return e.msg }
+func (e clientError) MarshalJSON() ([]byte, error) {
+	return []byte(`{"code":400,"message":"` + e.msg + `"}`), nil
+}
+
+func serveError(h http.HandlerFunc, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	h(w, r)
+	return w
+}
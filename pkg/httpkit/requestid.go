@@ -0,0 +1,49 @@
+package httpkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/ctxkit"
+)
+
+// RequestIDHeader is the header used to propagate the request id
+const RequestIDHeader = "X-Request-ID"
+
+// Context helpers for request correlation, backed by ctxkit
+
+// WithRequestID returns a context carrying the given request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return ctxkit.WithRequestID(ctx, id)
+}
+
+// RequestID gets the request id from context, or an empty string if absent
+func RequestID(ctx context.Context) string {
+	return ctxkit.RequestID(ctx)
+}
+
+// NewRequestIDMiddleware creates middleware that assigns every request an id.
+// An incoming X-Request-ID header is reused, otherwise a random id is generated.
+// The id is stored in the request context and echoed in the response header.
+func NewRequestIDMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" {
+				id = newRequestID()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// newRequestID generates a random 128-bit hex encoded id
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,168 @@
+package httpkit
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retry defaults
+const (
+	DefaultMaxRetries    = 3
+	DefaultBackoffBase   = 100 * time.Millisecond
+	DefaultMaxRetryAfter = time.Minute
+)
+
+// DefaultRetryableStatusCodes are transient failures worth retrying
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryOption configures a RetryTransport
+type RetryOption func(*RetryTransport)
+
+// WithMaxRetries sets how many times a request is retried after the first attempt
+func WithMaxRetries(n int) RetryOption {
+	return func(t *RetryTransport) { t.maxRetries = n }
+}
+
+// WithBackoff sets the delay before each retry; attempt starts at 1 for the first retry
+func WithBackoff(backoff func(attempt int) time.Duration) RetryOption {
+	return func(t *RetryTransport) { t.backoff = backoff }
+}
+
+// WithRetryableStatusCodes replaces the status codes that trigger a retry
+func WithRetryableStatusCodes(codes ...int) RetryOption {
+	return func(t *RetryTransport) { t.retryableStatusCodes = codes }
+}
+
+// WithMaxRetryAfter sets the longest Retry-After the transport waits for itself; a
+// response asking for a longer wait is returned to the caller as it is
+func WithMaxRetryAfter(d time.Duration) RetryOption {
+	return func(t *RetryTransport) { t.maxRetryAfter = d }
+}
+
+// ExponentialBackoff doubles the delay on every attempt, starting from base
+func ExponentialBackoff(base time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		return base << max(attempt-1, 0)
+	}
+}
+
+// RetryTransport is an http.RoundTripper that retries transport errors and
+// retryable status codes. A retryable response carrying Retry-After (e.g. 429) is
+// retried no sooner than it asks, or returned as it is when it asks for more than
+// the max retry-after. Requests whose body cannot be replayed (no GetBody) are sent
+// once. Waiting between attempts stops when the request context ends.
+type RetryTransport struct {
+	next                 http.RoundTripper
+	maxRetries           int
+	backoff              func(attempt int) time.Duration
+	retryableStatusCodes []int
+	maxRetryAfter        time.Duration
+}
+
+// NewRetryTransport wraps next (http.DefaultTransport when nil) with retries
+func NewRetryTransport(next http.RoundTripper, opts ...RetryOption) *RetryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &RetryTransport{
+		next:                 next,
+		maxRetries:           DefaultMaxRetries,
+		backoff:              ExponentialBackoff(DefaultBackoffBase),
+		retryableStatusCodes: DefaultRetryableStatusCodes,
+		maxRetryAfter:        DefaultMaxRetryAfter,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := t.rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if !replayable || attempt >= t.maxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt + 1)
+		if resp != nil {
+			if retryAfter, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > t.maxRetryAfter {
+					return resp, nil
+				}
+				wait = max(wait, retryAfter)
+			}
+
+			// Discard the failed response so its connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rewind returns the request to send for the given attempt, with a fresh body on retries
+func (t *RetryTransport) rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// shouldRetry reports whether the outcome of an attempt is transient
+func (t *RetryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up; retrying would not help
+		return req.Context().Err() == nil
+	}
+	return slices.Contains(t.retryableStatusCodes, resp.StatusCode)
+}
+
+// ParseRetryAfter reads a Retry-After header value, given either as delay-seconds or as
+// an HTTP date, into how long to wait from now. A date in the past yields 0. It reports
+// false when the value is empty or malformed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
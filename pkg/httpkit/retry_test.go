@@ -0,0 +1,301 @@
+package httpkit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestRetryTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it retries retryable statuses until the request succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverFailingTimes(&calls, 2, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(3))
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusOK)
+		assert.Equal(t, int64(3), calls.Load())
+	})
+
+	t.Run("it returns the last response once retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverFailingTimes(&calls, 10, http.StatusBadGateway)
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(2))
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusBadGateway)
+		assert.Equal(t, int64(3), calls.Load())
+	})
+
+	t.Run("it does not retry non-retryable statuses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverFailingTimes(&calls, 1, http.StatusInternalServerError)
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(3))
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusInternalServerError)
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("it retries custom status codes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverFailingTimes(&calls, 1, http.StatusInternalServerError)
+		defer server.Close()
+
+		client := clientWithRetry(server,
+			httpkit.WithMaxRetries(1),
+			httpkit.WithRetryableStatusCodes(http.StatusInternalServerError),
+		)
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusOK)
+		assert.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("it replays the request body on every attempt", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(1))
+
+		// Act
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusOK)
+		assert.Equal(t, []string{"payload", "payload"}, bodies)
+	})
+
+	t.Run("it stops waiting when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverFailingTimes(&calls, 10, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		client := &http.Client{Transport: httpkit.NewRetryTransport(server.Client().Transport,
+			httpkit.WithMaxRetries(3),
+			httpkit.WithBackoff(func(int) time.Duration { return time.Hour }),
+		)}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		// Act
+		resp, err := client.Do(req)
+
+		// Assert
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int64(1), calls.Load())
+	})
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it waits for the Retry-After of a rate-limited response rather than the backoff", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverRateLimitingOnce(&calls, "30")
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(3))
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		// Act
+		resp, err := client.Do(req)
+
+		// Assert
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "The retry should wait for the Retry-After")
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("it retries once a Retry-After in the past has elapsed", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverRateLimitingOnce(&calls, "Wed, 01 Jan 2025 12:00:00 GMT")
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(1))
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusOK)
+		assert.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("it returns a response asking to wait longer than the max retry-after", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := serverRateLimitingOnce(&calls, "30")
+		defer server.Close()
+
+		client := clientWithRetry(server, httpkit.WithMaxRetries(3), httpkit.WithMaxRetryAfter(10*time.Second))
+
+		// Act
+		resp, err := client.Get(server.URL)
+
+		// Assert
+		assertStatus(t, resp, err, http.StatusTooManyRequests)
+		assert.Equal(t, "30", resp.Header.Get("Retry-After"), "The caller should see the requested wait")
+		assert.Equal(t, int64(1), calls.Load())
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := httpkit.ExponentialBackoff(100 * time.Millisecond)
+
+	assert.Equal(t, 100*time.Millisecond, backoff(1))
+	assert.Equal(t, 200*time.Millisecond, backoff(2))
+	assert.Equal(t, 400*time.Millisecond, backoff(3))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "it reads delay-seconds", value: "30", expected: 30 * time.Second, ok: true},
+		{name: "it reads an HTTP date as the time until it", value: "Wed, 01 Jan 2025 12:01:30 GMT", expected: 90 * time.Second, ok: true},
+		{name: "it clamps a past HTTP date to zero", value: "Wed, 01 Jan 2025 11:00:00 GMT", expected: 0, ok: true},
+		{name: "it rejects an empty value", value: "", ok: false},
+		{name: "it rejects negative seconds", value: "-5", ok: false},
+		{name: "it rejects garbage", value: "soon", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			wait, ok := httpkit.ParseRetryAfter(tc.value, now)
+
+			// Assert
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, wait)
+		})
+	}
+}
+
+// serverFailingTimes answers with status for the first n requests and 200 afterwards
+func serverFailingTimes(calls *atomic.Int64, n int64, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// serverRateLimitingOnce answers the first request with 429 and the given Retry-After, then 200
+func serverRateLimitingOnce(calls *atomic.Int64, retryAfter string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// clientWithRetry wraps the server's transport with retries and no backoff delay
+func clientWithRetry(server *httptest.Server, opts ...httpkit.RetryOption) *http.Client {
+	opts = append(opts, httpkit.WithBackoff(func(int) time.Duration { return 0 }))
+	return &http.Client{Transport: httpkit.NewRetryTransport(server.Client().Transport, opts...)}
+}
+
+func assertStatus(t *testing.T, resp *http.Response, err error, expected int) {
+	t.Helper()
+
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, expected, resp.StatusCode)
+}
@@ -0,0 +1,162 @@
+package httpkit
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Security header defaults, suited to a JSON API that is never framed or rendered as a page
+const (
+	DefaultFrameOptions          = "DENY"
+	DefaultReferrerPolicy        = "no-referrer"
+	DefaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+)
+
+// Security header names
+const (
+	frameOptionsHeader          = "X-Frame-Options"
+	referrerPolicyHeader        = "Referrer-Policy"
+	contentSecurityPolicyHeader = "Content-Security-Policy"
+	strictTransportHeader       = "Strict-Transport-Security"
+)
+
+// SecurityOption configures the security headers middleware
+type SecurityOption func(*securityConfig)
+
+type securityConfig struct {
+	frameOptions          string
+	referrerPolicy        string
+	contentSecurityPolicy string
+	hstsMaxAge            time.Duration
+	hstsSubdomains        bool
+}
+
+// WithFrameOptions sets the X-Frame-Options value; empty omits the header
+func WithFrameOptions(value string) SecurityOption {
+	return func(c *securityConfig) { c.frameOptions = value }
+}
+
+// WithReferrerPolicy sets the Referrer-Policy value; empty omits the header
+func WithReferrerPolicy(value string) SecurityOption {
+	return func(c *securityConfig) { c.referrerPolicy = value }
+}
+
+// WithContentSecurityPolicy sets the Content-Security-Policy value; empty omits the header
+func WithContentSecurityPolicy(value string) SecurityOption {
+	return func(c *securityConfig) { c.contentSecurityPolicy = value }
+}
+
+// WithHSTS adds Strict-Transport-Security with the given max-age to responses served
+// over TLS, directly or behind a proxy reporting X-Forwarded-Proto: https.
+// A maxAge below one second leaves HSTS off, which is the default.
+func WithHSTS(maxAge time.Duration, includeSubdomains bool) SecurityOption {
+	return func(c *securityConfig) {
+		c.hstsMaxAge, c.hstsSubdomains = maxAge, includeSubdomains
+	}
+}
+
+// SecurityHeaders creates middleware adding X-Frame-Options, Referrer-Policy,
+// Content-Security-Policy and optionally HSTS to every response. Headers are added
+// when the response is written and only if not set, so handler values take precedence.
+func SecurityHeaders(opts ...SecurityOption) func(http.Handler) http.Handler {
+	cfg := securityConfig{
+		frameOptions:          DefaultFrameOptions,
+		referrerPolicy:        DefaultReferrerPolicy,
+		contentSecurityPolicy: DefaultContentSecurityPolicy,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	headers := cfg.headers()
+	httpsHeaders := headers
+	if hsts := cfg.hsts(); hsts != nil {
+		httpsHeaders = append(slices.Clone(headers), *hsts)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &securityHeadersWriter{ResponseWriter: w, headers: headers}
+			if isHTTPS(r) {
+				sw.headers = httpsHeaders
+			}
+			next.ServeHTTP(sw, r)
+			// A handler that wrote nothing still gets the headers on the implicit 200
+			sw.apply()
+		})
+	}
+}
+
+// securityHeader is a header added by the middleware
+type securityHeader struct {
+	key   string
+	value []string
+}
+
+// headers returns the configured non-empty headers
+func (c securityConfig) headers() []securityHeader {
+	var headers []securityHeader
+	for _, h := range []struct{ key, value string }{
+		{frameOptionsHeader, c.frameOptions},
+		{referrerPolicyHeader, c.referrerPolicy},
+		{contentSecurityPolicyHeader, c.contentSecurityPolicy},
+	} {
+		if h.value != "" {
+			headers = append(headers, securityHeader{key: h.key, value: []string{h.value}})
+		}
+	}
+	return headers
+}
+
+// hsts returns the Strict-Transport-Security header, or nil when HSTS is off
+func (c securityConfig) hsts() *securityHeader {
+	seconds := int64(c.hstsMaxAge / time.Second)
+	if seconds <= 0 {
+		return nil
+	}
+
+	value := fmt.Sprintf("max-age=%d", seconds)
+	if c.hstsSubdomains {
+		value += "; includeSubDomains"
+	}
+	return &securityHeader{key: strictTransportHeader, value: []string{value}}
+}
+
+// isHTTPS reports whether the request reached us, or the proxy in front of us, over TLS
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// securityHeadersWriter adds the security headers right before the response header is sent
+type securityHeadersWriter struct {
+	http.ResponseWriter
+	headers []securityHeader
+	applied bool
+}
+
+func (w *securityHeadersWriter) WriteHeader(code int) {
+	w.apply()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *securityHeadersWriter) Write(b []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. for flushing
+func (w *securityHeadersWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// apply adds each header not already set by the handler, once per response
+func (w *securityHeadersWriter) apply() {
+	if w.applied {
+		return
+	}
+	w.applied = true
+	for _, h := range w.headers {
+		addHeaderIfNotSet(w.ResponseWriter, h.key, h.value)
+	}
+}
@@ -0,0 +1,107 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it adds the default headers to responses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.SecurityHeaders()(httpkit.JSON(map[string]string{"status": "ok"}))
+
+		// Act
+		w := serve(handler, httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.DefaultFrameOptions, w.Header().Get("X-Frame-Options"))
+		assert.Equal(t, httpkit.DefaultReferrerPolicy, w.Header().Get("Referrer-Policy"))
+		assert.Equal(t, httpkit.DefaultContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+		assert.Empty(t, w.Header().Get("Strict-Transport-Security"), "HSTS should be off by default")
+	})
+
+	t.Run("it adds the headers when the handler writes nothing", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.SecurityHeaders()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		// Act
+		w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// Assert
+		assert.Equal(t, httpkit.DefaultFrameOptions, w.Header().Get("X-Frame-Options"))
+	})
+
+	t.Run("it keeps values set by the handler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.SecurityHeaders()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		// Act
+		w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// Assert
+		assert.Equal(t, []string{"SAMEORIGIN"}, w.Header().Values("X-Frame-Options"))
+		assert.Equal(t, []string{"default-src 'self'"}, w.Header().Values("Content-Security-Policy"))
+		assert.Equal(t, httpkit.DefaultReferrerPolicy, w.Header().Get("Referrer-Policy"))
+	})
+
+	t.Run("it omits headers configured as empty", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.SecurityHeaders(
+			httpkit.WithFrameOptions(""),
+			httpkit.WithReferrerPolicy("strict-origin"),
+		)(okHandler())
+
+		// Act
+		w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// Assert
+		assert.NotContains(t, w.Header(), "X-Frame-Options")
+		assert.Equal(t, "strict-origin", w.Header().Get("Referrer-Policy"))
+	})
+
+	t.Run("it adds HSTS only behind TLS", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		handler := httpkit.SecurityHeaders(httpkit.WithHSTS(365*24*time.Hour, true))(okHandler())
+		proxied := httptest.NewRequest(http.MethodGet, "/", nil)
+		proxied.Header.Set("X-Forwarded-Proto", "https")
+
+		// Act
+		plain := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		direct := serve(handler, httptest.NewRequest(http.MethodGet, "https://example.com/", nil))
+		behindProxy := serve(handler, proxied)
+
+		// Assert
+		assert.Empty(t, plain.Header().Get("Strict-Transport-Security"))
+		assert.Equal(t, "max-age=31536000; includeSubDomains", direct.Header().Get("Strict-Transport-Security"))
+		assert.Equal(t, "max-age=31536000; includeSubDomains", behindProxy.Header().Get("Strict-Transport-Security"))
+	})
+}
+
+func serve(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
@@ -0,0 +1,54 @@
+package httpkit
+
+import (
+	"net/http"
+	"time"
+)
+
+// Server timeout defaults, chosen to bound slow clients (slowloris) without
+// cutting off legitimate requests
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+)
+
+// ServerOption configures an http.Server built by NewServer
+type ServerOption func(*http.Server)
+
+// WithReadHeaderTimeout bounds the time allowed to read request headers
+func WithReadHeaderTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.ReadHeaderTimeout = d }
+}
+
+// WithReadTimeout bounds the time allowed to read the entire request, including the body
+func WithReadTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.ReadTimeout = d }
+}
+
+// WithWriteTimeout bounds the time from the end of the request headers to the end of the response
+func WithWriteTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.WriteTimeout = d }
+}
+
+// WithIdleTimeout bounds how long a keep-alive connection may wait for the next request
+func WithIdleTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.IdleTimeout = d }
+}
+
+// NewServer creates an http.Server with the default timeouts, followed by any options
+func NewServer(addr string, handler http.Handler, opts ...ServerOption) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		ReadTimeout:       DefaultReadTimeout,
+		WriteTimeout:      DefaultWriteTimeout,
+		IdleTimeout:       DefaultIdleTimeout,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
@@ -0,0 +1,51 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it applies secure default timeouts", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		server := httpkit.NewServer("localhost:8080", http.NotFoundHandler())
+
+		// Assert
+		assert.Equal(t, "localhost:8080", server.Addr)
+		assertServerTimeouts(t, server, httpkit.DefaultReadHeaderTimeout, httpkit.DefaultReadTimeout,
+			httpkit.DefaultWriteTimeout, httpkit.DefaultIdleTimeout)
+	})
+
+	t.Run("it applies the configured timeouts", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		server := httpkit.NewServer("localhost:8080", http.NotFoundHandler(),
+			httpkit.WithReadHeaderTimeout(1*time.Second),
+			httpkit.WithReadTimeout(2*time.Second),
+			httpkit.WithWriteTimeout(3*time.Second),
+			httpkit.WithIdleTimeout(4*time.Second),
+		)
+
+		// Assert
+		assertServerTimeouts(t, server, 1*time.Second, 2*time.Second, 3*time.Second, 4*time.Second)
+	})
+}
+
+func assertServerTimeouts(t *testing.T, server *http.Server, readHeader, read, write, idle time.Duration) {
+	t.Helper()
+
+	assert.Equal(t, readHeader, server.ReadHeaderTimeout)
+	assert.Equal(t, read, server.ReadTimeout)
+	assert.Equal(t, write, server.WriteTimeout)
+	assert.Equal(t, idle, server.IdleTimeout)
+}
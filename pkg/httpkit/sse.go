@@ -0,0 +1,81 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EventStreamContentType is the media type of Server-Sent Events streams
+const EventStreamContentType = "text/event-stream"
+
+var (
+	eventStreamContentType = []string{EventStreamContentType}
+	noCache                = []string{"no-cache"}
+)
+
+// SSEStream writes Server-Sent Events, flushing each one to the client as it is sent.
+// Like NDJSONStream, headers and the 200 status go out on Start or with the first event.
+type SSEStream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+}
+
+// NewSSEStream creates a stream writing to w. Middleware wrapping w must implement
+// Unwrap() http.ResponseWriter so events can be flushed.
+func NewSSEStream(w http.ResponseWriter) *SSEStream {
+	return &SSEStream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+}
+
+// Start sends the response headers and 200 status and lifts the server write timeout,
+// which would otherwise cut a long-lived stream. It fails when w cannot be flushed.
+func (s *SSEStream) Start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	addHeaderIfNotSet(s.w, contentTypeHeader, eventStreamContentType)
+	addHeaderIfNotSet(s.w, "Cache-Control", noCache)
+	_ = s.rc.SetWriteDeadline(time.Time{}) // Unsupported writers simply keep their deadline
+	s.w.WriteHeader(http.StatusOK)
+	return s.rc.Flush()
+}
+
+// Send writes data as JSON in a single event and flushes it. Empty event and id omit
+// their fields, so the client dispatches a plain "message" and keeps its last event ID.
+func (s *SSEStream) Send(event, id string, data any) error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrJSONEncode, err)
+	}
+
+	var sb strings.Builder
+	if event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", event)
+	}
+	if id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", id)
+	}
+	fmt.Fprintf(&sb, "data: %s\n\n", payload)
+
+	if _, err := s.w.Write([]byte(sb.String())); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// Started reports whether the response headers have been sent
+func (s *SSEStream) Started() bool {
+	return s.started
+}
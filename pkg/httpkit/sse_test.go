@@ -0,0 +1,80 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+func TestSSEStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it writes each event as flushed event, id and data fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewSSEStream(w)
+
+		// Act
+		err := stream.Send("latest", "42", map[string]int{"id": 42})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.EventStreamContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "event: latest\nid: 42\ndata: {\"id\":42}\n\n", w.Body.String())
+		assert.True(t, w.Flushed)
+	})
+
+	t.Run("it omits empty event and id fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewSSEStream(w)
+
+		// Act
+		err := stream.Send("", "", []int{1, 2})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "data: [1,2]\n\n", w.Body.String())
+	})
+
+	t.Run("it sends the headers on start", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		w := httptest.NewRecorder()
+		stream := httpkit.NewSSEStream(w)
+
+		// Act
+		err := stream.Start()
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, stream.Started())
+		assert.True(t, w.Flushed)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("it fails when the writer cannot flush", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		stream := httpkit.NewSSEStream(struct{ http.ResponseWriter }{httptest.NewRecorder()})
+
+		// Act
+		err := stream.Send("", "1", 1)
+
+		// Assert
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+	})
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
 )
@@ -10,9 +11,11 @@ const BritishTimeFormat = "02.01.2006 15:04:05"
 // Config represents logger configuration from environment/config
 // LogLevel is a string like "debug", "info", "error";
 // LogHumanFriendly toggles between text (true) and JSON (false).
+// Output defaults to stdout when nil.
 type Config struct {
 	LogLevel         string
 	LogHumanFriendly bool
+	Output           io.Writer
 }
 
 // ParseLevel converts a string to slog.Level, defaulting to Info on error.
@@ -40,11 +43,16 @@ func NewFromConfig(cfg Config) *slog.Logger {
 		},
 	}
 
+	out := cfg.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
 	var handler slog.Handler
 	if cfg.LogHumanFriendly {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(out, opts)
 	} else {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(out, opts)
 	}
 	return slog.New(handler)
 }
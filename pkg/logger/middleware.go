@@ -3,16 +3,58 @@ package logger
 import (
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/screwyprof/delegator/pkg/httpkit"
 )
 
+// MiddlewareOption configures the logging middleware
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	sampleBytes  int
+	levelFor     func(status int) slog.Level
+	excludePaths []string
+}
+
+// WithLevelMapper customizes the log level chosen for each response status code.
+// The default logs 5xx at Error and everything else at Info.
+func WithLevelMapper(mapper func(status int) slog.Level) MiddlewareOption {
+	return func(c *middlewareConfig) { c.levelFor = mapper }
+}
+
+// DefaultLevelMapper logs server errors (5xx) at Error and everything else at Info
+func DefaultLevelMapper(status int) slog.Level {
+	if status >= http.StatusInternalServerError {
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// WithBodySampling logs up to maxBytes of each response body at debug level.
+// The sample is taken from what the handler writes, so a compression middleware
+// placed outside (wrapping) this one still yields uncompressed samples.
+// The response sent to the client is not altered.
+func WithBodySampling(maxBytes int) MiddlewareOption {
+	return func(c *middlewareConfig) { c.sampleBytes = maxBytes }
+}
+
+// WithExcludePaths logs requests for the given paths, such as health probes, at debug
+// level so they stay out of info logs. Paths match the request path exactly. Responses
+// the level mapper logs above Info (server errors by default) keep their level, so a
+// failing probe still shows up.
+func WithExcludePaths(paths ...string) MiddlewareOption {
+	return func(c *middlewareConfig) { c.excludePaths = append(c.excludePaths, paths...) }
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code and response size
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
-	bytesOut   int
+	statusCode  int
+	bytesOut    int
+	sampleLimit int    // 0 disables body sampling
+	sample      []byte // first sampleLimit bytes of the body
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -23,17 +65,31 @@ func (rw *responseWriter) WriteHeader(code int) {
 func (rw *responseWriter) Write(b []byte) (int, error) {
 	size, err := rw.ResponseWriter.Write(b)
 	rw.bytesOut += size
+	if room := rw.sampleLimit - len(rw.sample); room > 0 {
+		rw.sample = append(rw.sample, b[:min(room, size)]...)
+	}
 	return size, err
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. for streaming flushes
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // NewMiddleware creates HTTP request logging middleware
-func NewMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+func NewMiddleware(logger *slog.Logger, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	cfg := middlewareConfig{levelFor: DefaultLevelMapper}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
 			// Ensure error tracking context exists (in case httpkit.HandlerFunc wasn't used)
 			ctx := httpkit.WithErrorTracking(r.Context())
+			ctx = httpkit.WithRouteTracking(ctx)
 			r = r.WithContext(ctx)
 
 			// Get request size - use max() to handle -1 case (unknown length)
@@ -45,19 +101,21 @@ func NewMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 				statusCode:     http.StatusOK, // Default to 200 if WriteHeader is never called
 			}
 
+			// Only pay for sampling when the sample would actually be logged
+			if cfg.sampleBytes > 0 && logger.Enabled(ctx, slog.LevelDebug) {
+				rw.sampleLimit = cfg.sampleBytes
+			}
+
 			// Serve the request
 			next.ServeHTTP(rw, r)
 
 			// Calculate duration
 			duration := time.Since(start)
 
-			// Determine log level based on status code
-			var level slog.Level
-			switch {
-			case rw.statusCode >= http.StatusInternalServerError:
-				level = slog.LevelError
-			default:
-				level = slog.LevelInfo
+			// Determine log level based on status code, demoting routine requests to excluded paths
+			level := cfg.levelFor(rw.statusCode)
+			if level <= slog.LevelInfo && slices.Contains(cfg.excludePaths, r.URL.Path) {
+				level = slog.LevelDebug
 			}
 
 			// Build base log attributes
@@ -70,6 +128,16 @@ func NewMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 				slog.Int("bytes_out", rw.bytesOut),
 			}
 
+			// Add the route template as a low-cardinality alternative to the uri
+			if route := matchedRoute(r); route != "" {
+				attrs = append(attrs, slog.String("route", route))
+			}
+
+			// Add request id for correlation with downstream logs (e.g. SQL traces)
+			if requestID := httpkit.RequestID(r.Context()); requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+
 			// Add error details if available
 			if err := httpkit.Error(r.Context()); err != nil {
 				// Extract appropriate error message for logging
@@ -78,6 +146,19 @@ func NewMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			// Log with constant message - let structured fields tell the story
 			logger.LogAttrs(r.Context(), level, "HTTP", attrs...)
+
+			if rw.sampleLimit > 0 {
+				bodyAttrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("uri", r.RequestURI),
+					slog.String("body", string(rw.sample)),
+					slog.Bool("body_truncated", rw.bytesOut > len(rw.sample)),
+				}
+				if requestID := httpkit.RequestID(r.Context()); requestID != "" {
+					bodyAttrs = append(bodyAttrs, slog.String("request_id", requestID))
+				}
+				logger.LogAttrs(r.Context(), slog.LevelDebug, "HTTP body", bodyAttrs...)
+			}
 		})
 	}
 }
@@ -89,3 +170,12 @@ func errorMessage(err error) string {
 	}
 	return err.Error() // fallback for regular errors
 }
+
+// matchedRoute returns the route pattern recorded by httpkit.HandlerFunc, falling back to
+// the request's own Pattern when the mux received this very request, e.g. for plain handlers
+func matchedRoute(r *http.Request) string {
+	if route := httpkit.Route(r.Context()); route != "" {
+		return route
+	}
+	return r.Pattern
+}
@@ -2,8 +2,10 @@ package logger_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -31,15 +33,17 @@ func (e Error) Cause() error  { return e.err }
 
 // logEntry represents a parsed log entry for testing
 type logEntry struct {
-	Level    string  `json:"level"`
-	Msg      string  `json:"msg"`
-	Method   string  `json:"method"`
-	URI      string  `json:"uri"`
-	Status   int     `json:"status"`
-	Duration float64 `json:"duration"` // slog logs duration as nanoseconds (number)
-	BytesIn  int     `json:"bytes_in"`
-	BytesOut int     `json:"bytes_out"`
-	Error    string  `json:"error,omitempty"`
+	Level     string  `json:"level"`
+	Msg       string  `json:"msg"`
+	Method    string  `json:"method"`
+	URI       string  `json:"uri"`
+	Status    int     `json:"status"`
+	Duration  float64 `json:"duration"` // slog logs duration as nanoseconds (number)
+	BytesIn   int     `json:"bytes_in"`
+	BytesOut  int     `json:"bytes_out"`
+	Error     string  `json:"error,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+	Route     string  `json:"route,omitempty"`
 }
 
 // parseLogEntry parses a single JSON log line
@@ -218,4 +222,382 @@ func TestNewMiddleware(t *testing.T) {
 		assert.Equal(t, len(reqBody), entry.BytesIn)
 		assert.Equal(t, rec.Body.Len(), entry.BytesOut)
 	})
+	t.Run("it logs the request id when present", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		middleware := httpkit.NewRequestIDMiddleware()(logger.NewMiddleware(log)(okHandler))
+		req := httptest.NewRequest(http.MethodGet, "/test/request-id", nil)
+		req.Header.Set(httpkit.RequestIDHeader, "req-789")
+		rec := httptest.NewRecorder()
+
+		// Act
+		middleware.ServeHTTP(rec, req)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "req-789", entry.RequestID)
+		assert.Equal(t, "req-789", rec.Header().Get(httpkit.RequestIDHeader))
+	})
+
+	t.Run("it logs the route template alongside the concrete uri", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /xtz/delegations/{id}", httpkit.HandlerFunc(func(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+			return httpkit.JSON(map[string]string{"id": r.PathValue("id")})
+		}))
+
+		middleware := logger.NewMiddleware(log)(copyingRequest(mux))
+		req := httptest.NewRequest(http.MethodGet, "/xtz/delegations/42?fields=amount", nil)
+
+		// Act
+		middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "GET /xtz/delegations/{id}", entry.Route)
+		assert.Equal(t, "/xtz/delegations/42?fields=amount", entry.URI)
+	})
+
+	t.Run("it falls back to the pattern of a plain handler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /xtz/delegations/{id}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		middleware := logger.NewMiddleware(log)(mux)
+		req := httptest.NewRequest(http.MethodGet, "/xtz/delegations/7", nil)
+
+		// Act
+		middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "GET /xtz/delegations/{id}", entry.Route)
+	})
+
+	t.Run("it omits the route when nothing matched", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		middleware := logger.NewMiddleware(log)(http.NewServeMux())
+		req := httptest.NewRequest(http.MethodGet, "/unknown/42", nil)
+
+		// Act
+		middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, http.StatusNotFound, entry.Status)
+		assert.Empty(t, entry.Route)
+	})
+
+	t.Run("it lets streaming handlers flush through", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		log := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		var flushErr error
+		streamHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("data: 1\n\n"))
+			flushErr = http.NewResponseController(w).Flush()
+		})
+
+		middleware := logger.NewMiddleware(log)(streamHandler)
+		rec := httptest.NewRecorder()
+
+		// Act
+		middleware.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+		// Assert
+		require.NoError(t, flushErr)
+		assert.True(t, rec.Flushed)
+	})
+}
+
+// copyingRequest passes a shallow copy of the request on, like middleware adding context values,
+// so the mux cannot report its pattern through the logger's own request
+func copyingRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(r.Context()))
+	})
+}
+
+func TestNewMiddlewareLevelMapper(t *testing.T) {
+	t.Parallel()
+
+	// warnOnClientErrors logs 4xx at Warn, 5xx at Error and everything else at Info
+	warnOnClientErrors := func(status int) slog.Level {
+		switch {
+		case status >= http.StatusInternalServerError:
+			return slog.LevelError
+		case status >= http.StatusBadRequest:
+			return slog.LevelWarn
+		default:
+			return slog.LevelInfo
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		status        int
+		expectedLevel string
+	}{
+		{name: "it logs 200 at info", status: http.StatusOK, expectedLevel: "INFO"},
+		{name: "it logs 404 at warn", status: http.StatusNotFound, expectedLevel: "WARN"},
+		{name: "it logs 500 at error", status: http.StatusInternalServerError, expectedLevel: "ERROR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var logBuffer bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+			statusHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			})
+
+			middleware := logger.NewMiddleware(log, logger.WithLevelMapper(warnOnClientErrors))(statusHandler)
+			req := httptest.NewRequest(http.MethodGet, "/test/level", nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			middleware.ServeHTTP(rec, req)
+
+			// Assert
+			entry := parseLogEntry(t, logBuffer.String())
+			assert.Equal(t, tc.expectedLevel, entry.Level)
+			assert.Equal(t, tc.status, entry.Status)
+		})
+	}
+}
+
+func TestNewMiddlewareExcludePaths(t *testing.T) {
+	t.Parallel()
+
+	serve := func(log *slog.Logger, path string, status int) {
+		statusHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})
+		middleware := logger.NewMiddleware(log, logger.WithExcludePaths("/healthz", "/readyz"))(statusHandler)
+		middleware.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	t.Run("it keeps excluded paths out of info logs", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		// Act
+		serve(log, "/healthz", http.StatusOK)
+		serve(log, "/readyz?probe=1", http.StatusOK)
+
+		// Assert
+		assert.Empty(t, logBuffer.String())
+	})
+
+	t.Run("it logs excluded paths at debug level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		// Act
+		serve(log, "/healthz", http.StatusOK)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "DEBUG", entry.Level)
+		assert.Equal(t, "/healthz", entry.URI)
+	})
+
+	t.Run("it logs other paths at info level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		// Act
+		serve(log, "/healthz/extra", http.StatusOK)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "INFO", entry.Level)
+		assert.Equal(t, "/healthz/extra", entry.URI)
+	})
+
+	t.Run("it keeps the error level of failing excluded paths", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		// Act
+		serve(log, "/readyz", http.StatusServiceUnavailable)
+
+		// Assert
+		entry := parseLogEntry(t, logBuffer.String())
+		assert.Equal(t, "ERROR", entry.Level)
+		assert.Equal(t, http.StatusServiceUnavailable, entry.Status)
+	})
+}
+
+func TestNewMiddlewareBodySampling(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"data":[{"amount":"1000000"}]}`
+
+	t.Run("it logs a truncated response body sample at debug level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		middleware := logger.NewMiddleware(log, logger.WithBodySampling(10))(bodyHandler(body))
+		req := httptest.NewRequest(http.MethodGet, "/test/sample", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		middleware.ServeHTTP(rec, req)
+
+		// Assert
+		entry := parseBodySampleEntry(t, logBuffer.String())
+		assert.Equal(t, "DEBUG", entry.Level)
+		assert.Equal(t, body[:10], entry.Body)
+		assert.True(t, entry.BodyTruncated)
+		assert.Equal(t, body, rec.Body.String(), "Sampling must not alter the response")
+	})
+
+	t.Run("it skips sampling when debug logging is disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		middleware := logger.NewMiddleware(log, logger.WithBodySampling(10))(bodyHandler(body))
+		req := httptest.NewRequest(http.MethodGet, "/test/sample", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		middleware.ServeHTTP(rec, req)
+
+		// Assert
+		assert.NotContains(t, logBuffer.String(), "HTTP body")
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("it samples uncompressed bytes behind a gzip middleware", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		middleware := gzipMiddleware(logger.NewMiddleware(log, logger.WithBodySampling(1024))(bodyHandler(body)))
+		req := httptest.NewRequest(http.MethodGet, "/test/sample", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		middleware.ServeHTTP(rec, req)
+
+		// Assert
+		entry := parseBodySampleEntry(t, logBuffer.String())
+		assert.Equal(t, body, entry.Body)
+		assert.False(t, entry.BodyTruncated)
+		assert.Equal(t, body, gunzip(t, rec.Body.Bytes()), "Client should receive the compressed body intact")
+	})
+}
+
+// bodySampleEntry represents a parsed body sample log entry
+type bodySampleEntry struct {
+	Level         string `json:"level"`
+	Msg           string `json:"msg"`
+	Body          string `json:"body"`
+	BodyTruncated bool   `json:"body_truncated"`
+}
+
+// parseBodySampleEntry finds and parses the body sample log entry
+func parseBodySampleEntry(t *testing.T, logOutput string) bodySampleEntry {
+	t.Helper()
+
+	for line := range strings.SplitSeq(strings.TrimSpace(logOutput), "\n") {
+		var entry bodySampleEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "Should parse log entry as JSON")
+		if entry.Msg == "HTTP body" {
+			return entry
+		}
+	}
+
+	require.Fail(t, "Expected a body sample log entry")
+	return bodySampleEntry{}
+}
+
+func bodyHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+// gzipResponseWriter compresses everything written through it
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	zw *gzip.Writer
+}
+
+func (w gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.zw.Write(b)
+}
+
+// gzipMiddleware is a minimal compression middleware wrapping the logging middleware
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		defer zw.Close()
+		next.ServeHTTP(gzipResponseWriter{ResponseWriter: w, zw: zw}, r)
+	})
+}
+
+func gunzip(t *testing.T, compressed []byte) string {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	return string(plain)
 }
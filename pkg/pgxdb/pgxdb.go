@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,13 +20,36 @@ var (
 	ErrDatabaseConnection      = errors.New("failed to connect to database")
 )
 
-// NewConnection creates a new pgx database connection pool with production-optimized settings
-func NewConnection(ctx context.Context, connectionString string) (*pgxpool.Pool, error) {
+// Option tunes the pool configuration
+type Option func(*pgxpool.Config)
+
+// WithMinConns sets the minimum number of connections kept open
+func WithMinConns(n int32) Option {
+	return func(c *pgxpool.Config) { c.MinConns = n }
+}
+
+// WithMaxConns sets the maximum number of connections in the pool
+func WithMaxConns(n int32) Option {
+	return func(c *pgxpool.Config) { c.MaxConns = n }
+}
+
+// WithTracer installs a pgx.QueryTracer on every connection of the pool
+func WithTracer(tracer pgx.QueryTracer) Option {
+	return func(c *pgxpool.Config) { c.ConnConfig.Tracer = tracer }
+}
+
+// NewConfig parses the connection string and applies production-optimized settings,
+// followed by any options
+func NewConfig(connectionString string, opts ...Option) (*pgxpool.Config, error) {
 	config, err := pgxpool.ParseConfig(connectionString)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidConnectionString, err)
 	}
 
+	if err := validateSocketHosts(config); err != nil {
+		return nil, err
+	}
+
 	// Optimize connection pool settings based on production best practices
 	// See: https://blog.cloudflare.com/how-hyperdrive-speeds-up-database-access/
 	// See: https://koho.dev/understanding-go-and-databases-at-scale-connection-pooling-f301e56fa73
@@ -40,6 +66,20 @@ func NewConnection(ctx context.Context, connectionString string) (*pgxpool.Pool,
 	// Acquisition settings
 	config.ConnConfig.ConnectTimeout = 10 * time.Second // Don't wait too long for new connections
 
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config, nil
+}
+
+// NewConnection creates a new pgx database connection pool with production-optimized settings
+func NewConnection(ctx context.Context, connectionString string, opts ...Option) (*pgxpool.Pool, error) {
+	config, err := NewConfig(connectionString, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrConnectionPoolCreation, err)
@@ -52,3 +92,20 @@ func NewConnection(ctx context.Context, connectionString string) (*pgxpool.Pool,
 
 	return pool, nil
 }
+
+// validateSocketHosts rejects hosts that look like socket paths but are not absolute.
+// pgx only dials a UNIX socket for absolute paths (postgres:///db?host=/var/run/postgresql);
+// anything else would be silently treated as a TCP hostname and fail much later.
+func validateSocketHosts(config *pgxpool.Config) error {
+	hosts := []string{config.ConnConfig.Host}
+	for _, fallback := range config.ConnConfig.Fallbacks {
+		hosts = append(hosts, fallback.Host)
+	}
+
+	for _, host := range hosts {
+		if strings.ContainsRune(host, '/') && !filepath.IsAbs(host) {
+			return fmt.Errorf("%w: socket directory %q must be an absolute path", ErrInvalidConnectionString, host)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build acceptance
+
+package pgxdb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/pgxdb"
+	"github.com/screwyprof/delegator/pkg/pgxdb/testcfg"
+)
+
+func TestNewConnectionOverSocket(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	connStr := testcfg.New().SocketURL
+	config, err := pgxdb.NewConfig(connStr)
+	require.NoError(t, err)
+
+	if _, err := os.Stat(config.ConnConfig.Host); err != nil {
+		t.Skipf("socket directory %s is not available: %v", config.ConnConfig.Host, err)
+	}
+
+	// Act
+	pool, err := pgxdb.NewConnection(t.Context(), connStr, pgxdb.WithMinConns(0), pgxdb.WithMaxConns(1))
+
+	// Assert
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var one int
+	require.NoError(t, pool.QueryRow(t.Context(), "SELECT 1").Scan(&one))
+	assert.Equal(t, 1, one)
+}
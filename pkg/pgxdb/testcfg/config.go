@@ -0,0 +1,22 @@
+package testcfg
+
+import (
+	"github.com/caarlos0/env/v11"
+)
+
+// Config holds test-specific configuration for pgxdb acceptance tests
+type Config struct {
+	SocketURL string `env:"PGXDB_TEST_SOCKET_URL" envDefault:"postgres://delegator:delegator@/delegator?host=/var/run/postgresql&sslmode=disable"`
+}
+
+// parseConfig wraps env.Parse to return (Config, error) for use with env.Must
+func parseConfig() (Config, error) {
+	var cfg Config
+	err := env.Parse(&cfg)
+	return cfg, err
+}
+
+// New loads test configuration from environment variables
+func New() Config {
+	return env.Must(parseConfig())
+}
@@ -9,39 +9,111 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
 )
 
 // Internal API constants
 const (
 	defaultLimit     = 100
 	delegationsPath  = "/v1/operations/delegations"
+	blocksPath       = "/v1/blocks"
+	headPath         = "/v1/head"
 	queryParamLimit  = "limit"
 	queryParamSelect = "select"
 	// Select only necessary fields to minimize payload
 	defaultSelectFields = "id,timestamp,amount,sender,level"
+	blockSelectFields   = "level,hash"
+	// Maximum number of levels per blocks request to keep URLs reasonably short
+	maxLevelsPerRequest = 500
 )
 
+// retryableStatusCodes are the gateway failures WithRetry retries by default
+var retryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
 // Sentinel errors for different failure modes
 var (
 	ErrMalformedRequest      = errors.New("malformed request")
 	ErrHTTPRequestFailed     = errors.New("http request failed")
 	ErrUnexpectedStatus      = errors.New("unexpected HTTP status code")
 	ErrMalformedResponseBody = errors.New("malformed response body")
+	ErrRateLimited           = errors.New("rate limited")
 )
 
+// RateLimitError is returned when TzKT answers 429 Too Many Requests. It matches both
+// ErrRateLimited and ErrUnexpectedStatus, so callers can check either with errors.Is.
+type RateLimitError struct {
+	RetryAfter time.Duration // Wait requested by the Retry-After header; 0 when absent
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v: %d: %v, retry after %v", ErrUnexpectedStatus, http.StatusTooManyRequests, ErrRateLimited, e.RetryAfter)
+	}
+	return fmt.Sprintf("%v: %d: %v", ErrUnexpectedStatus, http.StatusTooManyRequests, ErrRateLimited)
+}
+
+// Is reports whether target is ErrRateLimited or ErrUnexpectedStatus
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited || target == ErrUnexpectedStatus
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithStrictDecoding rejects responses containing fields unknown to Delegation, which
+// models every field TzKT returns for the selected ones. Useful for catching API
+// contract changes; the default is lenient.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *Client) { c.strictDecoding = strict }
+}
+
+// WithRetry retries transient failures (transport errors and 5xx gateway statuses)
+// using httpkit.RetryTransport. The caller's http.Client is not modified.
+// 429 Too Many Requests is not retried unless opts say so: it is returned as a
+// *RateLimitError, so a polling caller can wait for its Retry-After rather than
+// stay blocked in the transport.
+func WithRetry(opts ...httpkit.RetryOption) Option {
+	opts = append([]httpkit.RetryOption{httpkit.WithRetryableStatusCodes(retryableStatusCodes...)}, opts...)
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = httpkit.NewRetryTransport(httpClient.Transport, opts...)
+		c.httpClient = &httpClient
+	}
+}
+
+// WithRequestObserver calls observe with the full URL of every request the client sends
+// and the response status, or 0 when no response arrived. Meant for seeing exactly what
+// was queried without a proxy; observe runs synchronously and must be safe for concurrent use.
+func WithRequestObserver(observe func(url string, status int)) Option {
+	return func(c *Client) { c.observeRequest = observe }
+}
+
 // Client represents a Tzkt API client
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
+	httpClient     *http.Client
+	baseURL        string
+	strictDecoding bool
+	observeRequest func(url string, status int)
 }
 
-// NewClient creates a new Tzkt API client with explicit dependencies
-func NewClient(httpClient *http.Client, baseURL string) *Client {
-	return &Client{
+// NewClient creates a new Tzkt API client with explicit dependencies.
+// Trailing slashes are trimmed from baseURL so request paths never contain "//".
+func NewClient(httpClient *http.Client, baseURL string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: httpClient,
-		baseURL:    baseURL,
+		baseURL:    strings.TrimRight(baseURL, "/"),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // DelegationsRequest represents parameters for getting delegations with filtering
@@ -49,7 +121,12 @@ type DelegationsRequest struct {
 	Limit         uint64
 	Offset        uint64     // offset pagination
 	IDGreaterThan *int64     // id.gt filter
+	IDLessThan    *int64     // id.lt filter
 	TimestampGE   *time.Time // timestamp.ge filter
+	LevelGE       *int64     // level.ge filter: delegations at or above this block level
+	LevelLE       *int64     // level.le filter: delegations at or below this block level
+	SortDescByID  bool       // sort.desc=id (newest first); default is ascending by id
+	Senders       []string   // sender.in filter: delegations from any of these addresses
 }
 
 // Delegation represents a Tezos delegation from Tzkt API
@@ -57,10 +134,21 @@ type Delegation struct {
 	ID        int64     `json:"id"`
 	Level     int64     `json:"level"`
 	Timestamp time.Time `json:"timestamp"`
-	Sender    struct {
-		Address string `json:"address"`
-	} `json:"sender"`
-	Amount int64 `json:"amount"`
+	Sender    Account   `json:"sender"`
+	Amount    int64     `json:"amount"`
+}
+
+// Account is an account reference as embedded in TzKT operations.
+// Alias is only present for accounts TzKT knows a name for.
+type Account struct {
+	Alias   string `json:"alias,omitempty"`
+	Address string `json:"address"`
+}
+
+// Block represents the subset of a Tezos block used for delegation enrichment
+type Block struct {
+	Level int64  `json:"level"`
+	Hash  string `json:"hash"`
 }
 
 // GetDelegations retrieves delegations from the Tzkt API with filtering support
@@ -72,9 +160,9 @@ func (c *Client) GetDelegations(ctx context.Context, req DelegationsRequest) ([]
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrHTTPRequestFailed, err)
+		return nil, err
 	}
 	defer func() {
 		// Drain response body to enable connection reuse
@@ -83,17 +171,146 @@ func (c *Client) GetDelegations(ctx context.Context, req DelegationsRequest) ([]
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+		return nil, statusError(resp)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	if c.strictDecoding {
+		decoder.DisallowUnknownFields()
 	}
 
 	var delegations []Delegation
-	if err := json.NewDecoder(resp.Body).Decode(&delegations); err != nil {
+	if err := decoder.Decode(&delegations); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrMalformedResponseBody, err)
 	}
 
 	return delegations, nil
 }
 
+// GetBlockHashes looks up block hashes for the given levels in bulk.
+// Returns a map from level to block hash; duplicate levels are queried once.
+func (c *Client) GetBlockHashes(ctx context.Context, levels []int64) (map[int64]string, error) {
+	unique := uniqueLevels(levels)
+	hashes := make(map[int64]string, len(unique))
+
+	for start := 0; start < len(unique); start += maxLevelsPerRequest {
+		end := min(start+maxLevelsPerRequest, len(unique))
+
+		blocks, err := c.getBlocks(ctx, unique[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range blocks {
+			hashes[b.Level] = b.Hash
+		}
+	}
+
+	return hashes, nil
+}
+
+// GetHeadLevel returns the level of the current head block, i.e. the chain tip as seen
+// by the API. The head carries many fields that are not needed here, so it is always
+// decoded leniently, even with WithStrictDecoding.
+func (c *Client) GetHeadLevel(ctx context.Context) (int64, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+headPath, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrMalformedRequest, err)
+	}
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// Drain response body to enable connection reuse
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(resp)
+	}
+
+	var head struct {
+		Level *int64 `json:"level"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&head); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrMalformedResponseBody, err)
+	}
+	if head.Level == nil {
+		return 0, fmt.Errorf("%w: head without level", ErrMalformedResponseBody)
+	}
+
+	return *head.Level, nil
+}
+
+// getBlocks fetches blocks for a single chunk of levels
+func (c *Client) getBlocks(ctx context.Context, levels []int64) ([]Block, error) {
+	fullURL := c.buildBlocksURL(levels)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedRequest, err)
+	}
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Drain response body to enable connection reuse
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	if c.strictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+
+	var blocks []Block
+	if err := decoder.Decode(&blocks); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedResponseBody, err)
+	}
+
+	return blocks, nil
+}
+
+// do sends req and reports it to the request observer, if installed
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if c.observeRequest != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.observeRequest(req.URL.String(), status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHTTPRequestFailed, err)
+	}
+	return resp, nil
+}
+
+// uniqueLevels returns levels without duplicates, preserving first-seen order
+func uniqueLevels(levels []int64) []int64 {
+	seen := make(map[int64]struct{}, len(levels))
+	unique := make([]int64, 0, len(levels))
+	for _, level := range levels {
+		if _, ok := seen[level]; ok {
+			continue
+		}
+		seen[level] = struct{}{}
+		unique = append(unique, level)
+	}
+	return unique
+}
+
 func effectiveLimit(limit uint64) uint64 {
 	if limit == 0 {
 		return defaultLimit
@@ -102,6 +319,12 @@ func effectiveLimit(limit uint64) uint64 {
 }
 
 func (c *Client) buildRequest(ctx context.Context, req DelegationsRequest) (*http.Request, error) {
+	for i, sender := range req.Senders {
+		if sender == "" {
+			return nil, fmt.Errorf("%w: empty sender at index %d", ErrMalformedRequest, i)
+		}
+	}
+
 	fullURL := c.buildDelegationsURL(req)
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
@@ -121,9 +344,26 @@ func (c *Client) buildDelegationsURL(req DelegationsRequest) string {
 	if req.IDGreaterThan != nil {
 		params.Set("id.gt", strconv.FormatInt(*req.IDGreaterThan, 10))
 	}
+	if req.IDLessThan != nil {
+		params.Set("id.lt", strconv.FormatInt(*req.IDLessThan, 10))
+	}
 	if req.TimestampGE != nil {
 		params.Set("timestamp.ge", req.TimestampGE.Format(time.RFC3339))
 	}
+	if req.LevelGE != nil {
+		params.Set("level.ge", strconv.FormatInt(*req.LevelGE, 10))
+	}
+	if req.LevelLE != nil {
+		params.Set("level.le", strconv.FormatInt(*req.LevelLE, 10))
+	}
+	if len(req.Senders) > 0 {
+		params.Set("sender.in", strings.Join(req.Senders, ","))
+	}
+
+	// Add sorting if specified
+	if req.SortDescByID {
+		params.Set("sort.desc", "id")
+	}
 
 	// Add offset pagination if specified
 	if req.Offset > 0 {
@@ -132,3 +372,26 @@ func (c *Client) buildDelegationsURL(req DelegationsRequest) string {
 
 	return fmt.Sprintf("%s%s?%s", c.baseURL, delegationsPath, params.Encode())
 }
+
+func (c *Client) buildBlocksURL(levels []int64) string {
+	formatted := make([]string, len(levels))
+	for i, level := range levels {
+		formatted[i] = strconv.FormatInt(level, 10)
+	}
+
+	params := url.Values{}
+	params.Set(queryParamLimit, strconv.Itoa(len(levels)))
+	params.Set(queryParamSelect, blockSelectFields)
+	params.Set("level.in", strings.Join(formatted, ","))
+
+	return fmt.Sprintf("%s%s?%s", c.baseURL, blocksPath, params.Encode())
+}
+
+// statusError describes a non-200 response, as a *RateLimitError for 429
+func statusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := httpkit.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+	return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+}
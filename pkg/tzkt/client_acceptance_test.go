@@ -53,3 +53,24 @@ func TestTzktClientRealAPI(t *testing.T) {
 			i, delegation.ID, delegation.Level, delegation.Amount, delegation.Sender.Address, timestampStr)
 	}
 }
+
+func TestTzktClientRealAPIStrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	testCfg := testcfg.New()
+
+	// Arrange
+	client := tzkt.NewClient(&http.Client{
+		Timeout: testCfg.HTTPTimeout,
+	}, testCfg.BaseURL, tzkt.WithStrictDecoding(true))
+
+	// Act
+	delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+		Limit:  testCfg.Limit,
+		Offset: testCfg.Offset,
+	})
+
+	// Assert
+	require.NoError(t, err, "Real payloads should only contain fields known to Delegation")
+	assert.Len(t, delegations, int(testCfg.Limit))
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/screwyprof/delegator/pkg/httpkit"
 	"github.com/screwyprof/delegator/pkg/tzkt"
 )
 
@@ -89,6 +93,31 @@ func TestTzktClientGetDelegations(t *testing.T) {
 		assertAPIError(t, err, tzkt.ErrUnexpectedStatus, delegations)
 	})
 
+	t.Run("it reports a rate limit with the requested Retry-After", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 10,
+		})
+
+		// Assert
+		assertAPIError(t, err, tzkt.ErrRateLimited, delegations)
+		require.ErrorIs(t, err, tzkt.ErrUnexpectedStatus, "A rate limit is still an unexpected status")
+		var rateLimited *tzkt.RateLimitError
+		require.ErrorAs(t, err, &rateLimited)
+		assert.Equal(t, 30*time.Second, rateLimited.RetryAfter)
+	})
+
 	t.Run("it handles malformed response body", func(t *testing.T) {
 		t.Parallel()
 
@@ -285,6 +314,364 @@ func TestTzktClientGetDelegations(t *testing.T) {
 		// Assert
 		assertTimestampFilterPresent(t, err, requestURL, timestampFilter)
 	})
+
+	t.Run("it includes level.ge and level.le parameters for a block range", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+		fromLevel, toLevel := int64(5_000_000), int64(5_100_000)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:   10,
+			LevelGE: &fromLevel,
+			LevelLE: &toLevel,
+		})
+
+		// Assert
+		assertURLContainsParam(t, err, requestURL, "level.ge=5000000")
+		assertURLContainsParam(t, err, requestURL, "level.le=5100000")
+	})
+
+	t.Run("it includes only the level bound that is set", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+		fromLevel := int64(0)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:   10,
+			LevelGE: &fromLevel,
+		})
+
+		// Assert
+		assertURLContainsParam(t, err, requestURL, "level.ge=0")
+		assertURLExcludesParam(t, err, requestURL, "level.le")
+	})
+
+	t.Run("it excludes level parameters by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 10,
+		})
+
+		// Assert
+		assertURLExcludesParam(t, err, requestURL, "level.ge")
+		assertURLExcludesParam(t, err, requestURL, "level.le")
+	})
+
+	t.Run("it includes id.lt and sort.desc parameters for newest-first walks", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+		idFilter := int64(500)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:        10,
+			IDLessThan:   &idFilter,
+			SortDescByID: true,
+		})
+
+		// Assert
+		assertURLContainsParam(t, err, requestURL, "id.lt=500")
+		assertURLContainsParam(t, err, requestURL, "sort.desc=id")
+	})
+
+	t.Run("it excludes id.lt and sort.desc parameters by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 10,
+		})
+
+		// Assert
+		assertURLExcludesParam(t, err, requestURL, "id.lt")
+		assertURLExcludesParam(t, err, requestURL, "sort.desc")
+	})
+
+	t.Run("it includes sender.in parameter for multiple senders", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:   10,
+			Senders: []string{"tz1First", "tz1Second"},
+		})
+
+		// Assert
+		assertURLContainsParam(t, err, requestURL, "sender.in=tz1First%2Ctz1Second")
+	})
+
+	t.Run("it excludes sender.in parameter when no senders are given", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:   10,
+			Senders: []string{},
+		})
+
+		// Assert
+		assertURLExcludesParam(t, err, requestURL, "sender.in")
+	})
+
+	t.Run("it rejects an empty sender", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:   10,
+			Senders: []string{"tz1First", ""},
+		})
+
+		// Assert
+		assertAPIError(t, err, tzkt.ErrMalformedRequest, delegations)
+		assert.Empty(t, requestURL, "No request should be sent")
+	})
+}
+
+func TestTzktClientDecoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it ignores unknown fields by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithExtraField(t)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 1,
+		})
+
+		// Assert
+		assertDelegationsReceived(t, err, delegations, 1)
+	})
+
+	t.Run("it accepts a TzKT delegations payload in strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithFixture(t, "delegations.json")
+		defer server.Close()
+
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithStrictDecoding(true))
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 2,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, delegations, 2)
+		assert.Equal(t, tzkt.Account{Address: "tz1Wit2PqodvPeuRRhdQXmkrtU8e8bRYZecd"}, delegations[0].Sender)
+		assert.Equal(t, tzkt.Account{Alias: "Tezos Foundation", Address: "tz3RDC3Jdn4j15J7bBHZd29EUee9gVB1CxD9"}, delegations[1].Sender)
+		assert.Equal(t, int64(144), delegations[1].Level)
+	})
+
+	t.Run("it rejects unknown fields in strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithExtraField(t)
+		defer server.Close()
+
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithStrictDecoding(true))
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 1,
+		})
+
+		// Assert
+		assertAPIError(t, err, tzkt.ErrMalformedResponseBody, delegations)
+	})
+}
+
+func TestTzktClientGetBlockHashes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it maps levels to block hashes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithBlocks(t, `[{"level":100,"hash":"BLockA"},{"level":101,"hash":"BLockB"}]`)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		hashes, err := client.GetBlockHashes(t.Context(), []int64{100, 101})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]string{100: "BLockA", 101: "BLockB"}, hashes)
+	})
+
+	t.Run("it queries each level only once", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetBlockHashes(t.Context(), []int64{100, 101, 100})
+
+		// Assert
+		assertURLContainsParam(t, err, requestURL, "/v1/blocks?")
+		assertURLContainsParam(t, err, requestURL, "level.in=100%2C101&")
+		assertURLContainsParam(t, err, requestURL, "limit=2")
+	})
+
+	t.Run("it handles unexpected status code", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithStatusCode(t, http.StatusInternalServerError)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		hashes, err := client.GetBlockHashes(t.Context(), []int64{100})
+
+		// Assert
+		assert.ErrorIs(t, err, tzkt.ErrUnexpectedStatus)
+		assert.Nil(t, hashes)
+	})
+}
+
+func TestTzktClientGetHeadLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the level of the head block", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithHead(t, `{"chain":"mainnet","level":8123456,"hash":"BLockHead","synced":true}`)
+		defer server.Close()
+
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithStrictDecoding(true))
+
+		// Act
+		level, err := client.GetHeadLevel(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(8123456), level)
+	})
+
+	t.Run("it queries the head endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, _ = client.GetHeadLevel(t.Context())
+
+		// Assert
+		assert.Equal(t, "/v1/head", requestURL)
+	})
+
+	t.Run("it rejects a head without level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithHead(t, `{"chain":"mainnet"}`)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetHeadLevel(t.Context())
+
+		// Assert
+		assert.ErrorIs(t, err, tzkt.ErrMalformedResponseBody)
+	})
+
+	t.Run("it handles unexpected status code", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerWithStatusCode(t, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		_, err := client.GetHeadLevel(t.Context())
+
+		// Assert
+		assert.ErrorIs(t, err, tzkt.ErrUnexpectedStatus)
+	})
 }
 
 func createTestDelegation(id int64, level int64, timestamp, address string, amount int64) tzkt.Delegation {
@@ -293,9 +680,7 @@ func createTestDelegation(id int64, level int64, timestamp, address string, amou
 		ID:        id,
 		Level:     level,
 		Timestamp: parsedTime,
-		Sender: struct {
-			Address string `json:"address"`
-		}{
+		Sender: tzkt.Account{
 			Address: address,
 		},
 		Amount: amount,
@@ -336,6 +721,43 @@ func newServerWithInvalidJSON(t *testing.T) *httptest.Server {
 	}))
 }
 
+func newServerWithExtraField(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[{"id":1,"level":100,"timestamp":"2024-01-01T00:00:00Z","sender":{"address":"tz1abc"},"amount":1000,"newField":"surprise"}]`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+}
+
+func newServerWithBlocks(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err, "Failed to write response")
+	}))
+}
+
+func newServerWithHead(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	return newServerWithBlocks(t, body)
+}
+
+// newServerWithFixture serves a response body recorded in testdata
+func newServerWithFixture(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err, "Failed to read fixture")
+
+	return newServerWithBlocks(t, string(body))
+}
+
 func newURLTrackingServer(t *testing.T, urlCapture *string) *httptest.Server {
 	t.Helper()
 
@@ -349,6 +771,19 @@ func newURLTrackingServer(t *testing.T, urlCapture *string) *httptest.Server {
 	}))
 }
 
+func newPathTrackingServer(t *testing.T, pathCapture *string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*pathCapture = r.URL.Path
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+}
+
 func newClientWithServer(server *httptest.Server) *tzkt.Client {
 	return tzkt.NewClient(server.Client(), server.URL)
 }
@@ -422,3 +857,199 @@ func assertTimestampFilterPresent(t *testing.T, err error, requestURL string, ex
 	assert.Contains(t, requestURL, "timestamp.ge=", "Expected backfill filtering from timestamp")
 	assert.Contains(t, requestURL, "2024-12-01T10", "Expected backfill from time %v", expectedTime)
 }
+
+func TestTzktClientBaseURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		suffix string
+	}{
+		{name: "it builds paths from a base URL without a trailing slash", suffix: ""},
+		{name: "it trims a trailing slash from the base URL", suffix: "/"},
+		{name: "it trims repeated trailing slashes from the base URL", suffix: "///"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var requestPath string
+			server := newPathTrackingServer(t, &requestPath)
+			defer server.Close()
+
+			client := tzkt.NewClient(server.Client(), server.URL+tt.suffix)
+
+			// Act
+			_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{Limit: 1})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "/v1/operations/delegations", requestPath)
+			assert.NotContains(t, requestPath, "//")
+		})
+	}
+}
+
+func TestTzktClientRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it retries transient failures when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerFailingOnce(t)
+		defer server.Close()
+
+		client := tzkt.NewClient(server.Client(), server.URL,
+			tzkt.WithRetry(httpkit.WithMaxRetries(1), httpkit.WithBackoff(noBackoff)))
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 1,
+		})
+
+		// Assert
+		assertDelegationsReceived(t, err, delegations, 1)
+	})
+
+	t.Run("it leaves rate-limited responses to the caller", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var calls atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := tzkt.NewClient(server.Client(), server.URL,
+			tzkt.WithRetry(httpkit.WithMaxRetries(3), httpkit.WithBackoff(noBackoff)))
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{Limit: 1})
+
+		// Assert
+		var rateLimited *tzkt.RateLimitError
+		require.ErrorAs(t, err, &rateLimited)
+		assert.Equal(t, 30*time.Second, rateLimited.RetryAfter)
+		assert.Equal(t, int64(1), calls.Load(), "The transport should not retry a 429 itself")
+	})
+
+	t.Run("it does not retry by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := newServerFailingOnce(t)
+		defer server.Close()
+
+		client := newClientWithServer(server)
+
+		// Act
+		delegations, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit: 1,
+		})
+
+		// Assert
+		assertAPIError(t, err, tzkt.ErrUnexpectedStatus, delegations)
+	})
+}
+
+func TestTzktClientRequestObserver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it observes the final URL with encoded parameters and the status", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requestURL string
+		server := newURLTrackingServer(t, &requestURL)
+		defer server.Close()
+
+		var observed []string
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithRequestObserver(func(url string, status int) {
+			observed = append(observed, fmt.Sprintf("%d %s", status, url))
+		}))
+		since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		_, err := client.GetDelegations(t.Context(), tzkt.DelegationsRequest{
+			Limit:       10,
+			TimestampGE: &since,
+			Senders:     []string{"tz1First", "tz1Second"},
+		})
+
+		// Assert
+		expectedURL := server.URL + "/v1/operations/delegations?limit=10&select=id%2Ctimestamp%2Camount%2Csender%2Clevel" +
+			"&sender.in=tz1First%2Ctz1Second&timestamp.ge=2024-01-01T00%3A00%3A00Z"
+		require.NoError(t, err)
+		assert.Equal(t, []string{"200 " + expectedURL}, observed)
+		assert.Equal(t, expectedURL, server.URL+requestURL, "The observed URL should be the one the server received")
+	})
+
+	t.Run("it observes the status of a failed response", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		var observedStatus int
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithRequestObserver(func(_ string, status int) {
+			observedStatus = status
+		}))
+
+		// Act
+		_, err := client.GetHeadLevel(t.Context())
+
+		// Assert
+		require.ErrorIs(t, err, tzkt.ErrUnexpectedStatus)
+		assert.Equal(t, http.StatusInternalServerError, observedStatus)
+	})
+
+	t.Run("it observes status 0 when no response arrives", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		var observedURL string
+		observedStatus := -1
+		client := tzkt.NewClient(server.Client(), server.URL, tzkt.WithRequestObserver(func(url string, status int) {
+			observedURL, observedStatus = url, status
+		}))
+
+		// Act
+		_, err := client.GetBlockHashes(t.Context(), []int64{100})
+
+		// Assert
+		require.ErrorIs(t, err, tzkt.ErrHTTPRequestFailed)
+		assert.Equal(t, server.URL+"/v1/blocks?level.in=100&limit=1&select=level%2Chash", observedURL)
+		assert.Zero(t, observedStatus)
+	})
+}
+
+// newServerFailingOnce answers 503 to the first request and succeeds afterwards
+func newServerFailingOnce(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var calls atomic.Int64
+	success := successHandler(t, []tzkt.Delegation{
+		createTestDelegation(1, 100, "2018-06-30T19:30:27Z", "tz1Wit2PqodvPeuRRhdQXmkrtU8e8bRYZecd", 1000),
+	})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		success(w, r)
+	}))
+}
+
+func noBackoff(int) time.Duration { return 0 }
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/scraper/config"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	t.Run("it loads values from the file and defaults the rest", func(t *testing.T) {
+		// Arrange
+		unsetenv(t, "SCRAPER_CHUNK_SIZE", "SCRAPER_POLL_INTERVAL", "SCRAPER_TZKT_API_URL")
+		path := writeConfig(t, `{"SCRAPER_CHUNK_SIZE": 500, "SCRAPER_POLL_INTERVAL": "1m"}`)
+
+		// Act
+		cfg, err := config.LoadFromFile(path)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, uint64(500), cfg.ChunkSize)
+		assert.Equal(t, time.Minute, cfg.PollInterval)
+		assert.Equal(t, "https://api.tzkt.io", cfg.TzktAPIURL)
+	})
+
+	t.Run("it lets environment variables override the file", func(t *testing.T) {
+		// Arrange
+		unsetenv(t, "SCRAPER_POLL_INTERVAL")
+		path := writeConfig(t, `{"SCRAPER_CHUNK_SIZE": 500, "SCRAPER_POLL_INTERVAL": "1m"}`)
+		t.Setenv("SCRAPER_CHUNK_SIZE", "42")
+
+		// Act
+		cfg, err := config.LoadFromFile(path)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), cfg.ChunkSize)
+		assert.Equal(t, time.Minute, cfg.PollInterval)
+	})
+
+	t.Run("it rejects values of the wrong type", func(t *testing.T) {
+		// Arrange
+		unsetenv(t, "SCRAPER_CHUNK_SIZE")
+		path := writeConfig(t, `{"SCRAPER_CHUNK_SIZE": "lots"}`)
+
+		// Act
+		_, err := config.LoadFromFile(path)
+
+		// Assert
+		require.Error(t, err)
+	})
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "scraper.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// unsetenv clears variables a developer shell may export (e.g. from env.demo) for the
+// rest of the test
+func unsetenv(t *testing.T, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+}
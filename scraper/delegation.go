@@ -10,4 +10,5 @@ type Delegation struct {
 	Timestamp time.Time
 	Delegator string
 	Amount    int64
+	BlockHash string // Optional; set only when block hash enrichment is enabled
 }
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/clock"
+)
+
+// InstrumentedStoreOption configures the InstrumentedStore
+type InstrumentedStoreOption func(*InstrumentedStore)
+
+// WithWriteClock injects the clock that timestamps successful writes (e.g., for testing)
+func WithWriteClock(c Clock) InstrumentedStoreOption {
+	return func(s *InstrumentedStore) { s.clock = c }
+}
+
+// InstrumentedStore decorates a Store, recording when SaveBatch last succeeded and how
+// many delegations it has saved so far, so health checks can tell whether the scraper
+// is still writing without subscribing to its events. Failed saves are not counted.
+// The optional store capabilities of the wrapped store stay available through Unwrap.
+type InstrumentedStore struct {
+	Store
+
+	clock Clock
+
+	mu          sync.Mutex
+	lastWriteAt time.Time
+	written     int64
+}
+
+// NewInstrumentedStore wraps store with write instrumentation
+func NewInstrumentedStore(store Store, opts ...InstrumentedStoreOption) *InstrumentedStore {
+	s := &InstrumentedStore{
+		Store: store,
+		clock: clock.SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SaveBatch saves through the wrapped store and records the write when it succeeds
+func (s *InstrumentedStore) SaveBatch(ctx context.Context, delegations []Delegation) (int64, error) {
+	checkpointID, err := s.Store.SaveBatch(ctx, delegations)
+	if err != nil {
+		return checkpointID, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastWriteAt = s.clock.Now()
+	s.written += int64(len(delegations))
+	return checkpointID, nil
+}
+
+// LastWriteAt returns when SaveBatch last succeeded, or the zero time before the first write
+func (s *InstrumentedStore) LastWriteAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastWriteAt
+}
+
+// TotalWritten returns how many delegations successful SaveBatch calls have saved
+func (s *InstrumentedStore) TotalWritten() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.written
+}
+
+// Unwrap returns the wrapped store
+func (s *InstrumentedStore) Unwrap() Store {
+	return s.Store
+}
+
+// storeAs finds capability T on store or, through Unwrap, on any store it decorates
+func storeAs[T any](store Store) (T, bool) {
+	for {
+		if capability, ok := store.(T); ok {
+			return capability, true
+		}
+		wrapper, ok := store.(interface{ Unwrap() Store })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		store = wrapper.Unwrap()
+	}
+}
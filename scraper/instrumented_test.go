@@ -0,0 +1,113 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/scraper"
+)
+
+func TestInstrumentedStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it records the time and rows of each successful save", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		clock := &steppingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		store := scraper.NewInstrumentedStore(createTestStore(0, nil), scraper.WithWriteClock(clock))
+
+		// Act
+		_, firstErr := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2, 3))
+		clock.now = clock.now.Add(time.Minute)
+		checkpointID, secondErr := store.SaveBatch(t.Context(), delegationsWithIDs(4, 5))
+
+		// Assert
+		require.NoError(t, firstErr)
+		require.NoError(t, secondErr)
+		assert.Equal(t, int64(5), checkpointID, "The wrapped store's checkpoint should be returned")
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC), store.LastWriteAt())
+		assert.Equal(t, int64(5), store.TotalWritten())
+	})
+
+	t.Run("it reports no writes before the first save", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := scraper.NewInstrumentedStore(createTestStore(0, nil))
+
+		// Act
+		lastWriteAt, written := store.LastWriteAt(), store.TotalWritten()
+
+		// Assert
+		assert.True(t, lastWriteAt.IsZero())
+		assert.Zero(t, written)
+	})
+
+	t.Run("it does not record failed saves", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		saveErr := errors.New("connection refused")
+		clock := &steppingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		store := scraper.NewInstrumentedStore(createTestStore(0, func(context.Context, []scraper.Delegation) error {
+			return saveErr
+		}), scraper.WithWriteClock(clock))
+
+		// Act
+		_, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+
+		// Assert
+		require.ErrorIs(t, err, saveErr)
+		assert.True(t, store.LastWriteAt().IsZero())
+		assert.Zero(t, store.TotalWritten())
+	})
+
+	t.Run("it keeps the wrapped store's optional capabilities available to the service", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5)
+		defer server.Close()
+
+		gapStore := &gapReportingStore{mockStore: createTestStore(0, nil), gaps: []scraper.Gap{
+			{AfterID: 1, BeforeID: 5, StoredUpTo: 1},
+		}}
+		store := scraper.NewInstrumentedStore(gapStore)
+		svc := scraperForRepair(server, store)
+
+		// Act
+		result, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, scraper.GapRepairResult{Gaps: 1, Windows: 1, Recovered: 3}, result)
+		assert.Equal(t, int64(3), store.TotalWritten())
+	})
+}
+
+// steppingClock returns the time it is set to; its timers never fire
+type steppingClock struct {
+	now time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	return c.now
+}
+
+func (c *steppingClock) After(time.Duration) <-chan time.Time {
+	return nil
+}
+
+func delegationsWithIDs(ids ...int64) []scraper.Delegation {
+	delegations := make([]scraper.Delegation, len(ids))
+	for i, id := range ids {
+		delegations[i] = scraper.Delegation{ID: id}
+	}
+	return delegations
+}
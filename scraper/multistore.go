@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSecondaryStoreFailed reports that a secondary store could not save a batch
+var ErrSecondaryStoreFailed = errors.New("secondary store save failed")
+
+// MultiStorePolicy decides what a failing secondary store does to a MultiStore save
+type MultiStorePolicy int
+
+const (
+	// FailFast writes the secondary stores before the primary and fails the whole save,
+	// without touching the primary, at the first secondary store error (the default)
+	FailFast MultiStorePolicy = iota
+	// BestEffort writes the primary first, then every secondary store, and reports their
+	// errors without failing; a batch a secondary missed is not retried
+	BestEffort
+)
+
+// MultiStoreOption configures the MultiStore
+type MultiStoreOption func(*MultiStore)
+
+// WithMultiStorePolicy sets how secondary store failures are handled
+func WithMultiStorePolicy(policy MultiStorePolicy) MultiStoreOption {
+	return func(s *MultiStore) { s.policy = policy }
+}
+
+// WithPartialFailureHandler receives the joined secondary store errors of a best-effort
+// save, e.g. to log them. Without a handler they are dropped.
+func WithPartialFailureHandler(handle func(err error)) MultiStoreOption {
+	return func(s *MultiStore) { s.onPartialFailure = handle }
+}
+
+// MultiStore fans each batch out to a primary store and any number of secondary sinks.
+// The primary is the source of truth: a failure there always fails the save, and its
+// checkpoint is the one reported. With FailFast the primary is only written once every
+// secondary has the batch, so a failed save leaves the checkpoint where it was and the
+// batch is fetched and saved again; secondaries must therefore ignore delegations they
+// already have, as Store implementations do. The optional store capabilities of the
+// primary stay available through Unwrap.
+type MultiStore struct {
+	primary     Store
+	secondaries []Store
+
+	policy           MultiStorePolicy
+	onPartialFailure func(err error)
+}
+
+// NewMultiStore writes to primary and then to each of secondaries in order
+func NewMultiStore(primary Store, secondaries []Store, opts ...MultiStoreOption) *MultiStore {
+	s := &MultiStore{
+		primary:     primary,
+		secondaries: secondaries,
+		policy:      FailFast,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LastProcessedID returns the checkpoint of the primary store
+func (s *MultiStore) LastProcessedID(ctx context.Context) (int64, error) {
+	return s.primary.LastProcessedID(ctx)
+}
+
+// SaveBatch saves to the primary and the secondary stores in the order the policy sets
+func (s *MultiStore) SaveBatch(ctx context.Context, delegations []Delegation) (int64, error) {
+	if s.policy == FailFast {
+		for i, secondary := range s.secondaries {
+			if _, err := secondary.SaveBatch(ctx, delegations); err != nil {
+				return 0, fmt.Errorf("%w: secondary store %d: %w", ErrSecondaryStoreFailed, i, err)
+			}
+		}
+		return s.primary.SaveBatch(ctx, delegations)
+	}
+
+	checkpointID, err := s.primary.SaveBatch(ctx, delegations)
+	if err != nil {
+		return checkpointID, err
+	}
+
+	var errs []error
+	for i, secondary := range s.secondaries {
+		if _, err := secondary.SaveBatch(ctx, delegations); err != nil {
+			errs = append(errs, fmt.Errorf("secondary store %d: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 && s.onPartialFailure != nil {
+		s.onPartialFailure(fmt.Errorf("%w: %w", ErrSecondaryStoreFailed, errors.Join(errs...)))
+	}
+	return checkpointID, nil
+}
+
+// Unwrap returns the primary store
+func (s *MultiStore) Unwrap() Store {
+	return s.primary
+}
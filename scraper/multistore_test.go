@@ -0,0 +1,118 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/scraper"
+)
+
+func TestMultiStore(t *testing.T) {
+	t.Parallel()
+
+	failingStore := func(err error) *mockStore {
+		return createTestStore(0, func(context.Context, []scraper.Delegation) error { return err })
+	}
+
+	t.Run("it fails the save on a failing secondary by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		sinkErr := errors.New("sink unavailable")
+		primary, last := createTestStore(0, nil), createTestStore(0, nil)
+		store := scraper.NewMultiStore(primary, []scraper.Store{failingStore(sinkErr), last})
+
+		// Act
+		_, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+
+		// Assert
+		require.ErrorIs(t, err, scraper.ErrSecondaryStoreFailed)
+		require.ErrorIs(t, err, sinkErr)
+		assert.Zero(t, primary.lastID, "The primary checkpoint should not pass a batch a secondary missed")
+		assert.Zero(t, last.lastID, "Secondaries after the failing one should not be written")
+	})
+
+	t.Run("it delivers a failed batch to the secondary when the save is retried", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var down atomic.Bool
+		down.Store(true)
+		var delivered []int64
+		secondary := createTestStore(0, func(_ context.Context, batch []scraper.Delegation) error {
+			if down.Load() {
+				return errors.New("sink unavailable")
+			}
+			for _, d := range batch {
+				delivered = append(delivered, d.ID)
+			}
+			return nil
+		})
+		primary := createTestStore(0, nil)
+		store := scraper.NewMultiStore(primary, []scraper.Store{secondary})
+
+		_, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+		require.ErrorIs(t, err, scraper.ErrSecondaryStoreFailed)
+		require.Zero(t, primary.lastID, "The checkpoint should stay before the failed batch")
+		down.Store(false)
+
+		// Act: the unchanged checkpoint makes the next sync fetch the same batch again
+		checkpointID, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), checkpointID)
+		assert.Equal(t, []int64{1, 2}, delivered, "The secondary should get the batch it missed")
+	})
+
+	t.Run("it reports every failing secondary without failing the save in best-effort mode", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		firstErr, secondErr := errors.New("sink unavailable"), errors.New("disk full")
+		primary, healthy := createTestStore(0, nil), createTestStore(0, nil)
+
+		var reported []error
+		store := scraper.NewMultiStore(primary,
+			[]scraper.Store{failingStore(firstErr), healthy, failingStore(secondErr)},
+			scraper.WithMultiStorePolicy(scraper.BestEffort),
+			scraper.WithPartialFailureHandler(func(err error) { reported = append(reported, err) }),
+		)
+
+		// Act
+		checkpointID, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), checkpointID, "The primary's checkpoint should be returned")
+		assert.Equal(t, int64(2), healthy.lastID, "Healthy secondaries should still be written")
+
+		require.Len(t, reported, 1, "Errors of one save should be reported together")
+		assert.ErrorIs(t, reported[0], scraper.ErrSecondaryStoreFailed)
+		assert.ErrorIs(t, reported[0], firstErr)
+		assert.ErrorIs(t, reported[0], secondErr)
+	})
+
+	t.Run("it fails the save when the primary fails whatever the policy", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		primaryErr := errors.New("connection refused")
+		secondary := createTestStore(0, nil)
+		store := scraper.NewMultiStore(failingStore(primaryErr), []scraper.Store{secondary},
+			scraper.WithMultiStorePolicy(scraper.BestEffort))
+
+		// Act
+		_, err := store.SaveBatch(t.Context(), delegationsWithIDs(1, 2))
+
+		// Assert
+		require.ErrorIs(t, err, primaryErr)
+		assert.NotErrorIs(t, err, scraper.ErrSecondaryStoreFailed)
+		assert.Zero(t, secondary.lastID, "Secondaries should not get a batch the primary rejected")
+	})
+}
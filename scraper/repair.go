@@ -0,0 +1,137 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+)
+
+// GapRepairResult summarises a RepairGaps run
+type GapRepairResult struct {
+	Gaps      int // Holes found between stored IDs
+	Windows   int // Merged ID ranges re-fetched from the API
+	Recovered int // Delegations re-fetched and saved into the gaps
+}
+
+// RepairGaps finds holes in the stored delegation IDs and re-fetches the missing
+// delegations from the API. The store must implement GapFinder.
+//
+// TzKT delegation IDs are operation IDs shared with other operation types, so nearly
+// every pair of adjacent stored IDs is a gap and most recover nothing. Fetching each gap
+// on its own would cost an API call per stored delegation, so gaps are streamed from the
+// store and merged into windows spanning at most a chunk of stored delegations; each
+// window is fetched in chunks and only delegations falling inside one of its gaps are
+// saved. A full repair therefore costs about as many API calls as re-scraping the stored
+// range once. Run it as a one-off repair while the service is not running, not on every start.
+//
+// On error, the result counts the windows repaired so far.
+func (s *Service) RepairGaps(ctx context.Context) (GapRepairResult, error) {
+	var result GapRepairResult
+
+	finder, ok := storeAs[GapFinder](s.store)
+	if !ok {
+		return result, fmt.Errorf("%w: store does not implement GapFinder", ErrGapRepair)
+	}
+
+	var window []Gap
+	repairWindow := func() error {
+		if len(window) == 0 {
+			return nil
+		}
+		recovered, err := s.fillWindow(ctx, window)
+		result.Windows++
+		result.Recovered += recovered
+		if err != nil {
+			return fmt.Errorf("window %d..%d: %w", window[0].AfterID, window[len(window)-1].BeforeID, err)
+		}
+		window = window[:0]
+		return nil
+	}
+
+	err := finder.FindIDGaps(ctx, func(gap Gap) error {
+		result.Gaps++
+		if len(window) > 0 && uint64(gap.StoredUpTo-window[0].StoredUpTo) >= s.chunkSize {
+			if err := repairWindow(); err != nil {
+				return err
+			}
+		}
+		window = append(window, gap)
+		return nil
+	})
+	if err == nil {
+		err = repairWindow()
+	}
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrGapRepair, err)
+	}
+
+	return result, nil
+}
+
+// fillWindow fetches delegations between the first and last gap of window and saves
+// the ones falling strictly inside one of its gaps
+func (s *Service) fillWindow(ctx context.Context, window []Gap) (int, error) {
+	cursor := window[0].AfterID
+	end := window[len(window)-1].BeforeID
+	recovered := 0
+
+	for {
+		// respect cancellation
+		select {
+		case <-ctx.Done():
+			return recovered, ctx.Err()
+		default:
+		}
+
+		batch, err := s.api.GetDelegations(ctx, tzkt.DelegationsRequest{
+			Limit:         s.chunkSize,
+			IDGreaterThan: &cursor,
+			IDLessThan:    &end,
+		})
+		if err != nil {
+			return recovered, fmt.Errorf("%w: %w", ErrAPIRequestFailed, err)
+		}
+		if len(batch) == 0 {
+			return recovered, nil
+		}
+		cursor = batch[len(batch)-1].ID
+
+		missing := slices.DeleteFunc(batch, func(d tzkt.Delegation) bool { return !inGap(window, d.ID) })
+		if len(missing) == 0 {
+			continue
+		}
+
+		domainDelegations, err := convertTzktDelegations(missing)
+		if err != nil {
+			return recovered, err
+		}
+
+		if err := s.enrichWithBlockHashes(ctx, domainDelegations); err != nil {
+			return recovered, err
+		}
+
+		// The store keeps the higher forward checkpoint, so filling an old gap never rewinds it
+		if _, err := s.saveBatch(ctx, domainDelegations); err != nil {
+			return recovered, fmt.Errorf("%w: %w", ErrSaveBatchFailed, err)
+		}
+
+		recovered += len(domainDelegations)
+	}
+}
+
+// inGap reports whether id lies strictly inside one of the gaps, which are ordered by ID
+func inGap(gaps []Gap, id int64) bool {
+	i, _ := slices.BinarySearchFunc(gaps, id, func(gap Gap, id int64) int {
+		switch {
+		case gap.BeforeID <= id:
+			return -1
+		case gap.AfterID >= id:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return i < len(gaps) && gaps[i].AfterID < id && id < gaps[i].BeforeID
+}
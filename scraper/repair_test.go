@@ -0,0 +1,152 @@
+package scraper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+	"github.com/screwyprof/delegator/scraper"
+)
+
+func TestServiceRepairGaps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it re-fetches and saves the delegations missing from each gap", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		gapStore := &gapReportingStore{mockStore: store, gaps: []scraper.Gap{
+			{AfterID: 2, BeforeID: 5, StoredUpTo: 2},
+			{AfterID: 6, BeforeID: 10, StoredUpTo: 4},
+		}}
+		svc := scraperForRepair(server, gapStore)
+
+		// Act
+		result, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, scraper.GapRepairResult{Gaps: 2, Windows: 2, Recovered: 5}, result)
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{3, 4, 7, 8, 9})
+	})
+
+	t.Run("it merges gaps into chunk-sized windows and saves only what falls inside them", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5, 6, 7, 8, 9)
+		defer server.Close()
+
+		var requests atomic.Int32
+		client := tzkt.NewClient(http.DefaultClient, server.URL,
+			tzkt.WithRequestObserver(func(string, int) { requests.Add(1) }))
+
+		// Odd IDs are stored, so every pair of them is a gap
+		savedBatchesCh, store := storeCapturingBatches()
+		gapStore := &gapReportingStore{mockStore: store, gaps: []scraper.Gap{
+			{AfterID: 1, BeforeID: 3, StoredUpTo: 1},
+			{AfterID: 3, BeforeID: 5, StoredUpTo: 2},
+			{AfterID: 5, BeforeID: 7, StoredUpTo: 3},
+			{AfterID: 7, BeforeID: 9, StoredUpTo: 4},
+		}}
+		svc := scraper.NewService(client, gapStore, scraper.WithChunkSize(4))
+
+		// Act
+		result, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, scraper.GapRepairResult{Gaps: 4, Windows: 1, Recovered: 4}, result)
+		assert.Equal(t, int32(3), requests.Load(), "The window should be fetched in chunks, not once per gap")
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{2, 4, 6, 8})
+	})
+
+	t.Run("it keeps the forward checkpoint while filling older gaps", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(3, 4)
+		defer server.Close()
+
+		store := storeWithCheckpoint(10)
+		gapStore := &gapReportingStore{mockStore: store, gaps: []scraper.Gap{{AfterID: 2, BeforeID: 5, StoredUpTo: 2}}}
+		svc := scraperForRepair(server, gapStore)
+
+		// Act
+		_, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assertCheckpointAdvancedTo(t, store, 10)
+	})
+
+	t.Run("it fails when the store cannot find gaps", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations()
+		defer server.Close()
+
+		svc := scraperForRepair(server, storeWithCheckpoint(0))
+
+		// Act
+		_, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		assert.ErrorIs(t, err, scraper.ErrGapRepair)
+	})
+
+	t.Run("it reports API failures with the window being repaired", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiReturningError()
+		defer server.Close()
+
+		gapStore := &gapReportingStore{mockStore: storeWithCheckpoint(0), gaps: []scraper.Gap{
+			{AfterID: 2, BeforeID: 5, StoredUpTo: 2},
+			{AfterID: 6, BeforeID: 10, StoredUpTo: 4},
+		}}
+		svc := scraperForRepair(server, gapStore)
+
+		// Act
+		result, err := svc.RepairGaps(t.Context())
+
+		// Assert
+		assert.ErrorIs(t, err, scraper.ErrGapRepair)
+		assert.ErrorIs(t, err, scraper.ErrAPIRequestFailed)
+		assert.ErrorContains(t, err, "window 2..5")
+		assert.Equal(t, scraper.GapRepairResult{Gaps: 2, Windows: 1}, result, "Repair should stop at the failing window")
+	})
+}
+
+// scraperForRepair uses a small chunk size so gaps are filled over several batches
+func scraperForRepair(server *httptest.Server, store scraper.Store) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store, scraper.WithChunkSize(2))
+}
+
+// gapReportingStore adds GapFinder to mockStore
+type gapReportingStore struct {
+	*mockStore
+	gaps []scraper.Gap
+}
+
+func (g *gapReportingStore) FindIDGaps(_ context.Context, yield func(scraper.Gap) error) error {
+	for _, gap := range g.gaps {
+		if err := yield(gap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
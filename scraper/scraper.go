@@ -14,6 +14,35 @@ var (
 	ErrAPIRequestFailed    = errors.New("API request failed")
 	ErrSaveBatchFailed     = errors.New("save batch failed")
 	ErrInvalidTimestamp    = errors.New("invalid delegation timestamp")
+	ErrBackfillTimeout     = errors.New("backfill timed out")
+	ErrEnrichmentFailed    = errors.New("block hash enrichment failed")
+	ErrConversionFailed    = errors.New("delegation conversion failed")
+	ErrNegativeAmount      = errors.New("negative amount")
+	ErrCheckpointReconcile = errors.New("checkpoint reconciliation failed")
+	ErrGapRepair           = errors.New("gap repair failed")
+	ErrHeadLevel           = errors.New("head level retrieval failed")
+
+	// ErrTimestampCheckpointUnsupported is returned when WithTimestampCheckpoint is
+	// enabled but the store does not implement TimestampCheckpointer
+	ErrTimestampCheckpointUnsupported = errors.New("store does not implement TimestampCheckpointer")
+
+	// ErrSkipExistingUnsupported is returned when WithSkipExistingBatches is enabled but
+	// the store does not implement both ExistenceChecker and CheckpointReconciler
+	ErrSkipExistingUnsupported = errors.New("store does not implement ExistenceChecker and CheckpointReconciler")
+
+	// ErrConfirmationLagUnsupported is returned when WithConfirmationLag is set but
+	// the API client does not implement HeadLevelClient
+	ErrConfirmationLagUnsupported = errors.New("client does not implement HeadLevelClient")
+
+	// ErrStartupProbeFailed is returned when WithStartupProbe is enabled and the API
+	// cannot be reached before backfill starts
+	ErrStartupProbeFailed = errors.New("startup probe failed")
+
+	// ErrMaxPollCyclesReached is the PollingShutdown reason once WithMaxPollCycles is exhausted
+	ErrMaxPollCyclesReached = errors.New("max poll cycles reached")
+
+	// ErrTooManyPollErrors is the PollingShutdown reason once WithMaxConsecutivePollErrors is exceeded
+	ErrTooManyPollErrors = errors.New("too many consecutive poll errors")
 )
 
 // Default configuration values
@@ -28,18 +57,79 @@ type Client interface {
 	GetDelegations(ctx context.Context, req tzkt.DelegationsRequest) ([]tzkt.Delegation, error)
 }
 
+// BlockHashClient looks up block hashes by level in bulk
+type BlockHashClient interface {
+	GetBlockHashes(ctx context.Context, levels []int64) (map[int64]string, error)
+}
+
+// HeadLevelClient is implemented by API clients that can report the current chain head
+type HeadLevelClient interface {
+	// GetHeadLevel returns the level of the newest block known to the API
+	GetHeadLevel(ctx context.Context) (int64, error)
+}
+
 // Store provides persistence operations for delegation data
 type Store interface {
 	// LastProcessedID returns the ID of the last processed delegation
 	LastProcessedID(ctx context.Context) (int64, error)
-	// SaveBatch saves a batch of delegations. It update the last checkpoint.
-	SaveBatch(ctx context.Context, delegations []Delegation) error
+	// SaveBatch saves a batch of delegations sorted by ID ascending.
+	// It advances the last checkpoint to the highest ID, never moving it backwards,
+	// and returns the checkpoint as persisted.
+	SaveBatch(ctx context.Context, delegations []Delegation) (int64, error)
+}
+
+// CheckpointReconciler is implemented by stores that can repair a checkpoint
+// lagging behind the stored delegations (e.g. after manual edits)
+type CheckpointReconciler interface {
+	// MaxDelegationID returns the highest stored delegation ID, or 0 when there are none
+	MaxDelegationID(ctx context.Context) (int64, error)
+	// AdvanceCheckpoint moves the checkpoint up to id; it never moves it backwards
+	AdvanceCheckpoint(ctx context.Context, id int64) error
+}
+
+// TimestampCheckpointer is implemented by stores that can checkpoint by delegation
+// timestamp instead of ID
+type TimestampCheckpointer interface {
+	// LastProcessedTimestamp returns the timestamp of the newest stored delegation,
+	// or the zero time when there are none
+	LastProcessedTimestamp(ctx context.Context) (time.Time, error)
+}
+
+// ExistenceChecker is implemented by stores that can tell how many delegations are already stored
+type ExistenceChecker interface {
+	// CountExisting returns how many of the given delegation IDs are stored
+	CountExisting(ctx context.Context, ids []int64) (int64, error)
+}
+
+// GapFinder is implemented by stores that can list holes in the stored ID sequence
+type GapFinder interface {
+	// FindIDGaps streams every pair of consecutive stored IDs that are not adjacent to
+	// yield, ordered by ID, and stops with the first error yield returns
+	FindIDGaps(ctx context.Context, yield func(Gap) error) error
+}
+
+// Gap is a range of delegation IDs missing between two stored delegations
+type Gap struct {
+	AfterID    int64 // Last stored ID before the gap
+	BeforeID   int64 // First stored ID after the gap
+	StoredUpTo int64 // Number of stored delegations with IDs up to and including AfterID
 }
 
+// Direction controls the order in which backfill walks delegation IDs
+type Direction int
+
+const (
+	// AscendingByID walks oldest-first from the checkpoint (default)
+	AscendingByID Direction = iota
+	// DescendingByID walks newest-first down to the starting checkpoint
+	DescendingByID
+)
+
 // SyncResult contains the results of a sync batch operation
 type SyncResult struct {
 	Count        int
 	CheckpointID int64
+	FloorID      int64 // Descending backfill only: lowest ID saved so far
 }
 
 // Clock abstracts time for production and testing
@@ -51,7 +141,24 @@ type Clock interface {
 
 // Event represents a service lifecycle event
 // ------------------------------------------
-type Event any
+// Event is sealed: only the event types below implement it, so arbitrary values
+// cannot be sent on the events channel and type switches over it can be checked
+// for exhaustiveness (gochecksumtype).
+//
+//sumtype:decl
+type Event interface {
+	isEvent()
+}
+
+func (BackfillDone) isEvent()          {}
+func (BackfillStarted) isEvent()       {}
+func (BackfillSyncCompleted) isEvent() {}
+func (BackfillError) isEvent()         {}
+func (PollingSyncCompleted) isEvent()  {}
+func (PollingStarted) isEvent()        {}
+func (PollingShutdown) isEvent()       {}
+func (PollingError) isEvent()          {}
+func (Heartbeat) isEvent()             {}
 
 type BackfillDone struct {
 	TotalProcessed int64
@@ -61,11 +168,13 @@ type BackfillDone struct {
 type BackfillStarted struct {
 	StartedAt    time.Time
 	CheckpointID int64
+	ColdStart    bool // No checkpoint and nothing stored yet, as opposed to resuming
 }
 
 type BackfillSyncCompleted struct {
 	Fetched      int
 	CheckpointID int64
+	FloorID      int64 // Descending backfill only: lowest ID saved so far
 	ChunkSize    uint64
 }
 
@@ -77,6 +186,7 @@ type PollingSyncCompleted struct {
 	Fetched      int
 	CheckpointID int64
 	ChunkSize    uint64
+	NextInterval time.Duration // Wait before the next poll, grown by WithIdleBackoff while idle; 0 during a catch-up burst
 }
 
 type PollingStarted struct {
@@ -90,3 +200,9 @@ type PollingShutdown struct {
 type PollingError struct {
 	Err error
 }
+
+// Heartbeat is emitted every WithHeartbeat interval, whatever the scraper is doing
+type Heartbeat struct {
+	At           time.Time
+	CheckpointID int64 // Highest checkpoint reported by a sync so far
+}
@@ -4,10 +4,16 @@ package scraper_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,43 +66,362 @@ func TestScraperAcceptanceBehavior(t *testing.T) {
 		service := createTestService(t, client, store, cfg)
 
 		// Act
-		backfillResult := runScraperUntilPollingStarts(t, service, testCfg.ShutdownTimeout)
+		backfillResult := runScraperForOnePollCycle(t, service, testCfg.ShutdownTimeout)
 
 		// Assert
 		assertBackfillSucceeded(t, backfillResult)
 		// Test assertions use separate connection for isolation
 		assertDataWasStoredCorrectly(t, testDB)(backfillResult, testCfg.Checkpoint)
 	})
+
+	t.Run("it stores block hashes when enrichment is enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testCfg := testcfg.New()
+		cfg := createProdCfg(testCfg)
+
+		testDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", uint64(testCfg.Checkpoint))
+		defer testDB.Close()
+
+		productionDB, err := pgxdb.NewConnection(t.Context(), testDB.Config().ConnString())
+		require.NoError(t, err)
+		defer productionDB.Close()
+
+		store, storeCloser := pgxstore.New(productionDB)
+		defer storeCloser()
+
+		httpClient := &http.Client{Timeout: cfg.HttpClientTimeout}
+		client := tzkt.NewClient(httpClient, cfg.TzktAPIURL)
+
+		service := scraper.NewService(
+			client,
+			store,
+			scraper.WithChunkSize(cfg.ChunkSize),
+			scraper.WithPollInterval(cfg.PollInterval),
+			scraper.WithBlockHashEnrichment(client),
+			scraper.WithMaxPollCycles(1),
+		)
+
+		// Act
+		backfillResult := runScraperForOnePollCycle(t, service, testCfg.ShutdownTimeout)
+
+		// Assert
+		assertBackfillSucceeded(t, backfillResult)
+		assertAllBlockHashesStored(t, testDB, t.Context())
+	})
 }
 
-// runScraperUntilPollingStarts executes the scraper and returns backfill results
-func runScraperUntilPollingStarts(t *testing.T, service *scraper.Service, shutdownTimeout time.Duration) scraper.BackfillDone {
+// TestScraperCheckpointReconciliationAcceptance verifies a stale checkpoint is reconciled against stored rows
+func TestScraperCheckpointReconciliationAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it raises a stale checkpoint before fetching", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testCfg := testcfg.New()
+
+		testDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+		defer testDB.Close()
+
+		productionDB, err := pgxdb.NewConnection(t.Context(), testDB.Config().ConnString())
+		require.NoError(t, err)
+		defer productionDB.Close()
+
+		store, storeCloser := pgxstore.New(productionDB)
+		defer storeCloser()
+
+		_, err = store.SaveBatch(t.Context(), []scraper.Delegation{
+			{ID: 10, Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Delegator: "tz1abc", Level: 100},
+			{ID: 20, Timestamp: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC), Delegator: "tz1def", Level: 101},
+		})
+		require.NoError(t, err)
+		_, err = testDB.Exec(t.Context(), "UPDATE scraper_checkpoint SET last_id = 10")
+		require.NoError(t, err)
+
+		var firstIDFilter atomic.Value
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			firstIDFilter.CompareAndSwap(nil, r.URL.Query().Get("id.gt"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		service := scraper.NewService(
+			tzkt.NewClient(server.Client(), server.URL),
+			store,
+			scraper.WithPollInterval(testCfg.PollInterval),
+			scraper.WithCheckpointReconciliation(true),
+			scraper.WithMaxPollCycles(1),
+		)
+
+		// Act
+		backfillResult := runScraperForOnePollCycle(t, service, testCfg.ShutdownTimeout)
+
+		// Assert
+		assertBackfillSucceeded(t, backfillResult)
+		assertCheckpointAdvanced(t, testDB, t.Context(), 10)
+		assert.Equal(t, "20", firstIDFilter.Load(), "Backfill should resume after the highest stored delegation")
+	})
+}
+
+// TestScraperTimestampCheckpointAcceptance verifies continuation from the newest stored timestamp
+func TestScraperTimestampCheckpointAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it advances by timestamp without reprocessing same-timestamp rows", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testCfg := testcfg.New()
+
+		testDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+		defer testDB.Close()
+
+		productionDB, err := pgxdb.NewConnection(t.Context(), testDB.Config().ConnString())
+		require.NoError(t, err)
+		defer productionDB.Close()
+
+		store, storeCloser := pgxstore.New(productionDB)
+		defer storeCloser()
+
+		// IDs 20 and 21 share a timestamp; only 20 is stored so far
+		_, err = store.SaveBatch(t.Context(), []scraper.Delegation{
+			{ID: 10, Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Delegator: "tz1abc", Level: 100},
+			{ID: 20, Timestamp: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC), Delegator: "tz1def", Level: 101},
+		})
+		require.NoError(t, err)
+
+		var firstFilter atomic.Value
+		server := apiWithTimestampFilter(&firstFilter,
+			`{"id":10,"timestamp":"2024-01-01T00:00:00Z","amount":1,"sender":{"address":"tz1abc"},"level":100}`,
+			`{"id":20,"timestamp":"2024-01-01T00:00:01Z","amount":1,"sender":{"address":"tz1def"},"level":101}`,
+			`{"id":21,"timestamp":"2024-01-01T00:00:01Z","amount":1,"sender":{"address":"tz1ghi"},"level":101}`,
+			`{"id":30,"timestamp":"2024-01-01T00:00:02Z","amount":1,"sender":{"address":"tz1jkl"},"level":102}`,
+		)
+		defer server.Close()
+
+		service := scraper.NewService(
+			tzkt.NewClient(server.Client(), server.URL),
+			store,
+			scraper.WithPollInterval(testCfg.PollInterval),
+			scraper.WithTimestampCheckpoint(true),
+			scraper.WithMaxPollCycles(1),
+		)
+
+		// Act
+		backfillResult := runScraperForOnePollCycle(t, service, testCfg.ShutdownTimeout)
+
+		// Assert
+		assert.Equal(t, int64(2), backfillResult.TotalProcessed, "Only delegations 21 and 30 should be processed")
+		assert.Equal(t, "timestamp.ge=2024-01-01T00:00:01Z", firstFilter.Load(), "Backfill should continue by timestamp")
+		assertStoredIDs(t, testDB, []int64{10, 20, 21, 30})
+		assertLastDelegationMatchesCheckpoint(t, testDB, t.Context())
+	})
+}
+
+// apiWithTimestampFilter serves the given delegation JSON objects, which must be in ID
+// order, honouring timestamp.ge, and records the checkpoint filter of the first request
+func apiWithTimestampFilter(firstFilter *atomic.Value, delegations ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("timestamp.ge")
+		if since != "" {
+			firstFilter.CompareAndSwap(nil, "timestamp.ge="+since)
+		} else {
+			firstFilter.CompareAndSwap(nil, "id.gt="+r.URL.Query().Get("id.gt"))
+		}
+
+		items := make([]string, 0, len(delegations))
+		for _, d := range delegations {
+			var row struct {
+				Timestamp string `json:"timestamp"`
+			}
+			_ = json.Unmarshal([]byte(d), &row)
+			if row.Timestamp >= since { // RFC 3339 UTC timestamps compare lexically
+				items = append(items, d)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+}
+
+// TestScraperRepairGapsAcceptance verifies missing delegations are re-fetched into a real database
+func TestScraperRepairGapsAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it fills a deliberate gap in the stored delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+		defer testDB.Close()
+
+		productionDB, err := pgxdb.NewConnection(t.Context(), testDB.Config().ConnString())
+		require.NoError(t, err)
+		defer productionDB.Close()
+
+		store, storeCloser := pgxstore.New(productionDB)
+		defer storeCloser()
+
+		// IDs 3 and 4 are missing from the table but available from the API
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5, 6)
+		defer server.Close()
+		service := scraper.NewService(tzkt.NewClient(server.Client(), server.URL), store, scraper.WithChunkSize(1))
+		_, err = store.SaveBatch(t.Context(), storedDelegations(1, 2))
+		require.NoError(t, err)
+		_, err = store.SaveBatch(t.Context(), storedDelegations(5, 6))
+		require.NoError(t, err)
+
+		// Act
+		result, err := service.RepairGaps(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, scraper.GapRepairResult{Gaps: 1, Windows: 1, Recovered: 2}, result)
+		assertStoredIDs(t, testDB, []int64{1, 2, 3, 4, 5, 6})
+		assertCheckpointAdvanced(t, testDB, t.Context(), 5)
+	})
+}
+
+// TestScraperSkipExistingBatchesAcceptance verifies fully stored batches are detected and skipped
+func TestScraperSkipExistingBatchesAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it skips a fully duplicate batch but still advances the checkpoint", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testDB, store := storeWithStoredDelegations(t, 1, 3)
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+		service := scraperSkippingExistingBatches(server, store)
+
+		// Act
+		backfillResult := runScraperForOnePollCycle(t, service, testcfg.New().ShutdownTimeout)
+
+		// Assert
+		assertBackfillSucceeded(t, backfillResult)
+		assert.Zero(t, store.saves.Load(), "A fully stored batch should not be saved again")
+		assertStoredIDs(t, testDB, []int64{1, 2, 3})
+		assertCheckpointAdvanced(t, testDB, t.Context(), 0)
+	})
+
+	t.Run("it still saves a partially new batch", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		testDB, store := storeWithStoredDelegations(t, 1, 2)
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+		service := scraperSkippingExistingBatches(server, store)
+
+		// Act
+		backfillResult := runScraperForOnePollCycle(t, service, testcfg.New().ShutdownTimeout)
+
+		// Assert
+		assertBackfillSucceeded(t, backfillResult)
+		assert.Equal(t, int64(1), store.saves.Load(), "A batch with new delegations should be saved")
+		assertStoredIDs(t, testDB, []int64{1, 2, 3})
+		assertCheckpointAdvanced(t, testDB, t.Context(), 2)
+	})
+}
+
+// savesCountingStore counts the SaveBatch calls that reach the real store
+type savesCountingStore struct {
+	*pgxstore.Store
+	saves atomic.Int64
+}
+
+func (s *savesCountingStore) SaveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
+	s.saves.Add(1)
+	return s.Store.SaveBatch(ctx, delegations)
+}
+
+// storeWithStoredDelegations seeds delegations from..to with the checkpoint left at 0,
+// so the scraper fetches them again, and wraps the store to count SaveBatch calls
+func storeWithStoredDelegations(t *testing.T, from, to int64) (*pgxpool.Pool, *savesCountingStore) {
+	t.Helper()
+
+	testDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(testDB.Close)
+
+	productionDB, err := pgxdb.NewConnection(t.Context(), testDB.Config().ConnString())
+	require.NoError(t, err)
+	t.Cleanup(productionDB.Close)
+
+	store, _ := pgxstore.New(productionDB)
+	_, err = store.SaveBatch(t.Context(), storedDelegations(from, to))
+	require.NoError(t, err)
+	_, err = testDB.Exec(t.Context(), "UPDATE scraper_checkpoint SET last_id = 0")
+	require.NoError(t, err)
+
+	return testDB, &savesCountingStore{Store: store}
+}
+
+// scraperSkippingExistingBatches fetches the whole test range as one batch and polls once
+func scraperSkippingExistingBatches(server *httptest.Server, store scraper.Store) *scraper.Service {
+	return scraper.NewService(tzkt.NewClient(server.Client(), server.URL), store,
+		scraper.WithChunkSize(3),
+		scraper.WithPollInterval(testcfg.New().PollInterval),
+		scraper.WithSkipExistingBatches(true),
+		scraper.WithMaxPollCycles(1),
+	)
+}
+
+// storedDelegations builds delegations with IDs from..to for seeding the store directly
+func storedDelegations(from, to int64) []scraper.Delegation {
+	result := make([]scraper.Delegation, 0, to-from+1)
+	for id := from; id <= to; id++ {
+		result = append(result, scraper.Delegation{
+			ID:        id,
+			Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Amount:    1000000,
+			Delegator: fmt.Sprintf("tz1%03d", id),
+			Level:     100 + id,
+		})
+	}
+	return result
+}
+
+// assertStoredIDs verifies exactly the expected delegation IDs are stored
+func assertStoredIDs(t *testing.T, testDB *pgxpool.Pool, expected []int64) {
 	t.Helper()
 
-	// Create cancellable context for service
-	ctx, cancel := context.WithCancel(t.Context())
+	rows, err := testDB.Query(t.Context(), "SELECT id FROM delegations ORDER BY id")
+	require.NoError(t, err)
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	require.NoError(t, err)
+	assert.Equal(t, expected, ids, "Gap repair should leave no IDs missing")
+}
+
+// runScraperForOnePollCycle executes the scraper, which must be built with WithMaxPollCycles(1),
+// until it stops by itself after backfill and a single poll, and returns backfill results
+func runScraperForOnePollCycle(t *testing.T, service *scraper.Service, shutdownTimeout time.Duration) scraper.BackfillDone {
+	t.Helper()
 
 	// Start service (returns immediately, runs in background goroutine)
-	events, done := service.Start(ctx)
+	events, done := service.Start(t.Context())
 
 	// Capture backfill result for assertions
 	var backfillDone scraper.BackfillDone
 
-	// Subscribe to events and cancel when we reach polling phase
+	// Subscribe to events for observability; the service stops after its single poll cycle
 	closer := scraper.NewSubscriber(events,
 		scraper.OnBackfillDone(func(e scraper.BackfillDone) {
 			backfillDone = e
 			t.Logf("Backfill completed: %d delegations in %v", e.TotalProcessed, e.Duration)
 		}),
 		scraper.OnPollingStarted(func(e scraper.PollingStarted) {
-			t.Logf("Polling started with interval: %v - canceling service", e.Interval)
-			cancel()
+			t.Logf("Polling started with interval: %v", e.Interval)
 		}),
 		scraper.OnPollingSyncCompleted(func(e scraper.PollingSyncCompleted) {
 			t.Logf("Polling cycle: %d delegations fetched, checkpoint: %d", e.Fetched, e.CheckpointID)
 		}),
 		scraper.OnPollingShutdown(func(e scraper.PollingShutdown) {
 			t.Logf("Polling shutdown: %v", e.Reason)
+			assert.ErrorIs(t, e.Reason, scraper.ErrMaxPollCyclesReached, "Service should stop after its poll cycle")
 		}),
 	)
 	t.Cleanup(closer)
@@ -201,6 +526,18 @@ func assertTimestampsAreValid(t *testing.T, testDB *pgxpool.Pool, ctx context.Co
 	assert.False(t, lastTimestamp.IsZero(), "Last timestamp should not be zero")
 }
 
+// assertAllBlockHashesStored verifies every stored delegation has a block hash
+func assertAllBlockHashesStored(t *testing.T, testDB *pgxpool.Pool, ctx context.Context) {
+	t.Helper()
+
+	var total, withHash int64
+	err := testDB.QueryRow(ctx, "SELECT COUNT(*), COUNT(block_hash) FROM delegations").Scan(&total, &withHash)
+	require.NoError(t, err)
+
+	assert.Positive(t, total, "Expected delegations to be stored")
+	assert.Equal(t, total, withHash, "Every stored delegation should have a block hash")
+}
+
 // createTestService creates a scraper service with test-optimized configuration
 func createTestService(t *testing.T, client *tzkt.Client, store *pgxstore.Store, cfg config.Config) *scraper.Service {
 	t.Helper()
@@ -210,6 +547,7 @@ func createTestService(t *testing.T, client *tzkt.Client, store *pgxstore.Store,
 		store,
 		scraper.WithChunkSize(cfg.ChunkSize),
 		scraper.WithPollInterval(cfg.PollInterval),
+		scraper.WithMaxPollCycles(1),
 	)
 }
 
@@ -2,15 +2,23 @@ package scraper_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/screwyprof/delegator/pkg/httpkit"
 	"github.com/screwyprof/delegator/pkg/tzkt"
 	"github.com/screwyprof/delegator/scraper"
 )
@@ -58,35 +66,1120 @@ func TestServiceBackfillBehavior(t *testing.T) {
 		assertCheckpointAdvancedTo(t, store, 5)
 	})
 
+	t.Run("it reports the checkpoint persisted by the store", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations(delegation(5))
+		defer server.Close()
+
+		store := &aheadStore{mockStore: storeWithCheckpoint(0), persistedID: 50}
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, store, scraper.WithChunkSize(1))
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		require.NotEmpty(t, events.syncCompleted)
+		assert.Equal(t, int64(50), events.syncCompleted[0].CheckpointID,
+			"The checkpoint should come from the store, not the batch")
+	})
+
 	t.Run("it processes multiple batches sequentially", func(t *testing.T) {
 		t.Parallel()
 
 		// Arrange
-		expectedDelegations := []tzkt.Delegation{delegation(1), delegation(2), delegation(3)}
-		server := apiWithDelegations(expectedDelegations...)
+		expectedDelegations := []tzkt.Delegation{delegation(1), delegation(2), delegation(3)}
+		server := apiWithDelegations(expectedDelegations...)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithChunkSize(1)(server, store)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertDelegationsWereSaved(t, savedBatchesCh, expectedDelegations)
+		assertCheckpointAdvancedTo(t, store, 3)
+	})
+
+	t.Run("it handles API errors during backfill", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiReturningError()
+		defer server.Close()
+
+		_, store := storeCapturingBatches()
+		svc := scraperWithChunkSize(1)(server, store)
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assertBackfillFailedWithAPIError(t, errorCh)
+	})
+
+	t.Run("it aborts backfill when the timeout elapses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithEndlessDelegations()
+		defer server.Close()
+
+		store := storeWithCheckpoint(0)
+		clock, svc := clockControlledBackfillWithTimeout(server, store, time.Minute)
+
+		// Act
+		errorCh := runBackfillUntilTimeout(t, svc, clock)
+
+		// Assert
+		assertBackfillFailedWithTimeout(t, errorCh)
+	})
+
+	t.Run("it interrupts a hung request when the timeout elapses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		client := &hangingClient{called: make(chan struct{})}
+		clock := createTestClock()
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithBackfillTimeout(time.Minute),
+		)
+		errorCh := runBackfillExpectingError(t, svc)
+		<-client.called
+
+		// Act
+		clock.tick <- time.Now()
+
+		// Assert
+		select {
+		case err := <-errorCh:
+			assert.ErrorIs(t, err, scraper.ErrBackfillTimeout)
+		case <-time.After(time.Second):
+			t.Fatal("Backfill should not wait for the hung request past its timeout")
+		}
+	})
+
+	t.Run("it walks newest-first when backfilling in descending order", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithBackfillDirection(server, store, scraper.DescendingByID)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{3, 2, 1})
+		assertCheckpointAdvancedTo(t, store, 3)
+	})
+
+	t.Run("it stops at the starting checkpoint and keeps the forward checkpoint when descending", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		store.lastID = 2
+		svc := scraperWithBackfillDirection(server, store, scraper.DescendingByID)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{5, 4, 3})
+		assertCheckpointAdvancedTo(t, store, 5)
+	})
+
+	t.Run("it enriches delegations with block hashes before saving", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		expectedDelegations := []tzkt.Delegation{delegation(1), delegation(2)}
+		server := apiWithDelegations(expectedDelegations...)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		blocks := blockHashesForLevels(map[int64]string{101: "BLockOne", 102: "BLockTwo"})
+		svc := scraperWithBlockHashEnrichment(server, store, blocks)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertBlockHashesWereSaved(t, savedBatchesCh, []string{"BLockOne", "BLockTwo"})
+	})
+
+	t.Run("it fails backfill when block hash lookup fails", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations(delegation(1))
+		defer server.Close()
+
+		_, store := storeCapturingBatches()
+		blocks := &fakeBlockHashClient{err: errors.New("blocks unavailable")}
+		svc := scraperWithBlockHashEnrichment(server, store, blocks)
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assertBackfillFailedWithEnrichmentError(t, errorCh)
+	})
+
+	t.Run("it fetches without saving in dry-run mode", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations(delegation(1), delegation(2))
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperInDryRun(server, store)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assertNothingWasSaved(t, savedBatchesCh)
+		assertCheckpointAdvancedTo(t, store, 0)
+		assertBackfillSyncCompletedEvents(t, events.syncCompleted, 2)
+		assertBackfillDoneEvent(t, events.done, 2)
+	})
+
+	t.Run("it rejects a batch containing a negative amount", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		negative := delegation(2)
+		negative.Amount = -1
+		client := &fakeClient{delegations: []tzkt.Delegation{delegation(1), negative}}
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraper.NewService(client, store, scraper.WithChunkSize(2))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assertBackfillFailedWithConversionError(t, errorCh)
+		assertNothingWasSaved(t, savedBatchesCh)
+	})
+
+	t.Run("it reconciles a stale checkpoint with stored delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations()
+		defer server.Close()
+
+		store := &reconcilingStore{mockStore: storeWithCheckpoint(3), maxID: 7}
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, store, scraper.WithCheckpointReconciliation(true))
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.Equal(t, int64(7), events.started.CheckpointID, "Backfill should start from the reconciled checkpoint")
+		assertCheckpointAdvancedTo(t, store.mockStore, 7)
+	})
+
+	t.Run("it fails when the store cannot reconcile checkpoints", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations()
+		defer server.Close()
+
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0), scraper.WithCheckpointReconciliation(true))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assert.ErrorIs(t, <-errorCh, scraper.ErrCheckpointReconcile)
+	})
+}
+
+func TestServiceSkipExistingBatches(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it advances the checkpoint past a fully stored batch without saving it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+
+		savedBatchesCh, store := storeWithExistingUpTo(3)
+		svc := scraperSkippingExisting(server, store)
+
+		// Act
+		<-runBackfillUntilComplete(t, svc)
+
+		// Assert
+		assertNothingWasSaved(t, savedBatchesCh)
+		assertCheckpointAdvancedTo(t, store.mockStore, 3)
+	})
+
+	t.Run("it saves a batch containing new delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+
+		savedBatchesCh, store := storeWithExistingUpTo(2)
+		svc := scraperSkippingExisting(server, store)
+
+		// Act
+		<-runBackfillUntilComplete(t, svc)
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{1, 2, 3})
+		assertCheckpointAdvancedTo(t, store.mockStore, 3)
+	})
+
+	t.Run("it fails when the store cannot check existence", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3)
+		defer server.Close()
+
+		svc := scraperSkippingExisting(server, storeWithCheckpoint(0))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		err := <-errorCh
+		assert.ErrorIs(t, err, scraper.ErrSaveBatchFailed)
+		assert.ErrorIs(t, err, scraper.ErrSkipExistingUnsupported)
+	})
+}
+
+// TestServiceDuplicateIDs tests batches in which the API repeats a delegation
+func TestServiceDuplicateIDs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it saves a repeated delegation once and counts it once", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 2, 3)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithChunkSize(10)(server, store)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{1, 2, 3})
+		assertCheckpointAdvancedTo(t, store, 3)
+		require.Len(t, events.syncCompleted, 1)
+		assert.Equal(t, 3, events.syncCompleted[0].Fetched)
+		assert.Equal(t, int64(3), events.done.TotalProcessed)
+	})
+
+	t.Run("it drops the repeat when backfilling newest first", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 2, 3)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithMaxBackfillRecords(server, store, scraper.DescendingByID, 10)
+
+		// Act
+		<-runBackfillUntilComplete(t, svc)
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{2, 3, 1})
+		assertCheckpointAdvancedTo(t, store, 3)
+	})
+}
+
+// TestServiceTimestampCheckpoint tests continuing from the stored timestamp instead of the ID
+func TestServiceConfirmationLag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it defers delegations within the lag of the head", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4) // levels 101 to 104
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithConfirmationLag(server, store, 2, 104)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{1, 2})
+		assertCheckpointAdvancedTo(t, store, 2)
+	})
+
+	t.Run("it saves deferred delegations once the head moves on", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4)
+		defer server.Close()
+
+		store := storeWithCheckpoint(0)
+		clock := createTestClock()
+		client := headAt(server, 104, 104, 106) // two backfill batches, then one poll
+		svc := scraper.NewService(client, store, scraper.WithClock(clock), scraper.WithConfirmationLag(2))
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 1)
+
+		// Assert
+		require.Len(t, cycles, 1)
+		assertPollFoundDelegations(t, cycles[0], 2)
+		assert.Equal(t, int64(4), cycles[0].CheckpointID)
+	})
+
+	t.Run("it fails when the client cannot report the head", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		client := &fakeClient{delegations: []tzkt.Delegation{delegation(1)}}
+		svc := scraper.NewService(client, storeWithCheckpoint(0), scraper.WithConfirmationLag(2))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assert.ErrorIs(t, <-errorCh, scraper.ErrConfirmationLagUnsupported)
+	})
+
+	t.Run("it fails when the head cannot be read", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1)
+		defer server.Close()
+
+		client := headAt(server)
+		client.err = errors.New("head unavailable")
+		_, store := storeCapturingBatches()
+		svc := scraper.NewService(client, store, scraper.WithConfirmationLag(2))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		assert.ErrorIs(t, <-errorCh, scraper.ErrHeadLevel)
+	})
+}
+
+func TestServiceMaxBackfillRecords(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stops backfill at the limit, trimming the last request", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithMaxBackfillRecords(server, store, scraper.AscendingByID, 5)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.Equal(t, int64(5), events.done.TotalProcessed)
+		require.Len(t, events.syncCompleted, 2)
+		assert.Equal(t, 3, events.syncCompleted[0].Fetched)
+		assert.Equal(t, 2, events.syncCompleted[1].Fetched, "The last request should only fetch what is left")
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{1, 2, 3, 4, 5})
+	})
+
+	t.Run("it applies the limit to a descending backfill", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2, 3, 4, 5)
+		defer server.Close()
+
+		savedBatchesCh, store := storeCapturingBatches()
+		svc := scraperWithMaxBackfillRecords(server, store, scraper.DescendingByID, 2)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.Equal(t, int64(2), events.done.TotalProcessed)
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{4, 5})
+	})
+
+	t.Run("it ends early when the API runs dry below the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFilterableDelegations(1, 2)
+		defer server.Close()
+
+		_, store := storeCapturingBatches()
+		svc := scraperWithMaxBackfillRecords(server, store, scraper.AscendingByID, 5)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.Equal(t, int64(2), events.done.TotalProcessed)
+	})
+}
+
+func TestServiceTimestampCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	// IDs 2 and 3 share a timestamp; 2 was already stored
+	delegations := []tzkt.Delegation{delegationAt(1, 1), delegationAt(2, 2), delegationAt(3, 2), delegationAt(4, 3)}
+
+	t.Run("it continues from the stored timestamp without reprocessing same-timestamp rows", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, filters := apiWithTimestampedDelegations(delegations...)
+		defer server.Close()
+
+		savedBatchesCh, store := timestampStoreCapturingBatches(2, delegationAt(2, 2).Timestamp)
+		svc := scraperWithTimestampCheckpoint(server, store)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{3, 4})
+		assertCheckpointAdvancedTo(t, store.mockStore, 4)
+		assert.Equal(t, "timestamp.ge=2024-01-01T00:02:00Z", <-filters, "Backfill should continue by timestamp")
+	})
+
+	t.Run("it uses the ID checkpoint while nothing is stored", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, filters := apiWithTimestampedDelegations(delegations...)
+		defer server.Close()
+
+		savedBatchesCh, store := timestampStoreCapturingBatches(2, time.Time{})
+		svc := scraperWithTimestampCheckpoint(server, store)
+
+		// Act
+		done := runBackfillUntilComplete(t, svc)
+		<-done
+
+		// Assert
+		assertSavedIDsInOrder(t, savedBatchesCh, []int64{3, 4})
+		assert.Equal(t, "id.gt=2", <-filters, "The first batch should continue by ID")
+	})
+
+	t.Run("it fails when the store cannot checkpoint by timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, _ := apiWithTimestampedDelegations(delegations...)
+		defer server.Close()
+
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0), scraper.WithTimestampCheckpoint(true))
+
+		// Act
+		errorCh := runBackfillExpectingError(t, svc)
+
+		// Assert
+		err := <-errorCh
+		assert.ErrorIs(t, err, scraper.ErrCheckpointRetrieval)
+		assert.ErrorIs(t, err, scraper.ErrTimestampCheckpointUnsupported)
+	})
+}
+
+// TestServicePollingBehavior tests core polling business logic
+func TestServicePollingBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it emits no event for empty polls when suppression is enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), emptyPoll(), pollWithDelegation(1))
+		defer server.Close()
+
+		clock := createTestClock()
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithChunkSize(1),
+			scraper.WithSuppressEmptyPollEvents(true),
+		)
+
+		// Act
+		cycles := runPollingTicksUntilCycles(t, svc, clock, 3, 1)
+
+		// Assert
+		require.Len(t, cycles, 1)
+		assertPollFoundDelegations(t, cycles[0], 1)
+	})
+
+	t.Run("it emits an event for empty polls by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), emptyPoll(), pollWithDelegation(1))
+		defer server.Close()
+
+		clock, svc := clockControlledPolling(server, storeWithCheckpoint(0))
+
+		// Act
+		cycles := runPollingTicksUntilCycles(t, svc, clock, 3, 3)
+
+		// Assert
+		assertEmptyPollOccurred(t, cycles[0])
+		assertEmptyPollOccurred(t, cycles[1])
+		assertPollFoundDelegations(t, cycles[2], 1)
+	})
+
+	t.Run("it polls at configured intervals after backfill", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), pollWithDelegation(1))
+		defer server.Close()
+
+		store := storeWithCheckpoint(0)
+		clock, svc := clockControlledPolling(server, store)
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 2)
+
+		// Assert
+		assertEmptyPollOccurred(t, cycles[0])
+		assertPollFoundDelegations(t, cycles[1], 1)
+	})
+
+	t.Run("it continues from last checkpoint during polling", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(pollWithDelegation(6))
+		defer server.Close()
+
+		store := storeWithCheckpoint(5) // Start with checkpoint at 5
+		clock, svc := clockControlledPolling(server, store)
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 1)
+
+		// Assert
+		assertPollFoundDelegations(t, cycles[0], 1)
+		assertCheckpointAdvancedTo(t, store, 6)
+	})
+
+	t.Run("it handles API errors during polling", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingErrors()
+		defer server.Close()
+
+		store := storeWithCheckpoint(0)
+		clock, svc := clockControlledPolling(server, store)
+
+		// Act
+		errorCh := runPollingExpectingError(t, svc, clock)
+
+		// Assert
+		assertPollingFailedWithAPIError(t, errorCh)
+	})
+}
+
+func TestServiceIdleBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it grows the interval after empty polls up to the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), emptyPoll(), emptyPoll())
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithIdleBackoff(server, storeWithCheckpoint(0), 35*time.Second, 2)
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 3)
+
+		// Assert
+		assertNextIntervals(t, cycles, 20*time.Second, 35*time.Second, 35*time.Second)
+	})
+
+	t.Run("it resets to the base interval once a poll fetches data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), emptyPoll(), pollWithDelegation(1), emptyPoll())
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithIdleBackoff(server, storeWithCheckpoint(0), time.Minute, 2)
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 4)
+
+		// Assert
+		assertNextIntervals(t, cycles, 20*time.Second, 40*time.Second, 10*time.Second, 20*time.Second)
+	})
+
+	t.Run("it keeps the base interval by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(emptyPoll(), emptyPoll())
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithIdleBackoff(server, storeWithCheckpoint(0), 0, 0)
+
+		// Act
+		cycles := runPollingCycles(t, svc, clock, 2)
+
+		// Assert
+		assertNextIntervals(t, cycles, 10*time.Second, 10*time.Second)
+	})
+}
+
+func TestServiceTriggerPoll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it runs a sync cycle immediately without a clock tick", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(pollWithDelegation(6))
+		defer server.Close()
+
+		store := storeWithCheckpoint(5)
+		_, svc := clockControlledPolling(server, store)
+
+		// Act
+		cycles := runTriggeredPolls(t, svc, 1)
+
+		// Assert
+		assertPollFoundDelegations(t, cycles[0], 1)
+		assertCheckpointAdvancedTo(t, store, 6)
+	})
+
+	t.Run("it polls immediately despite a long real-time interval", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(pollWithDelegation(1), pollWithDelegation(2))
+		defer server.Close()
+
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithPollInterval(time.Hour),
+			scraper.WithChunkSize(1),
+		)
+
+		// Act
+		cycles := runTriggeredPolls(t, svc, 2)
+
+		// Assert
+		assertPollFoundDelegations(t, cycles[0], 1)
+		assertPollFoundDelegations(t, cycles[1], 1)
+	})
+}
+
+// TestServiceEventEmission tests observability and event emission
+func TestServiceMaxPollCycles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stops cleanly after the configured number of poll cycles", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses(pollWithDelegation(1), emptyPoll(), pollWithDelegation(2))
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithMaxCycles(server, storeWithCheckpoint(0), 3)
+
+		// Act
+		stopped := runPollingUntilStopped(t, svc, clock, 3)
+
+		// Assert
+		assert.Len(t, stopped.cycles, 3, "Should poll exactly the configured number of times")
+		assertPollFoundDelegations(t, stopped.cycles[2], 1)
+		assert.ErrorIs(t, stopped.shutdown.Reason, scraper.ErrMaxPollCyclesReached)
+	})
+
+	t.Run("it counts failed polls as cycles", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingErrors()
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithMaxCycles(server, storeWithCheckpoint(0), 2)
+
+		// Act
+		stopped := runPollingUntilStopped(t, svc, clock, 2)
+
+		// Assert
+		assert.Equal(t, 2, stopped.errors, "Both failed polls should be reported")
+		assert.Empty(t, stopped.cycles)
+		assert.ErrorIs(t, stopped.shutdown.Reason, scraper.ErrMaxPollCyclesReached)
+	})
+
+	t.Run("it still stops on cancellation before the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingResponses()
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithMaxCycles(server, storeWithCheckpoint(0), 3)
+
+		// Act
+		shutdown := runPollingCapturingShutdown(t, svc, clock)
+
+		// Assert
+		assertShutdownEventOccurred(t, shutdown)
+	})
+}
+
+func TestServiceMaxConsecutivePollErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it shuts down after the configured number of consecutive failures", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithPollingErrors()
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithMaxErrors(server, storeWithCheckpoint(0), 3, 0)
+
+		// Act
+		stopped := runPollingUntilStopped(t, svc, clock, 3)
+
+		// Assert
+		assert.Equal(t, 3, stopped.errors, "Every failed poll should be reported")
+		assert.ErrorIs(t, stopped.shutdown.Reason, scraper.ErrTooManyPollErrors)
+		assert.ErrorIs(t, stopped.shutdown.Reason, scraper.ErrAPIRequestFailed, "The last poll error should be kept")
+	})
+
+	t.Run("it keeps polling when a success resets the count", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithFailingPolls(true, true, false, true, true)
+		defer server.Close()
+
+		clock, svc := clockControlledPollingWithMaxErrors(server, storeWithCheckpoint(0), 3, 5)
+
+		// Act
+		stopped := runPollingUntilStopped(t, svc, clock, 5)
+
+		// Assert
+		assert.Equal(t, 4, stopped.errors)
+		assert.Len(t, stopped.cycles, 1)
+		assert.ErrorIs(t, stopped.shutdown.Reason, scraper.ErrMaxPollCyclesReached, "Failures separated by a success should not shut down")
+	})
+}
+
+func TestServiceCatchUpBurst(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it polls right away while polls fetch full chunks, then waits for the interval", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, addDelegations := apiWithGrowingDelegations()
+		defer server.Close()
+
+		clock, svc := clockControlledCatchUp(server, true, 4)
+		backfilled, captured := startCapturingPollCycles(t, svc)
+		<-backfilled
+		addDelegations(1, 2, 3, 4, 5) // backlog piled up while the scraper was down
+
+		// Act
+		fireWithin(t, clock, 10*time.Second, "the first poll should wait for the poll interval")
+		fireWithin(t, clock, 10*time.Second, "the poll after a partial chunk should wait for the interval")
+		cycles := captured()
+
+		// Assert
+		require.Len(t, cycles, 4)
+		assert.Equal(t, []int{2, 2, 1, 0}, fetchedPerCycle(cycles))
+		assertNextIntervals(t, cycles, 0, 0, 10*time.Second, 10*time.Second)
+	})
+
+	t.Run("it waits for the interval after a full chunk when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, addDelegations := apiWithGrowingDelegations()
+		defer server.Close()
+
+		clock, svc := clockControlledCatchUp(server, false, 2)
+		backfilled, captured := startCapturingPollCycles(t, svc)
+		<-backfilled
+		addDelegations(1, 2, 3, 4, 5)
+
+		// Act
+		fireWithin(t, clock, 10*time.Second, "the first poll should wait for the poll interval")
+		fireWithin(t, clock, 10*time.Second, "a full chunk should not skip the interval")
+		cycles := captured()
+
+		// Assert
+		assert.Equal(t, []int{2, 2}, fetchedPerCycle(cycles))
+		assertNextIntervals(t, cycles, 10*time.Second, 10*time.Second)
+	})
+}
+
+func TestServiceRateLimitedPolling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it waits for the Retry-After of a rate-limited poll, then resumes the interval", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiRateLimitingFirstPoll("30")
+		defer server.Close()
+
+		clock := newDurationClock()
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithPollInterval(10*time.Second),
+			scraper.WithMaxPollCycles(3),
+		)
+
+		events, done := svc.Start(t.Context())
+		var pollErrors []scraper.PollingError
+		subCloser := scraper.NewSubscriber(events,
+			scraper.OnPollingError(func(e scraper.PollingError) { pollErrors = append(pollErrors, e) }),
+		)
+
+		// Act
+		fireWithin(t, clock, 10*time.Second, "the first poll should wait for the poll interval")
+		fireWithin(t, clock, 30*time.Second, "the poll after a 429 should wait for its Retry-After")
+		fireWithin(t, clock, 10*time.Second, "later polls should wait for the poll interval again")
+		<-done
+		subCloser()
+
+		// Assert
+		require.Len(t, pollErrors, 1)
+		var rateLimited *tzkt.RateLimitError
+		require.ErrorAs(t, pollErrors[0].Err, &rateLimited)
+		assert.Equal(t, 30*time.Second, rateLimited.RetryAfter)
+	})
+
+	t.Run("it waits for the Retry-After even with transport retries enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiRateLimitingFirstPoll("30")
+		defer server.Close()
+
+		clock := newDurationClock()
+		client := tzkt.NewClient(http.DefaultClient, server.URL,
+			tzkt.WithRetry(httpkit.WithMaxRetries(3), httpkit.WithBackoff(func(int) time.Duration { return 0 })))
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithPollInterval(10*time.Second),
+			scraper.WithMaxPollCycles(2),
+		)
+
+		events, done := svc.Start(t.Context())
+		var pollErrors []scraper.PollingError
+		subCloser := scraper.NewSubscriber(events,
+			scraper.OnPollingError(func(e scraper.PollingError) { pollErrors = append(pollErrors, e) }),
+		)
+
+		// Act
+		fireWithin(t, clock, 10*time.Second, "the first poll should wait for the poll interval")
+		fireWithin(t, clock, 30*time.Second, "the 429 should reach the poll loop and set its wait")
+		<-done
+		subCloser()
+
+		// Assert
+		require.Len(t, pollErrors, 1)
+		assert.ErrorIs(t, pollErrors[0].Err, tzkt.ErrRateLimited)
+	})
+
+	t.Run("it keeps the poll interval when the Retry-After is shorter", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiRateLimitingFirstPoll("1")
+		defer server.Close()
+
+		clock := newDurationClock()
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithPollInterval(10*time.Second),
+			scraper.WithMaxPollCycles(2),
+		)
+		_, done := svc.Start(t.Context())
+
+		// Act
+		fireWithin(t, clock, 10*time.Second, "the first poll should wait for the poll interval")
+		fireWithin(t, clock, 10*time.Second, "a shorter Retry-After should not shorten the wait")
+
+		// Assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Service did not stop after its last poll cycle")
+		}
+	})
+}
+
+// TestServiceConcurrentPolling tests polling from the head while backfill is still running
+func TestServiceConcurrentPolling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it saves new delegations while backfill is still running", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, addDelegations := apiWithGrowingDelegations(1, 2, 3)
+		defer server.Close()
+
+		polled := make(chan struct{})
+		store := &concurrentStore{beforeSave: func(ctx context.Context, batch []scraper.Delegation) error {
+			switch batch[0].ID {
+			case 1: // hold the backfill until polling has saved the new delegation
+				select {
+				case <-polled:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			case 4:
+				close(polled)
+			}
+			return nil
+		}}
+		clock, svc := clockControlledConcurrentPolling(server, store)
+
+		// Act
+		events := runConcurrentPollingCapturingEvents(t, svc, clock, func() { addDelegations(4) })
+
+		// Assert
+		assert.Equal(t, []int64{4, 1, 2, 3}, store.savedIDs(), "Polling should save the new delegation before backfill finishes")
+		assert.Equal(t, int64(4), store.checkpoint())
+		for _, ev := range eventsOfType[scraper.BackfillSyncCompleted](events) {
+			assert.Equal(t, int64(4), ev.CheckpointID, "Backfill should never lower the checkpoint polling advanced")
+		}
+		assert.Equal(t, int64(3), eventsOfType[scraper.BackfillDone](events)[0].TotalProcessed,
+			"Backfill should stop at the newest delegation seen at startup")
+		assert.Equal(t, 1, totalPolled(events), "Polling should not refetch what backfill saved")
+
+		shutdown, ok := events[len(events)-1].(scraper.PollingShutdown)
+		require.True(t, ok, "The last event should be the polling shutdown")
+		assert.ErrorIs(t, shutdown.Reason, scraper.ErrMaxPollCyclesReached)
+	})
+
+	t.Run("it polls from where a backfill stopped short of the head", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, _ := apiWithGrowingDelegations(1, 2, 3)
+		defer server.Close()
+
+		store := &concurrentStore{}
+		clock, svc := clockControlledConcurrentPolling(server, store,
+			scraper.WithChunkSize(10), scraper.WithMaxBackfillRecords(1))
+
+		// Act
+		events := runConcurrentPollingCapturingEvents(t, svc, clock, nil)
+
+		// Assert
+		assert.ElementsMatch(t, []int64{1, 2, 3}, store.savedIDs())
+		assert.Equal(t, int64(3), store.checkpoint())
+		assert.Equal(t, 2, totalPolled(events))
+	})
+
+	t.Run("it walks down to the checkpoint when backfilling in descending order", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, _ := apiWithGrowingDelegations(1, 2, 3)
+		defer server.Close()
+
+		store := &concurrentStore{}
+		clock, svc := clockControlledConcurrentPolling(server, store, scraper.WithBackfillDirection(scraper.DescendingByID))
+
+		// Act
+		events := runConcurrentPollingCapturingEvents(t, svc, clock, nil)
+
+		// Assert
+		assert.Equal(t, []int64{3, 2, 1}, store.savedIDs())
+		assert.Equal(t, int64(3), store.checkpoint())
+		assert.Zero(t, totalPolled(events))
+	})
+
+	t.Run("it stops polling when backfill fails", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, _ := apiWithGrowingDelegations(1, 2, 3)
 		defer server.Close()
 
-		savedBatchesCh, store := storeCapturingBatches()
-		svc := scraperWithChunkSize(1)(server, store)
+		store := &concurrentStore{beforeSave: func(context.Context, []scraper.Delegation) error {
+			return errors.New("disk full")
+		}}
+		clock, svc := clockControlledConcurrentPolling(server, store)
 
 		// Act
-		done := runBackfillUntilComplete(t, svc)
-		<-done
+		events := runConcurrentPollingCapturingEvents(t, svc, clock, nil)
 
 		// Assert
-		assertDelegationsWereSaved(t, savedBatchesCh, expectedDelegations)
-		assertCheckpointAdvancedTo(t, store, 3)
+		backfillErr, ok := events[len(events)-1].(scraper.BackfillError)
+		require.True(t, ok, "The last event should be the backfill error")
+		assert.ErrorIs(t, backfillErr.Err, scraper.ErrSaveBatchFailed)
+
+		shutdowns := eventsOfType[scraper.PollingShutdown](events)
+		require.Len(t, shutdowns, 1)
+		assert.ErrorIs(t, shutdowns[0].Reason, context.Canceled)
 	})
 
-	t.Run("it handles API errors during backfill", func(t *testing.T) {
+	t.Run("it fails when the newest delegation cannot be read", func(t *testing.T) {
 		t.Parallel()
 
 		// Arrange
-		server := apiReturningError()
+		server := createErrorServer()
 		defer server.Close()
 
-		_, store := storeCapturingBatches()
-		svc := scraperWithChunkSize(1)(server, store)
+		_, svc := clockControlledConcurrentPolling(server, &concurrentStore{})
 
 		// Act
 		errorCh := runBackfillExpectingError(t, svc)
@@ -96,65 +1189,128 @@ func TestServiceBackfillBehavior(t *testing.T) {
 	})
 }
 
-// TestServicePollingBehavior tests core polling business logic
-func TestServicePollingBehavior(t *testing.T) {
+// TestServiceHeartbeat tests the liveness events emitted independently of polling
+func TestServiceHeartbeat(t *testing.T) {
 	t.Parallel()
 
-	t.Run("it polls at configured intervals after backfill", func(t *testing.T) {
+	const heartbeat = 30 * time.Second
+
+	t.Run("it emits a heartbeat every interval while polls find nothing", func(t *testing.T) {
 		t.Parallel()
 
 		// Arrange
-		server := apiWithPollingResponses(emptyPoll(), pollWithDelegation(1))
+		server := createTestServer(nil) // every request finds nothing
 		defer server.Close()
 
-		store := storeWithCheckpoint(0)
-		clock, svc := clockControlledPolling(server, store)
+		clock := newDurationClock()
+		svc := scraper.NewService(tzkt.NewClient(http.DefaultClient, server.URL), storeWithCheckpoint(5),
+			scraper.WithClock(clock),
+			scraper.WithHeartbeat(heartbeat),
+			scraper.WithSuppressEmptyPollEvents(true),
+		)
+		events := startCapturingHeartbeats(t, svc)
 
 		// Act
-		cycles := runPollingCycles(t, svc, clock, 2)
+		var beats []time.Time
+		for i := range 3 {
+			at := clock.Now().Add(time.Duration(i+1) * heartbeat)
+			clock.fire(heartbeat, at)
+			beats = append(beats, at)
+		}
 
 		// Assert
-		assertEmptyPollOccurred(t, cycles[0])
-		assertPollFoundDelegations(t, cycles[1], 1)
+		for _, at := range beats {
+			assert.Equal(t, scraper.Heartbeat{At: at, CheckpointID: 5}, nextHeartbeat(t, events))
+		}
 	})
 
-	t.Run("it continues from last checkpoint during polling", func(t *testing.T) {
+	t.Run("it reports the checkpoint reached by the latest sync", func(t *testing.T) {
 		t.Parallel()
 
 		// Arrange
-		server := apiWithPollingResponses(pollWithDelegation(6))
+		server := apiWithDelegations(delegation(1), delegation(2))
 		defer server.Close()
 
-		store := storeWithCheckpoint(5) // Start with checkpoint at 5
-		clock, svc := clockControlledPolling(server, store)
+		clock := newDurationClock()
+		svc := scraper.NewService(tzkt.NewClient(http.DefaultClient, server.URL), storeWithCheckpoint(0),
+			scraper.WithClock(clock),
+			scraper.WithChunkSize(1),
+			scraper.WithHeartbeat(heartbeat),
+		)
+		events := startCapturingHeartbeats(t, svc)
+		<-events.backfillDone
 
 		// Act
-		cycles := runPollingCycles(t, svc, clock, 1)
+		clock.fire(heartbeat, clock.Now())
 
 		// Assert
-		assertPollFoundDelegations(t, cycles[0], 1)
-		assertCheckpointAdvancedTo(t, store, 6)
+		assert.Equal(t, int64(2), nextHeartbeat(t, events).CheckpointID)
 	})
+}
 
-	t.Run("it handles API errors during polling", func(t *testing.T) {
+func TestServiceStartupProbe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it fails fast without touching the store when the API is unreachable", func(t *testing.T) {
 		t.Parallel()
 
 		// Arrange
-		server := apiWithPollingErrors()
+		server := createTestServer(nil)
+		server.Close() // nothing listens at the URL any more
+
+		var saves atomic.Int32
+		store := createTestStore(0, func(context.Context, []scraper.Delegation) error {
+			saves.Add(1)
+			return nil
+		})
+		svc := scraper.NewService(tzkt.NewClient(http.DefaultClient, server.URL), store,
+			scraper.WithStartupProbe(true),
+		)
+
+		// Act
+		events := runBackfillCancellingAfter(t, svc, 0)
+
+		// Assert
+		require.Len(t, events, 1, "The probe failure should be the only event")
+		backfillError, ok := events[0].(scraper.BackfillError)
+		require.True(t, ok, "Expected a BackfillError, got %T", events[0])
+		assert.ErrorIs(t, backfillError.Err, scraper.ErrStartupProbeFailed)
+		assert.ErrorIs(t, backfillError.Err, scraper.ErrAPIRequestFailed)
+		assert.Zero(t, saves.Load(), "Nothing should be saved after a failed probe")
+	})
+
+	t.Run("it backfills as usual once the probe succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var requests []string
+		var mu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests = append(requests, r.URL.Query().Get("limit"))
+			mu.Unlock()
+			_, _ = w.Write([]byte(emptyResponse()))
+		}))
 		defer server.Close()
 
-		store := storeWithCheckpoint(0)
-		clock, svc := clockControlledPolling(server, store)
+		svc := scraper.NewService(tzkt.NewClient(http.DefaultClient, server.URL), storeWithCheckpoint(0),
+			scraper.WithChunkSize(100),
+			scraper.WithStartupProbe(true),
+		)
 
 		// Act
-		errorCh := runPollingExpectingError(t, svc, clock)
+		done := runBackfillUntilComplete(t, svc)
+		<-done
 
 		// Assert
-		assertPollingFailedWithAPIError(t, errorCh)
+		mu.Lock()
+		defer mu.Unlock()
+		require.GreaterOrEqual(t, len(requests), 2, "The probe should be followed by the backfill")
+		assert.Equal(t, "1", requests[0], "The probe should fetch a single delegation")
+		assert.Equal(t, "100", requests[1], "Backfill should fetch full chunks after the probe")
 	})
 }
 
-// TestServiceEventEmission tests observability and event emission
 func TestServiceEventEmission(t *testing.T) {
 	t.Parallel()
 
@@ -177,6 +1333,58 @@ func TestServiceEventEmission(t *testing.T) {
 		assertBackfillDoneEvent(t, events.done, 1)
 	})
 
+	t.Run("it reports a cold start when nothing is stored", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations()
+		defer server.Close()
+
+		store := &reconcilingStore{mockStore: storeWithCheckpoint(0), maxID: 0}
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, store)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.True(t, events.started.ColdStart, "An empty store should be reported as a cold start")
+	})
+
+	t.Run("it reports a resume when delegations are stored", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations()
+		defer server.Close()
+
+		store := &reconcilingStore{mockStore: storeWithCheckpoint(0), maxID: 7}
+		client := tzkt.NewClient(http.DefaultClient, server.URL)
+		svc := scraper.NewService(client, store)
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.False(t, events.started.ColdStart, "Stored delegations should not be reported as a cold start")
+	})
+
+	t.Run("it reports a resume from a non-zero checkpoint", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithDelegations()
+		defer server.Close()
+
+		svc := scraperWithChunkSize(1)(server, storeWithCheckpoint(3))
+
+		// Act
+		events := runBackfillCapturingEvents(t, svc)
+
+		// Assert
+		assert.False(t, events.started.ColdStart, "A stored checkpoint should not be reported as a cold start")
+	})
+
 	t.Run("it emits polling lifecycle events", func(t *testing.T) {
 		t.Parallel()
 
@@ -211,6 +1419,25 @@ func TestServiceEventEmission(t *testing.T) {
 		// Assert
 		assertShutdownEventOccurred(t, shutdown)
 	})
+
+	t.Run("it ends with a terminal event when cancelled mid-backfill", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server := apiWithEndlessDelegations()
+		defer server.Close()
+
+		const runs = 100
+		for i := range runs {
+			svc := scraperWithChunkSize(1)(server, storeWithCheckpoint(0))
+
+			// Act
+			received := runBackfillCancellingAfter(t, svc, i%5+1)
+
+			// Assert
+			assertLastEventIsTerminal(t, received)
+		}
+	})
 }
 
 // Test data helpers
@@ -276,6 +1503,133 @@ func apiWithPollingErrors() *httptest.Server {
 	}))
 }
 
+// apiWithFailingPolls ends backfill, then fails each poll whose entry in failed is true
+// and answers the others with an empty page
+func apiWithFailingPolls(failed ...bool) *httptest.Server {
+	callCount := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		poll := callCount - 1 // the first call ends backfill
+		callCount++
+		if poll >= 0 && poll < len(failed) && failed[poll] {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "polling error"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(emptyResponse()))
+	}))
+}
+
+// apiRateLimitingFirstPoll ends backfill, answers the first poll with 429 and the given
+// Retry-After, and every later one with an empty page
+func apiRateLimitingFirstPoll(retryAfter string) *httptest.Server {
+	var callCount atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if callCount.Add(1) == 2 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(emptyResponse()))
+	}))
+}
+
+func apiWithEndlessDelegations() *httptest.Server {
+	var callCount atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pollWithDelegation(callCount.Add(1))))
+	}))
+}
+
+// apiWithFilterableDelegations serves the given ids honouring id.gt, id.lt, sort.desc and limit
+func apiWithFilterableDelegations(ids ...int64) *httptest.Server {
+	server, _ := apiWithGrowingDelegations(ids...)
+	return server
+}
+
+// apiWithGrowingDelegations serves the given IDs honouring id.gt, id.lt, sort.desc and limit,
+// and returns a function adding IDs while the service runs
+func apiWithGrowingDelegations(initial ...int64) (*httptest.Server, func(ids ...int64)) {
+	var mu sync.Mutex
+	ids := slices.Clone(initial)
+	addDelegations := func(more ...int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		ids = append(ids, more...)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids := slices.Clone(ids)
+		mu.Unlock()
+
+		query := r.URL.Query()
+		gt, _ := strconv.ParseInt(query.Get("id.gt"), 10, 64)
+		lt, err := strconv.ParseInt(query.Get("id.lt"), 10, 64)
+		if err != nil {
+			lt = math.MaxInt64
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		matching := make([]int64, 0, len(ids))
+		for _, id := range ids {
+			if id > gt && id < lt {
+				matching = append(matching, id)
+			}
+		}
+		if query.Get("sort.desc") == "id" {
+			slices.Reverse(matching)
+		}
+		if limit > 0 && len(matching) > limit {
+			matching = matching[:limit]
+		}
+
+		items := make([]string, 0, len(matching))
+		for _, id := range matching {
+			items = append(items, fmt.Sprintf(`{"id":%d,"timestamp":"2024-01-01T00:00:00Z","amount":1000000,"sender":{"address":"tz1%03d"},"level":%d}`,
+				id, id, 100+id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	return server, addDelegations
+}
+
+// apiWithTimestampedDelegations serves the given delegations in ID order honouring id.gt,
+// timestamp.ge and limit, and reports the checkpoint filter of every request
+func apiWithTimestampedDelegations(delegations ...tzkt.Delegation) (*httptest.Server, <-chan string) {
+	filters := make(chan string, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var (
+			gt    int64 = math.MinInt64
+			since time.Time
+		)
+		if v := query.Get("id.gt"); v != "" {
+			gt, _ = strconv.ParseInt(v, 10, 64)
+			filters <- "id.gt=" + v
+		}
+		if v := query.Get("timestamp.ge"); v != "" {
+			since, _ = time.Parse(time.RFC3339, v)
+			filters <- "timestamp.ge=" + v
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		items := make([]string, 0, len(delegations))
+		for _, d := range delegations {
+			if d.ID > gt && !d.Timestamp.Before(since) && (limit == 0 || len(items) < limit) {
+				items = append(items, fmt.Sprintf(`{"id":%d,"timestamp":"%s","amount":%d,"sender":{"address":"%s"},"level":%d}`,
+					d.ID, d.Timestamp.Format(time.RFC3339), d.Amount, d.Sender.Address, d.Level))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	return server, filters
+}
+
 func apiReturningError() *httptest.Server {
 	return createErrorServer()
 }
@@ -293,6 +1647,30 @@ func delegation(id int64) tzkt.Delegation {
 	return d
 }
 
+// delegationAt builds a delegation whose timestamp is the given minute, so IDs can share one
+func delegationAt(id int64, minute int) tzkt.Delegation {
+	d := delegation(id)
+	d.Timestamp = time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC)
+	return d
+}
+
+func timestampStoreCapturingBatches(lastID int64, lastTimestamp time.Time) (chan []scraper.Delegation, *timestampStore) {
+	savedBatchesCh := make(chan []scraper.Delegation, 10)
+	store := createTestStore(lastID, func(ctx context.Context, batch []scraper.Delegation) error {
+		savedBatchesCh <- batch
+		return nil
+	})
+	return savedBatchesCh, &timestampStore{mockStore: store, lastTimestamp: lastTimestamp}
+}
+
+func scraperWithTimestampCheckpoint(server *httptest.Server, store *timestampStore) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(3),
+		scraper.WithTimestampCheckpoint(true),
+	)
+}
+
 func storeCapturingBatches() (chan []scraper.Delegation, *mockStore) {
 	savedBatchesCh := make(chan []scraper.Delegation, 10)
 	store := createTestStore(0, func(ctx context.Context, batch []scraper.Delegation) error {
@@ -313,6 +1691,74 @@ func scraperWithChunkSize(chunkSize uint64) func(*httptest.Server, *mockStore) *
 	}
 }
 
+// storeWithExistingUpTo returns a store reporting IDs up to storedUpTo as already stored
+func storeWithExistingUpTo(storedUpTo int64) (chan []scraper.Delegation, *existenceStore) {
+	savedBatchesCh, store := storeCapturingBatches()
+	return savedBatchesCh, &existenceStore{
+		reconcilingStore: &reconcilingStore{mockStore: store},
+		storedUpTo:       storedUpTo,
+	}
+}
+
+func scraperSkippingExisting(server *httptest.Server, store scraper.Store) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(3),
+		scraper.WithSkipExistingBatches(true),
+	)
+}
+
+func scraperWithMaxBackfillRecords(server *httptest.Server, store *mockStore, dir scraper.Direction, maxRecords uint64) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(3),
+		scraper.WithBackfillDirection(dir),
+		scraper.WithMaxBackfillRecords(maxRecords),
+	)
+}
+
+func scraperWithBackfillDirection(server *httptest.Server, store *mockStore, dir scraper.Direction) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(1),
+		scraper.WithBackfillDirection(dir),
+	)
+}
+
+// scraperWithConfirmationLag reads delegations from server while the head stays at head
+func scraperWithConfirmationLag(server *httptest.Server, store *mockStore, lag, head int64) *scraper.Service {
+	return scraper.NewService(headAt(server, head), store, scraper.WithConfirmationLag(lag))
+}
+
+// headAt reads delegations from server and reports the given head levels in turn,
+// repeating the last one
+func headAt(server *httptest.Server, heads ...int64) *headLevelClient {
+	return &headLevelClient{
+		Client: tzkt.NewClient(http.DefaultClient, server.URL),
+		heads:  heads,
+	}
+}
+
+func clockControlledConcurrentPolling(server *httptest.Server, store scraper.Store, opts ...scraper.Option) (*fakeClock, *scraper.Service) {
+	clock := createTestClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, store, append([]scraper.Option{
+		scraper.WithChunkSize(1),
+		scraper.WithClock(clock),
+		scraper.WithConcurrentPolling(true),
+		scraper.WithMaxPollCycles(2),
+	}, opts...)...)
+	return clock, svc
+}
+
+func scraperInDryRun(server *httptest.Server, store *mockStore) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(1),
+		scraper.WithDryRun(true),
+	)
+}
+
 func clockControlledPolling(server *httptest.Server, store *mockStore) (*fakeClock, *scraper.Service) {
 	clock := createTestClock()
 	client := tzkt.NewClient(http.DefaultClient, server.URL)
@@ -324,6 +1770,67 @@ func clockControlledPolling(server *httptest.Server, store *mockStore) (*fakeClo
 	return clock, svc
 }
 
+// clockControlledPollingWithIdleBackoff polls every 10s, backing off while idle
+func clockControlledPollingWithIdleBackoff(server *httptest.Server, store *mockStore, maxInterval time.Duration, factor float64) (*fakeClock, *scraper.Service) {
+	clock := createTestClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, store,
+		scraper.WithClock(clock),
+		scraper.WithPollInterval(10*time.Second),
+		scraper.WithChunkSize(1),
+		scraper.WithIdleBackoff(maxInterval, factor),
+	)
+	return clock, svc
+}
+
+func clockControlledPollingWithMaxCycles(server *httptest.Server, store *mockStore, maxCycles int) (*fakeClock, *scraper.Service) {
+	clock := createTestClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, store,
+		scraper.WithClock(clock),
+		scraper.WithPollInterval(1*time.Millisecond),
+		scraper.WithChunkSize(1),
+		scraper.WithMaxPollCycles(maxCycles),
+	)
+	return clock, svc
+}
+
+func clockControlledPollingWithMaxErrors(server *httptest.Server, store *mockStore, maxErrors, maxCycles int) (*fakeClock, *scraper.Service) {
+	clock := createTestClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, store,
+		scraper.WithClock(clock),
+		scraper.WithPollInterval(1*time.Millisecond),
+		scraper.WithChunkSize(1),
+		scraper.WithMaxConsecutivePollErrors(maxErrors),
+		scraper.WithMaxPollCycles(maxCycles),
+	)
+	return clock, svc
+}
+
+func clockControlledBackfillWithTimeout(server *httptest.Server, store *mockStore, timeout time.Duration) (*fakeClock, *scraper.Service) {
+	clock := createTestClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, store,
+		scraper.WithClock(clock),
+		scraper.WithChunkSize(1),
+		scraper.WithBackfillTimeout(timeout),
+	)
+	return clock, svc
+}
+
+func scraperWithBlockHashEnrichment(server *httptest.Server, store *mockStore, blocks scraper.BlockHashClient) *scraper.Service {
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	return scraper.NewService(client, store,
+		scraper.WithChunkSize(1),
+		scraper.WithBlockHashEnrichment(blocks),
+	)
+}
+
+func blockHashesForLevels(hashes map[int64]string) *fakeBlockHashClient {
+	return &fakeBlockHashClient{hashes: hashes}
+}
+
 // Domain-specific assertions
 
 func assertDelegationsWereSaved(t *testing.T, savedBatchesCh chan []scraper.Delegation, expected []tzkt.Delegation) {
@@ -344,6 +1851,48 @@ func assertDelegationsWereSaved(t *testing.T, savedBatchesCh chan []scraper.Dele
 	}
 }
 
+func assertBlockHashesWereSaved(t *testing.T, savedBatchesCh chan []scraper.Delegation, expected []string) {
+	t.Helper()
+	close(savedBatchesCh)
+
+	var hashes []string
+	for batch := range savedBatchesCh {
+		for _, d := range batch {
+			hashes = append(hashes, d.BlockHash)
+		}
+	}
+
+	assert.Equal(t, expected, hashes, "Saved delegations should carry block hashes looked up by level")
+}
+
+func assertSavedIDsInOrder(t *testing.T, savedBatchesCh chan []scraper.Delegation, expected []int64) {
+	t.Helper()
+	close(savedBatchesCh)
+
+	var ids []int64
+	for batch := range savedBatchesCh {
+		for _, d := range batch {
+			ids = append(ids, d.ID)
+		}
+	}
+
+	assert.Equal(t, expected, ids, "Delegations should be saved in the expected order")
+}
+
+func assertLastEventIsTerminal(t *testing.T, received []scraper.Event) {
+	t.Helper()
+	require.NotEmpty(t, received, "Expected events before shutdown")
+
+	switch last := received[len(received)-1].(type) {
+	case scraper.BackfillError:
+		assert.ErrorIs(t, last.Err, context.Canceled)
+	case scraper.PollingShutdown:
+		assert.ErrorIs(t, last.Reason, context.Canceled)
+	default:
+		t.Errorf("Expected BackfillError or PollingShutdown as the last event, got %T", last)
+	}
+}
+
 func assertCheckpointAdvancedTo(t *testing.T, store *mockStore, expectedID int64) {
 	t.Helper()
 	checkpoint, err := store.LastProcessedID(t.Context())
@@ -362,6 +1911,17 @@ func assertPollFoundDelegations(t *testing.T, cycle scraper.PollingSyncCompleted
 	assert.Greater(t, cycle.CheckpointID, int64(0), "Expected valid checkpoint ID")
 }
 
+// assertNextIntervals verifies the interval each poll cycle scheduled before the next one
+func assertNextIntervals(t *testing.T, cycles []scraper.PollingSyncCompleted, expected ...time.Duration) {
+	t.Helper()
+
+	actual := make([]time.Duration, len(cycles))
+	for i, cycle := range cycles {
+		actual[i] = cycle.NextInterval
+	}
+	assert.Equal(t, expected, actual)
+}
+
 func assertBackfillFailedWithAPIError(t *testing.T, errorCh <-chan error) {
 	t.Helper()
 	backfillError := <-errorCh
@@ -369,6 +1929,39 @@ func assertBackfillFailedWithAPIError(t *testing.T, errorCh <-chan error) {
 	assert.ErrorIs(t, backfillError, scraper.ErrAPIRequestFailed, "Error should be an API request failure")
 }
 
+func assertBackfillFailedWithConversionError(t *testing.T, errorCh <-chan error) {
+	t.Helper()
+	backfillError := <-errorCh
+	require.NotNil(t, backfillError, "Expected backfill to fail with an error")
+	assert.ErrorIs(t, backfillError, scraper.ErrConversionFailed, "Error should be a conversion failure")
+	assert.ErrorIs(t, backfillError, scraper.ErrNegativeAmount, "Error should name the negative amount")
+}
+
+func assertNothingWasSaved(t *testing.T, savedBatchesCh chan []scraper.Delegation) {
+	t.Helper()
+	close(savedBatchesCh)
+
+	var saved int
+	for batch := range savedBatchesCh {
+		saved += len(batch)
+	}
+	assert.Zero(t, saved, "No delegations should be saved from a rejected batch")
+}
+
+func assertBackfillFailedWithTimeout(t *testing.T, errorCh <-chan error) {
+	t.Helper()
+	backfillError := <-errorCh
+	require.NotNil(t, backfillError, "Expected backfill to fail with an error")
+	assert.ErrorIs(t, backfillError, scraper.ErrBackfillTimeout, "Error should be a backfill timeout")
+}
+
+func assertBackfillFailedWithEnrichmentError(t *testing.T, errorCh <-chan error) {
+	t.Helper()
+	backfillError := <-errorCh
+	require.NotNil(t, backfillError, "Expected backfill to fail with an error")
+	assert.ErrorIs(t, backfillError, scraper.ErrEnrichmentFailed, "Error should be an enrichment failure")
+}
+
 func assertPollingFailedWithAPIError(t *testing.T, errorCh <-chan error) {
 	t.Helper()
 	pollingError := <-errorCh
@@ -394,6 +1987,32 @@ func runBackfillUntilComplete(t *testing.T, svc *scraper.Service) <-chan struct{
 	return done
 }
 
+// runBackfillCancellingAfter cancels the service once n events were received
+// and returns every event delivered until the channel closed
+func runBackfillCancellingAfter(t *testing.T, svc *scraper.Service, n int) []scraper.Event {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	events, done := svc.Start(ctx)
+
+	var received []scraper.Event
+	for ev := range events {
+		received = append(received, ev)
+		if len(received) == n {
+			cancel()
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Service did not shut down after the events channel closed")
+	}
+
+	return received
+}
+
 func runBackfillExpectingError(t *testing.T, svc *scraper.Service) <-chan error {
 	t.Helper()
 	ctx, cancel := context.WithCancel(t.Context())
@@ -417,7 +2036,42 @@ func runBackfillExpectingError(t *testing.T, svc *scraper.Service) <-chan error
 	return errorCh
 }
 
+func runBackfillUntilTimeout(t *testing.T, svc *scraper.Service, clock *fakeClock) <-chan error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+
+	events, done := svc.Start(ctx)
+	errorCh := make(chan error, 1)
+
+	subCloser := scraper.NewSubscriber(events,
+		scraper.OnBackfillSyncCompleted(func(e scraper.BackfillSyncCompleted) {
+			// Backfill keeps receiving data; let the timeout elapse after the first batch
+			if e.CheckpointID == 1 {
+				clock.tick <- time.Now()
+			}
+		}),
+		scraper.OnBackfillError(func(e scraper.BackfillError) {
+			errorCh <- e.Err
+			cancel()
+		}),
+	)
+
+	t.Cleanup(func() {
+		subCloser()
+		cancel()
+		<-done
+	})
+
+	return errorCh
+}
+
 func runPollingCycles(t *testing.T, svc *scraper.Service, clock *fakeClock, cycleCount int) []scraper.PollingSyncCompleted {
+	t.Helper()
+	return runPollingTicksUntilCycles(t, svc, clock, cycleCount, cycleCount)
+}
+
+// runPollingTicksUntilCycles drives tickCount polls and collects the first cycleCount polling events
+func runPollingTicksUntilCycles(t *testing.T, svc *scraper.Service, clock *fakeClock, tickCount, cycleCount int) []scraper.PollingSyncCompleted {
 	t.Helper()
 	ctx, cancel := context.WithCancel(t.Context())
 
@@ -444,7 +2098,7 @@ func runPollingCycles(t *testing.T, svc *scraper.Service, clock *fakeClock, cycl
 	})
 
 	// Drive polling ticks
-	for range cycleCount {
+	for range tickCount {
 		clock.tick <- time.Now()
 	}
 
@@ -457,6 +2111,41 @@ func runPollingCycles(t *testing.T, svc *scraper.Service, clock *fakeClock, cycl
 	return cycles
 }
 
+// runTriggeredPolls starts the service and calls TriggerPoll for each of cycleCount
+// polling events, never advancing the clock
+func runTriggeredPolls(t *testing.T, svc *scraper.Service, cycleCount int) []scraper.PollingSyncCompleted {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+
+	events, done := svc.Start(ctx)
+
+	pollCyclesCh := make(chan scraper.PollingSyncCompleted, cycleCount)
+	subCloser := scraper.NewSubscriber(events,
+		scraper.OnPollingSyncCompleted(func(e scraper.PollingSyncCompleted) {
+			pollCyclesCh <- e
+		}),
+	)
+
+	t.Cleanup(func() {
+		subCloser()
+		cancel()
+		<-done
+	})
+
+	cycles := make([]scraper.PollingSyncCompleted, 0, cycleCount)
+	for range cycleCount {
+		svc.TriggerPoll()
+		select {
+		case cycle := <-pollCyclesCh:
+			cycles = append(cycles, cycle)
+		case <-time.After(time.Second):
+			t.Fatal("Triggered poll did not run")
+		}
+	}
+
+	return cycles
+}
+
 func runPollingExpectingError(t *testing.T, svc *scraper.Service, clock *fakeClock) <-chan error {
 	t.Helper()
 	ctx, cancel := context.WithCancel(t.Context())
@@ -512,6 +2201,18 @@ func createTestStore(lastID int64, onSave func(ctx context.Context, batch []scra
 
 // Mock implementations
 
+// hangingClient blocks every request until its context is done, like an API that stopped answering
+type hangingClient struct {
+	called chan struct{}
+	once   sync.Once
+}
+
+func (c *hangingClient) GetDelegations(ctx context.Context, _ tzkt.DelegationsRequest) ([]tzkt.Delegation, error) {
+	c.once.Do(func() { close(c.called) })
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
 // fakeClock implements Clock interface for deterministic testing
 type fakeClock struct {
 	tick chan time.Time
@@ -525,6 +2226,136 @@ func (f *fakeClock) Now() time.Time {
 	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 }
 
+// durationClock hands out one tick channel per requested duration, so timers of
+// different lengths (e.g. polls and heartbeats) can be fired independently
+type durationClock struct {
+	mu    sync.Mutex
+	ticks map[time.Duration]chan time.Time
+}
+
+func newDurationClock() *durationClock {
+	return &durationClock{ticks: make(map[time.Duration]chan time.Time)}
+}
+
+func (c *durationClock) After(d time.Duration) <-chan time.Time {
+	return c.channel(d)
+}
+
+func (c *durationClock) Now() time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// fire delivers at to the next timer of duration d, waiting until one is started
+func (c *durationClock) fire(d time.Duration, at time.Time) {
+	c.channel(d) <- at
+}
+
+// clockControlledCatchUp polls every 10s in chunks of 2 for maxCycles cycles
+func clockControlledCatchUp(server *httptest.Server, burst bool, maxCycles int) (*durationClock, *scraper.Service) {
+	clock := newDurationClock()
+	client := tzkt.NewClient(http.DefaultClient, server.URL)
+	svc := scraper.NewService(client, storeWithCheckpoint(0),
+		scraper.WithClock(clock),
+		scraper.WithPollInterval(10*time.Second),
+		scraper.WithChunkSize(2),
+		scraper.WithCatchUpBurst(burst),
+		scraper.WithMaxPollCycles(maxCycles),
+	)
+	return clock, svc
+}
+
+// startCapturingPollCycles starts svc and returns a channel closed once backfill is done
+// and a function that waits for the service to stop and returns its poll cycles
+func startCapturingPollCycles(t *testing.T, svc *scraper.Service) (<-chan struct{}, func() []scraper.PollingSyncCompleted) {
+	t.Helper()
+
+	events, done := svc.Start(t.Context())
+	backfilled := make(chan struct{})
+	var cycles []scraper.PollingSyncCompleted
+	subCloser := scraper.NewSubscriber(events,
+		scraper.OnBackfillDone(func(scraper.BackfillDone) { close(backfilled) }),
+		scraper.OnPollingSyncCompleted(func(e scraper.PollingSyncCompleted) { cycles = append(cycles, e) }),
+	)
+
+	return backfilled, func() []scraper.PollingSyncCompleted {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Service did not stop after its last poll cycle")
+		}
+		subCloser()
+		return cycles
+	}
+}
+
+func fetchedPerCycle(cycles []scraper.PollingSyncCompleted) []int {
+	fetched := make([]int, len(cycles))
+	for i, cycle := range cycles {
+		fetched[i] = cycle.Fetched
+	}
+	return fetched
+}
+
+// fireWithin fires the next timer of duration d, failing the test when the service does
+// not start one within a second
+func fireWithin(t *testing.T, clock *durationClock, d time.Duration, msg string) {
+	t.Helper()
+
+	select {
+	case clock.channel(d) <- time.Now():
+	case <-time.After(time.Second):
+		t.Fatalf("No %v timer was started: %s", d, msg)
+	}
+}
+
+func (c *durationClock) channel(d time.Duration) chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.ticks[d]; !ok {
+		c.ticks[d] = make(chan time.Time)
+	}
+	return c.ticks[d]
+}
+
+// fakeBlockHashClient implements BlockHashClient with canned hashes
+type fakeBlockHashClient struct {
+	hashes map[int64]string
+	err    error
+}
+
+func (f *fakeBlockHashClient) GetBlockHashes(_ context.Context, _ []int64) (map[int64]string, error) {
+	return f.hashes, f.err
+}
+
+// fakeClient implements Client returning a canned batch once, then nothing
+type fakeClient struct {
+	delegations []tzkt.Delegation
+}
+
+func (f *fakeClient) GetDelegations(_ context.Context, _ tzkt.DelegationsRequest) ([]tzkt.Delegation, error) {
+	batch := f.delegations
+	f.delegations = nil
+	return batch, nil
+}
+
+// headLevelClient adds HeadLevelClient to a real client, reporting canned head levels
+type headLevelClient struct {
+	*tzkt.Client
+	heads []int64
+	err   error
+}
+
+func (c *headLevelClient) GetHeadLevel(context.Context) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	head := c.heads[0]
+	if len(c.heads) > 1 {
+		c.heads = c.heads[1:]
+	}
+	return head, nil
+}
+
 // mockStore implements Store interface for testing
 type mockStore struct {
 	lastID int64
@@ -535,26 +2366,129 @@ func (m *mockStore) LastProcessedID(ctx context.Context) (int64, error) {
 	return m.lastID, nil
 }
 
-func (m *mockStore) SaveBatch(ctx context.Context, batch []scraper.Delegation) error {
+func (m *mockStore) SaveBatch(ctx context.Context, batch []scraper.Delegation) (int64, error) {
 	if m.onSave != nil {
-		err := m.onSave(ctx, batch)
-		if err == nil && len(batch) > 0 {
-			m.lastID = batch[len(batch)-1].ID
+		if err := m.onSave(ctx, batch); err != nil {
+			return 0, err
 		}
-		return err
 	}
 
 	if len(batch) == 0 {
-		return nil
+		return m.lastID, nil
 	}
 
-	// simulate checkpoint update to highest ID in batch
-	newCheckpoint := batch[len(batch)-1].ID
-	m.lastID = newCheckpoint
+	// simulate checkpoint update to highest ID in batch; it never moves backwards
+	m.lastID = max(m.lastID, batch[len(batch)-1].ID)
+
+	return m.lastID, nil
+}
+
+// reconcilingStore adds CheckpointReconciler to mockStore
+type reconcilingStore struct {
+	*mockStore
+	maxID int64
+}
+
+func (r *reconcilingStore) MaxDelegationID(_ context.Context) (int64, error) {
+	return r.maxID, nil
+}
 
+func (r *reconcilingStore) AdvanceCheckpoint(_ context.Context, id int64) error {
+	r.lastID = max(r.lastID, id)
 	return nil
 }
 
+// existenceStore adds ExistenceChecker to reconcilingStore, treating IDs up to storedUpTo as stored
+type existenceStore struct {
+	*reconcilingStore
+	storedUpTo int64
+}
+
+func (s *existenceStore) CountExisting(_ context.Context, ids []int64) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		if id <= s.storedUpTo {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// aheadStore simulates another writer: every save leaves the checkpoint at persistedID or above
+type aheadStore struct {
+	*mockStore
+	persistedID int64
+}
+
+func (s *aheadStore) SaveBatch(ctx context.Context, batch []scraper.Delegation) (int64, error) {
+	if _, err := s.mockStore.SaveBatch(ctx, batch); err != nil {
+		return 0, err
+	}
+	s.lastID = max(s.lastID, s.persistedID)
+	return s.lastID, nil
+}
+
+// concurrentStore is a store safe for the backfill and polling loops saving at once.
+// It records IDs in save order; beforeSave can hold or fail a save.
+type concurrentStore struct {
+	beforeSave func(ctx context.Context, batch []scraper.Delegation) error
+
+	mu     sync.Mutex
+	lastID int64
+	saved  []int64
+}
+
+func (s *concurrentStore) LastProcessedID(context.Context) (int64, error) {
+	return s.checkpoint(), nil
+}
+
+func (s *concurrentStore) SaveBatch(ctx context.Context, batch []scraper.Delegation) (int64, error) {
+	if s.beforeSave != nil {
+		if err := s.beforeSave(ctx, batch); err != nil {
+			return 0, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range batch {
+		s.saved = append(s.saved, d.ID)
+	}
+	s.lastID = max(s.lastID, batch[len(batch)-1].ID)
+	return s.lastID, nil
+}
+
+func (s *concurrentStore) checkpoint() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastID
+}
+
+func (s *concurrentStore) savedIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.saved)
+}
+
+// timestampStore adds TimestampCheckpointer to mockStore
+type timestampStore struct {
+	*mockStore
+	lastTimestamp time.Time
+}
+
+func (s *timestampStore) LastProcessedTimestamp(_ context.Context) (time.Time, error) {
+	return s.lastTimestamp, nil
+}
+
+func (s *timestampStore) SaveBatch(ctx context.Context, batch []scraper.Delegation) (int64, error) {
+	for _, d := range batch {
+		if d.Timestamp.After(s.lastTimestamp) {
+			s.lastTimestamp = d.Timestamp
+		}
+	}
+	return s.mockStore.SaveBatch(ctx, batch)
+}
+
 // Event capture types for testing
 
 type capturedBackfillEvents struct {
@@ -593,6 +2527,7 @@ func runBackfillCapturingEvents(t *testing.T, svc *scraper.Service) capturedBack
 	})
 
 	<-done
+	subCloser() // wait for every handler before closing the channel they send to
 
 	// Collect all sync completed events
 	close(backfillSyncCompletedCh)
@@ -608,6 +2543,95 @@ func runBackfillCapturingEvents(t *testing.T, svc *scraper.Service) capturedBack
 	}
 }
 
+// runConcurrentPollingCapturingEvents records every event until the service stops on its
+// own, ticking the clock once polling starts and again once backfill is done.
+// beforeFirstPoll, if set, runs right before the first tick.
+func runConcurrentPollingCapturingEvents(t *testing.T, svc *scraper.Service, clock *fakeClock, beforeFirstPoll func()) []scraper.Event {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events, done := svc.Start(ctx)
+
+	var received []scraper.Event
+	for ev := range events {
+		received = append(received, ev)
+		switch ev.(type) {
+		case scraper.PollingStarted:
+			if beforeFirstPoll != nil {
+				beforeFirstPoll()
+			}
+			clock.tick <- clock.Now()
+		case scraper.BackfillDone:
+			clock.tick <- clock.Now()
+		}
+	}
+	<-done
+
+	require.NoError(t, ctx.Err(), "The service should stop on its own")
+	return received
+}
+
+type capturedHeartbeats struct {
+	heartbeats   chan scraper.Heartbeat
+	backfillDone chan struct{}
+}
+
+// startCapturingHeartbeats runs the service until the test ends, forwarding its heartbeats
+func startCapturingHeartbeats(t *testing.T, svc *scraper.Service) capturedHeartbeats {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+
+	events, done := svc.Start(ctx)
+	captured := capturedHeartbeats{
+		heartbeats:   make(chan scraper.Heartbeat, 10),
+		backfillDone: make(chan struct{}),
+	}
+	subCloser := scraper.NewSubscriber(events,
+		scraper.OnHeartbeat(func(e scraper.Heartbeat) { captured.heartbeats <- e }),
+		scraper.OnBackfillDone(func(scraper.BackfillDone) { close(captured.backfillDone) }),
+	)
+
+	t.Cleanup(func() {
+		cancel()
+		subCloser()
+		<-done
+	})
+	return captured
+}
+
+func nextHeartbeat(t *testing.T, captured capturedHeartbeats) scraper.Heartbeat {
+	t.Helper()
+
+	select {
+	case beat := <-captured.heartbeats:
+		return beat
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no heartbeat emitted")
+		return scraper.Heartbeat{}
+	}
+}
+
+// eventsOfType returns the events of type E, in order
+func eventsOfType[E scraper.Event](events []scraper.Event) []E {
+	var matching []E
+	for _, ev := range events {
+		if e, ok := ev.(E); ok {
+			matching = append(matching, e)
+		}
+	}
+	return matching
+}
+
+// totalPolled sums the delegations fetched by every polling cycle
+func totalPolled(events []scraper.Event) int {
+	total := 0
+	for _, cycle := range eventsOfType[scraper.PollingSyncCompleted](events) {
+		total += cycle.Fetched
+	}
+	return total
+}
+
 func runPollingCapturingEvents(t *testing.T, svc *scraper.Service, clock *fakeClock) capturedPollingEvents {
 	t.Helper()
 	ctx, cancel := context.WithCancel(t.Context())
@@ -640,6 +2664,40 @@ func runPollingCapturingEvents(t *testing.T, svc *scraper.Service, clock *fakeCl
 	}
 }
 
+// stoppedPolling captures the polling events of a service that stopped by itself
+type stoppedPolling struct {
+	cycles   []scraper.PollingSyncCompleted
+	errors   int
+	shutdown scraper.PollingShutdown
+}
+
+// runPollingUntilStopped drives tickCount poll ticks without ever cancelling and waits for the service to stop on its own
+func runPollingUntilStopped(t *testing.T, svc *scraper.Service, clock *fakeClock, tickCount int) stoppedPolling {
+	t.Helper()
+
+	events, done := svc.Start(t.Context())
+
+	var stopped stoppedPolling
+	subCloser := scraper.NewSubscriber(events,
+		scraper.OnPollingSyncCompleted(func(e scraper.PollingSyncCompleted) { stopped.cycles = append(stopped.cycles, e) }),
+		scraper.OnPollingError(func(scraper.PollingError) { stopped.errors++ }),
+		scraper.OnPollingShutdown(func(e scraper.PollingShutdown) { stopped.shutdown = e }),
+	)
+
+	for range tickCount {
+		clock.tick <- time.Now()
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Service did not stop on its own")
+	}
+	subCloser()
+
+	return stopped
+}
+
 func runPollingCapturingShutdown(t *testing.T, svc *scraper.Service, clock *fakeClock) scraper.PollingShutdown {
 	t.Helper()
 	ctx, cancel := context.WithCancel(t.Context())
@@ -0,0 +1,126 @@
+package scrapertest
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+)
+
+// API is a fake Tzkt delegations endpoint. Each run starts in backfill: requests are
+// answered from the added delegations, filtered, sorted and limited like the real API,
+// until one comes back empty. Every later request is a poll and gets the next queued
+// poll response, then empty ones.
+type API struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	delegations []tzkt.Delegation
+	polls       [][]tzkt.Delegation
+	polling     bool
+	requests    int
+}
+
+// NewAPI starts the fake API; Close it when done
+func NewAPI() *API {
+	a := &API{}
+	a.server = httptest.NewServer(http.HandlerFunc(a.serveDelegations))
+	return a
+}
+
+// AddDelegations adds delegations served during backfill
+func (a *API) AddDelegations(delegations ...tzkt.Delegation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.delegations = append(a.delegations, delegations...)
+	slices.SortFunc(a.delegations, func(x, y tzkt.Delegation) int { return cmp.Compare(x.ID, y.ID) })
+}
+
+// AddPollResponses queues one response per poll, in order
+func (a *API) AddPollResponses(polls ...[]tzkt.Delegation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.polls = append(a.polls, polls...)
+}
+
+// Rewind goes back to serving backfill requests, for a new run
+func (a *API) Rewind() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.polling = false
+}
+
+// Requests returns how many requests the API answered
+func (a *API) Requests() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests
+}
+
+// URL returns the base URL to pass to tzkt.NewClient
+func (a *API) URL() string {
+	return a.server.URL
+}
+
+// Client returns an HTTP client for the API
+func (a *API) Client() *http.Client {
+	return a.server.Client()
+}
+
+// Close shuts the API down
+func (a *API) Close() {
+	a.server.Close()
+}
+
+func (a *API) serveDelegations(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.requests++
+	var page []tzkt.Delegation
+	if a.polling {
+		if len(a.polls) > 0 {
+			page, a.polls = a.polls[0], a.polls[1:]
+		}
+	} else {
+		page = a.backfillPage(r)
+		a.polling = len(page) == 0
+	}
+	a.mu.Unlock()
+
+	if page == nil {
+		page = []tzkt.Delegation{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// backfillPage applies the id.gt, id.lt, sort.desc and limit parameters the scraper sends
+func (a *API) backfillPage(r *http.Request) []tzkt.Delegation {
+	q := r.URL.Query()
+	gt, hasGT := queryInt(q.Get("id.gt"))
+	lt, hasLT := queryInt(q.Get("id.lt"))
+
+	var page []tzkt.Delegation
+	for _, d := range a.delegations {
+		if (hasGT && d.ID <= gt) || (hasLT && d.ID >= lt) {
+			continue
+		}
+		page = append(page, d)
+	}
+	if q.Get("sort.desc") == "id" {
+		slices.Reverse(page)
+	}
+	if limit, ok := queryInt(q.Get("limit")); ok && int64(len(page)) > limit {
+		page = page[:limit]
+	}
+	return page
+}
+
+func queryInt(s string) (int64, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,24 @@
+package scrapertest
+
+import "time"
+
+// Clock is a manual scraper.Clock: its time stands still and every After fires only
+// when the harness ticks it, so polling advances one cycle per tick
+type Clock struct {
+	ticks chan time.Time
+}
+
+// NewClock creates a clock that fires only when ticked
+func NewClock() *Clock {
+	return &Clock{ticks: make(chan time.Time)}
+}
+
+// After returns the channel ticked by the harness, whatever the duration
+func (c *Clock) After(time.Duration) <-chan time.Time {
+	return c.ticks
+}
+
+// Now returns a fixed instant
+func (c *Clock) Now() time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,29 @@
+package scrapertest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+)
+
+// Delegation builds a valid API delegation whose fields are derived from id
+func Delegation(id int64) tzkt.Delegation {
+	d := tzkt.Delegation{
+		ID:        id,
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(id) * time.Minute),
+		Amount:    1000000 + id*100000,
+		Level:     100 + id,
+	}
+	d.Sender.Address = fmt.Sprintf("tz1%03d", id)
+	return d
+}
+
+// Delegations builds a delegation for each ID from first to last, inclusive
+func Delegations(first, last int64) []tzkt.Delegation {
+	var delegations []tzkt.Delegation
+	for id := first; id <= last; id++ {
+		delegations = append(delegations, Delegation(id))
+	}
+	return delegations
+}
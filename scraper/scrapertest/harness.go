@@ -0,0 +1,190 @@
+// Package scrapertest runs a scraper.Service against a fake Tzkt API, an in-memory store
+// and a manual clock, so integrations embedding the scraper can be tested without
+// rebuilding that plumbing. The harness only drives the service and records what
+// happened; assertions are left to the caller.
+//
+//	h := scrapertest.NewHarness(t).
+//		WithDelegations(scrapertest.Delegation(1), scrapertest.Delegation(2)).
+//		RunBackfill()
+//	events, saved := h.Events(), h.Saved()
+package scrapertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+	"github.com/screwyprof/delegator/scraper"
+)
+
+// DefaultTimeout bounds a single run, so a service that never reaches the expected
+// event fails the test instead of hanging it
+const DefaultTimeout = 5 * time.Second
+
+// Harness builds and runs a scraper.Service. Configure it with the With methods, then
+// call RunBackfill or RunPolls; the store and API state carry over between runs.
+type Harness struct {
+	t       testing.TB
+	api     *API
+	store   *Store
+	opts    []scraper.Option
+	timeout time.Duration
+	events  []scraper.Event
+}
+
+// NewHarness creates a harness with an empty API and store, cleaned up with t
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	api := NewAPI()
+	t.Cleanup(api.Close)
+
+	return &Harness{
+		t:       t,
+		api:     api,
+		store:   NewStore(0),
+		timeout: DefaultTimeout,
+	}
+}
+
+// WithDelegations adds delegations the API serves during backfill
+func (h *Harness) WithDelegations(delegations ...tzkt.Delegation) *Harness {
+	h.api.AddDelegations(delegations...)
+	return h
+}
+
+// WithPollResponses queues one API response per poll, in order; polls past the
+// last response find nothing
+func (h *Harness) WithPollResponses(polls ...[]tzkt.Delegation) *Harness {
+	h.api.AddPollResponses(polls...)
+	return h
+}
+
+// WithCheckpoint sets the checkpoint the store starts from
+func (h *Harness) WithCheckpoint(id int64) *Harness {
+	h.store.SetCheckpoint(id)
+	return h
+}
+
+// WithOptions adds service options, applied after the harness clock.
+// Replacing the clock with scraper.WithClock stops RunPolls from driving the polls.
+func (h *Harness) WithOptions(opts ...scraper.Option) *Harness {
+	h.opts = append(h.opts, opts...)
+	return h
+}
+
+// WithTimeout overrides DefaultTimeout for each run
+func (h *Harness) WithTimeout(d time.Duration) *Harness {
+	h.timeout = d
+	return h
+}
+
+// RunBackfill runs the service until the backfill finishes or fails, then stops it.
+// Events records everything up to and including BackfillDone or BackfillError.
+func (h *Harness) RunBackfill() *Harness {
+	h.t.Helper()
+
+	h.run(nil, nil, func(ev scraper.Event) bool {
+		switch ev.(type) {
+		case scraper.BackfillDone, scraper.BackfillError:
+			return true
+		}
+		return false
+	})
+	return h
+}
+
+// RunPolls runs the backfill, then n polling cycles, and lets the service shut down.
+// Events records everything up to the final PollingShutdown, or the BackfillError
+// that prevented polling. n must be positive.
+func (h *Harness) RunPolls(n int) *Harness {
+	h.t.Helper()
+
+	if n < 1 {
+		h.t.Fatalf("scrapertest: RunPolls needs at least one cycle, got %d", n)
+	}
+
+	opts := []scraper.Option{scraper.WithMaxPollCycles(n)}
+	h.run(opts, func(ctx context.Context, clock *Clock) {
+		for range n {
+			select {
+			case clock.ticks <- clock.Now():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, func(ev scraper.Event) bool {
+		switch ev.(type) {
+		case scraper.PollingShutdown, scraper.BackfillError:
+			return true
+		}
+		return false
+	})
+	return h
+}
+
+// Events returns the events of the last run, in the order they were emitted
+func (h *Harness) Events() []scraper.Event {
+	return h.events
+}
+
+// Saved returns every delegation saved so far, across runs, in save order
+func (h *Harness) Saved() []scraper.Delegation {
+	return h.store.Saved()
+}
+
+// Checkpoint returns the store's current checkpoint
+func (h *Harness) Checkpoint() int64 {
+	return h.store.Checkpoint()
+}
+
+// Store returns the in-memory store the service writes to
+func (h *Harness) Store() *Store {
+	return h.store
+}
+
+// API returns the fake Tzkt API the service reads from
+func (h *Harness) API() *API {
+	return h.api
+}
+
+// run starts a fresh service, calls drive once the backfill is done, and records
+// events until one satisfies until. The service is then stopped and drained.
+func (h *Harness) run(extra []scraper.Option, drive func(context.Context, *Clock), until func(scraper.Event) bool) {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(h.t.Context(), h.timeout)
+	defer cancel()
+
+	clock := NewClock()
+	opts := append([]scraper.Option{scraper.WithClock(clock)}, h.opts...)
+	opts = append(opts, extra...)
+
+	h.api.Rewind()
+	svc := scraper.NewService(tzkt.NewClient(h.api.Client(), h.api.URL()), h.store, opts...)
+	events, done := svc.Start(ctx)
+
+	h.events = nil
+	for ev := range events {
+		h.events = append(h.events, ev)
+		if _, ok := ev.(scraper.BackfillDone); ok && drive != nil {
+			go drive(ctx, clock)
+		}
+		if until(ev) {
+			break
+		}
+	}
+	// Checked before stopping the service: a run cut short by the timeout also ends in an event
+	err := ctx.Err()
+
+	cancel()
+	for range events {
+		// Drain so the service can exit
+	}
+	<-done
+
+	if err != nil {
+		h.t.Fatalf("scrapertest: run did not finish within %v: %v", h.timeout, err)
+	}
+}
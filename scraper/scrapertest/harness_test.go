@@ -0,0 +1,189 @@
+package scrapertest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/tzkt"
+	"github.com/screwyprof/delegator/scraper"
+	"github.com/screwyprof/delegator/scraper/scrapertest"
+)
+
+func TestHarnessBackfill(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it backfills every delegation the API serves", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		h := scrapertest.NewHarness(t).
+			WithDelegations(scrapertest.Delegations(1, 3)...).
+			WithOptions(scraper.WithChunkSize(2))
+
+		// Act
+		h.RunBackfill()
+
+		// Assert
+		assert.Equal(t, []int64{1, 2, 3}, savedIDs(h.Saved()))
+		assert.Equal(t, int64(3), h.Checkpoint())
+
+		events := h.Events()
+		require.NotEmpty(t, events)
+		assert.IsType(t, scraper.BackfillStarted{}, events[0])
+		assert.Equal(t, []int{2, 1}, backfillBatchSizes(events))
+		assert.Equal(t, scraper.BackfillDone{TotalProcessed: 3}, events[len(events)-1])
+	})
+
+	t.Run("it resumes from the checkpoint", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		h := scrapertest.NewHarness(t).
+			WithDelegations(scrapertest.Delegations(1, 5)...).
+			WithCheckpoint(3)
+
+		// Act
+		h.RunBackfill()
+
+		// Assert
+		assert.Equal(t, []int64{4, 5}, savedIDs(h.Saved()))
+		started, ok := h.Events()[0].(scraper.BackfillStarted)
+		require.True(t, ok, "the first event should start the backfill")
+		assert.Equal(t, int64(3), started.CheckpointID)
+	})
+
+	t.Run("it finishes an empty backfill", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		h := scrapertest.NewHarness(t).RunBackfill()
+
+		// Assert
+		assert.Empty(t, h.Saved())
+		assert.Equal(t, scraper.BackfillDone{}, h.Events()[len(h.Events())-1])
+	})
+
+	t.Run("it records a failed backfill without failing the test", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		invalid := scrapertest.Delegation(1)
+		invalid.Amount = -1
+
+		// Act
+		h := scrapertest.NewHarness(t).WithDelegations(invalid).RunBackfill()
+
+		// Assert
+		backfillErr, ok := h.Events()[len(h.Events())-1].(scraper.BackfillError)
+		require.True(t, ok, "the last event should be the backfill error")
+		assert.ErrorIs(t, backfillErr.Err, scraper.ErrNegativeAmount)
+		assert.Empty(t, h.Saved())
+	})
+}
+
+func TestHarnessPolling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it runs one polling cycle per queued response", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		h := scrapertest.NewHarness(t).
+			WithDelegations(scrapertest.Delegation(1)).
+			WithPollResponses(
+				[]tzkt.Delegation{scrapertest.Delegation(2), scrapertest.Delegation(3)},
+				nil,
+				[]tzkt.Delegation{scrapertest.Delegation(4)},
+			)
+
+		// Act
+		h.RunPolls(3)
+
+		// Assert
+		cycles := pollingCycles(h.Events())
+		require.Len(t, cycles, 3)
+		assert.Equal(t, []int{2, 0, 1}, []int{cycles[0].Fetched, cycles[1].Fetched, cycles[2].Fetched})
+		assert.Equal(t, int64(4), cycles[2].CheckpointID)
+		assert.Equal(t, []int64{1, 2, 3, 4}, savedIDs(h.Saved()))
+
+		shutdown, ok := h.Events()[len(h.Events())-1].(scraper.PollingShutdown)
+		require.True(t, ok, "the last event should be the polling shutdown")
+		assert.ErrorIs(t, shutdown.Reason, scraper.ErrMaxPollCyclesReached)
+	})
+
+	t.Run("it finds nothing once the queued responses run out", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		h := scrapertest.NewHarness(t).
+			WithPollResponses([]tzkt.Delegation{scrapertest.Delegation(1)})
+
+		// Act
+		h.RunPolls(2)
+
+		// Assert
+		cycles := pollingCycles(h.Events())
+		require.Len(t, cycles, 2)
+		assert.Equal(t, 1, cycles[0].Fetched)
+		assert.Equal(t, 0, cycles[1].Fetched)
+	})
+
+	t.Run("it keeps the store between runs", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		h := scrapertest.NewHarness(t).
+			WithDelegations(scrapertest.Delegations(1, 2)...).
+			WithPollResponses([]tzkt.Delegation{scrapertest.Delegation(3)})
+		h.RunBackfill()
+
+		// Act
+		h.RunPolls(1)
+
+		// Assert
+		assert.Equal(t, []int64{1, 2, 3}, savedIDs(h.Saved()), "the second backfill should find nothing new")
+		assert.Equal(t, scraper.BackfillDone{}, backfillDone(t, h.Events()))
+	})
+}
+
+func savedIDs(delegations []scraper.Delegation) []int64 {
+	ids := make([]int64, 0, len(delegations))
+	for _, d := range delegations {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func backfillBatchSizes(events []scraper.Event) []int {
+	var sizes []int
+	for _, ev := range events {
+		if batch, ok := ev.(scraper.BackfillSyncCompleted); ok {
+			sizes = append(sizes, batch.Fetched)
+		}
+	}
+	return sizes
+}
+
+func pollingCycles(events []scraper.Event) []scraper.PollingSyncCompleted {
+	var cycles []scraper.PollingSyncCompleted
+	for _, ev := range events {
+		if cycle, ok := ev.(scraper.PollingSyncCompleted); ok {
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+func backfillDone(t *testing.T, events []scraper.Event) scraper.BackfillDone {
+	t.Helper()
+
+	for _, ev := range events {
+		if done, ok := ev.(scraper.BackfillDone); ok {
+			return done
+		}
+	}
+	require.FailNow(t, "no BackfillDone event")
+	return scraper.BackfillDone{}
+}
@@ -0,0 +1,64 @@
+package scrapertest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/screwyprof/delegator/scraper"
+)
+
+// Store is an in-memory scraper.Store, safe for concurrent use
+type Store struct {
+	mu         sync.Mutex
+	checkpoint int64
+	saved      []scraper.Delegation
+}
+
+// NewStore creates an empty store starting from checkpoint
+func NewStore(checkpoint int64) *Store {
+	return &Store{checkpoint: checkpoint}
+}
+
+// LastProcessedID returns the current checkpoint
+func (s *Store) LastProcessedID(context.Context) (int64, error) {
+	return s.Checkpoint(), nil
+}
+
+// SaveBatch records the batch and advances the checkpoint to its highest ID,
+// never moving it backwards
+func (s *Store) SaveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saved = append(s.saved, delegations...)
+	for _, d := range delegations {
+		s.checkpoint = max(s.checkpoint, d.ID)
+	}
+	return s.checkpoint, nil
+}
+
+// SetCheckpoint moves the checkpoint to id, e.g. to resume after earlier runs
+func (s *Store) SetCheckpoint(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = id
+}
+
+// Checkpoint returns the current checkpoint
+func (s *Store) Checkpoint() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoint
+}
+
+// Saved returns a copy of every saved delegation, in save order
+func (s *Store) Saved() []scraper.Delegation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.saved)
+}
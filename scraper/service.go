@@ -1,8 +1,12 @@
 package scraper
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/screwyprof/delegator/pkg/clock"
@@ -18,7 +22,8 @@ func WithClock(c Clock) Option {
 	return func(s *Service) { s.clock = c }
 }
 
-// WithPollInterval sets the polling interval
+// WithPollInterval sets the polling interval. After a poll rate-limited by the API, the
+// next one waits for the Retry-After the API asked for instead, when that is longer.
 func WithPollInterval(d time.Duration) Option {
 	return func(s *Service) { s.pollInterval = d }
 }
@@ -28,15 +33,189 @@ func WithChunkSize(n uint64) Option {
 	return func(s *Service) { s.chunkSize = n }
 }
 
+// WithBackfillTimeout bounds the whole backfill phase using the injected clock. Once
+// it elapses, a fetch or save still in flight is cancelled through its context.
+// Zero (the default) disables the timeout.
+func WithBackfillTimeout(d time.Duration) Option {
+	return func(s *Service) { s.backfillTimeout = d }
+}
+
+// WithBlockHashEnrichment attaches block hashes to every fetched batch before it is saved.
+// Hashes are looked up by level using a second API call per batch.
+func WithBlockHashEnrichment(client BlockHashClient) Option {
+	return func(s *Service) { s.blockHashes = client }
+}
+
+// WithBackfillDirection sets the order in which backfill walks delegation IDs.
+// DescendingByID fetches newest-first down to the starting checkpoint so recent
+// data is available early. Its progress (the floor) is tracked in memory only, so
+// an interrupted descending backfill is not resumed on restart.
+func WithBackfillDirection(dir Direction) Option {
+	return func(s *Service) { s.direction = dir }
+}
+
+// WithDryRun fetches and converts delegations without saving them or advancing the
+// stored checkpoint. The checkpoint is read once and then advanced in memory only,
+// so sync events still report the fetched counts. Useful for connectivity smoke tests.
+func WithDryRun(enabled bool) Option {
+	return func(s *Service) { s.dryRun = enabled }
+}
+
+// WithCheckpointReconciliation raises a stale checkpoint to the highest stored
+// delegation ID at startup, so rows already in the database are not fetched again.
+// The store must implement CheckpointReconciler; otherwise backfill fails.
+func WithCheckpointReconciliation(enabled bool) Option {
+	return func(s *Service) { s.reconcile = enabled }
+}
+
+// WithSuppressEmptyPollEvents skips the PollingSyncCompleted event for polls that
+// fetched nothing, keeping quiet periods out of the event stream. Polling still
+// waits a full interval between attempts.
+func WithSuppressEmptyPollEvents(enabled bool) Option {
+	return func(s *Service) { s.suppressEmpty = enabled }
+}
+
+// WithMaxPollCycles stops the service cleanly after n poll cycles, emitting
+// PollingShutdown{Reason: ErrMaxPollCyclesReached}. Every poll attempt counts,
+// including failed and empty ones. Useful for tests and bounded batch jobs.
+// n <= 0 (the default) polls until the context is cancelled.
+func WithMaxPollCycles(n int) Option {
+	return func(s *Service) { s.maxPollCycles = n }
+}
+
+// WithMaxBackfillRecords ends backfill once n delegations have been fetched, trimming
+// the last request so exactly n are saved when that many are available. Polling then
+// starts as usual. Useful for seeding small, reproducible databases.
+// n == 0 (the default) backfills until the API runs dry.
+func WithMaxBackfillRecords(n uint64) Option {
+	return func(s *Service) { s.maxBackfillRecords = n }
+}
+
+// WithMaxConsecutivePollErrors shuts the service down after n polls in a row fail,
+// emitting PollingShutdown{Reason: ErrTooManyPollErrors} wrapping the last error, so a
+// persistent outage surfaces instead of being retried forever. A successful poll
+// resets the count. n <= 0 (the default) keeps polling through any number of errors.
+func WithMaxConsecutivePollErrors(n int) Option {
+	return func(s *Service) { s.maxPollErrors = n }
+}
+
+// WithTimestampCheckpoint continues backfill and polling from the newest stored
+// delegation timestamp (timestamp.ge) instead of the highest ID (id.gt). Rows sharing
+// that timestamp are refetched and skipped when their ID is at or below the ID
+// checkpoint, so nothing is processed twice. Until a delegation is stored, the ID
+// checkpoint is used. Descending backfill ignores this option. The store must
+// implement TimestampCheckpointer; otherwise syncing fails.
+// A single timestamp (block) must hold fewer delegations than the chunk size.
+func WithTimestampCheckpoint(enabled bool) Option {
+	return func(s *Service) { s.timestampCheckpoint = enabled }
+}
+
+// WithIdleBackoff grows the poll interval by factor after every poll that fetches
+// nothing, up to maxInterval, to save API calls during quiet periods. The first poll
+// that fetches data resets it to the WithPollInterval value; failed polls keep it.
+// A maxInterval not above the poll interval or a factor <= 1 (the default) disables it.
+func WithIdleBackoff(maxInterval time.Duration, factor float64) Option {
+	return func(s *Service) {
+		s.idleMaxInterval = maxInterval
+		s.idleFactor = factor
+	}
+}
+
+// WithCatchUpBurst polls again right away, without waiting for the interval, after
+// every poll that fetched a full chunk, so a backlog that piled up while the scraper
+// was down drains at full speed. The first partial or empty poll returns to waiting
+// for the interval. Burst polls count towards WithMaxPollCycles.
+func WithCatchUpBurst(enabled bool) Option {
+	return func(s *Service) { s.catchUpBurst = enabled }
+}
+
+// WithSkipExistingBatches checks whether every delegation of a batch is already stored
+// before saving it and, if so, only advances the checkpoint past it. This saves a full
+// temp-table copy per batch when re-running over a range that was scraped before.
+// The store must implement ExistenceChecker and CheckpointReconciler; otherwise saving fails.
+func WithSkipExistingBatches(enabled bool) Option {
+	return func(s *Service) { s.skipExisting = enabled }
+}
+
+// WithConfirmationLag saves only delegations at least blocks levels below the current
+// head, leaving more recent ones, which could still be dropped by a reorganisation, for a
+// later sync. The batch is cut before its first delegation within the lag, so the
+// checkpoint never passes an unconfirmed delegation. The head is read from the API client
+// once per non-empty batch; the client must implement HeadLevelClient, otherwise syncing
+// fails. Descending backfill ignores this option. blocks <= 0 (the default) saves every
+// delegation as soon as it is fetched.
+func WithConfirmationLag(blocks int64) Option {
+	return func(s *Service) { s.confirmationLag = blocks }
+}
+
+// WithConcurrentPolling starts polling as soon as backfill starts instead of after it
+// finishes, so new delegations become available right away on a fresh database. At
+// startup the newest delegation ID is read from the API: polling continues from there
+// while backfill walks up to it from the checkpoint (or down to the checkpoint with
+// DescendingByID). Each loop tracks its progress in memory and saves through the store,
+// whose checkpoint never moves backwards and which ignores delegations it already has,
+// so overlapping saves are harmless. If an ascending backfill stops short of that ID
+// (e.g. at WithMaxBackfillRecords or an unconfirmed delegation), polling continues from
+// where it stopped. The loops stop together: a failed backfill stops polling, and
+// polling shutting down stops the backfill.
+//
+// Once polling has saved newer delegations the stored checkpoint is past the backfill,
+// so an interrupted backfill is not resumed on restart; use RepairGaps to fill the hole.
+// WithTimestampCheckpoint is ignored.
+func WithConcurrentPolling(enabled bool) Option {
+	return func(s *Service) { s.concurrentPolling = enabled }
+}
+
+// WithHeartbeat emits a Heartbeat event every interval, driven by the clock and
+// independent of backfill and poll activity, so quiet periods (e.g. with
+// WithSuppressEmptyPollEvents) still show the scraper is alive. The reported checkpoint
+// is the highest one returned by a sync so far; the store is not queried.
+// interval <= 0 (the default) disables heartbeats.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(s *Service) { s.heartbeat = interval }
+}
+
+// WithStartupProbe fetches a single delegation from the API before anything else, so an
+// unreachable API or a wrong base URL fails right away with ErrStartupProbeFailed instead
+// of partway into a long backfill. The store is not touched when the probe fails.
+func WithStartupProbe(enabled bool) Option {
+	return func(s *Service) { s.startupProbe = enabled }
+}
+
 // Service implements two-phase scraping: backfill then live polling
 // -----------------------------------------------------------------
 type Service struct {
-	api          Client
-	store        Store
-	clock        Clock
-	pollInterval time.Duration
-	chunkSize    uint64
-	events       chan Event
+	api                 Client
+	store               Store
+	clock               Clock
+	pollInterval        time.Duration
+	chunkSize           uint64
+	backfillTimeout     time.Duration
+	direction           Direction
+	blockHashes         BlockHashClient
+	dryRun              bool
+	dryRunCursor        int64 // In-memory checkpoint for dry runs, loaded from the store on first use
+	dryRunStarted       bool
+	reconcile           bool
+	suppressEmpty       bool
+	maxPollCycles       int
+	maxPollErrors       int
+	maxBackfillRecords  uint64
+	timestampCheckpoint bool
+	idleMaxInterval     time.Duration
+	idleFactor          float64
+	skipExisting        bool
+	confirmationLag     int64
+	concurrentPolling   bool
+	catchUpBurst        bool
+	heartbeat           time.Duration
+	startupProbe        bool
+	mu                  sync.Mutex // Guards the dry-run and heartbeat checkpoints, which concurrent loops update
+	checkpointID        int64      // Highest checkpoint reported by a sync, for heartbeats
+	dryRunTimestamp     time.Time  // In-memory timestamp checkpoint for dry runs
+	dryRunTimestampSet  bool
+	trigger             chan struct{} // Pending TriggerPoll request; buffered so requests coalesce
+	events              chan Event
 }
 
 // NewService constructs a Service with required dependencies and options
@@ -49,6 +228,7 @@ func NewService(api Client, store Store, opts ...Option) *Service {
 		clock:        clock.SystemClock{},
 		pollInterval: DefaultPollInterval,
 		chunkSize:    DefaultChunkSize,
+		trigger:      make(chan struct{}, 1),
 		events:       make(chan Event, 10),
 	}
 	for _, opt := range opts {
@@ -73,82 +253,399 @@ func NewService(api Client, store Store, opts ...Option) *Service {
 //	}()
 //
 // The context signals when to stop, the done channel confirms when stopped.
+//
+// Ordering guarantees:
+//   - Events are produced by run and, with WithConcurrentPolling, by the polling
+//     goroutine run waits for. The events channel is closed only after run has
+//     returned, so no event is ever sent on a closed channel.
+//   - BackfillDone always precedes PollingStarted, unless WithConcurrentPolling is
+//     enabled: then polling events interleave with backfill events after BackfillStarted.
+//   - The last event before the channel closes is either BackfillError (backfill
+//     aborted, including by cancellation) or PollingShutdown. Heartbeat events, sent
+//     from their own goroutine, may arrive at any point, including right after it.
+//   - The events channel is closed before done, so once done is closed every
+//     event has already been delivered to the channel.
 func (s *Service) Start(ctx context.Context) (<-chan Event, <-chan struct{}) {
 	done := make(chan struct{})
 	go func() {
-		defer close(s.events)
 		defer close(done)
+		defer close(s.events)
+
+		stopHeartbeat := s.startHeartbeat(ctx)
+		defer stopHeartbeat()
+
 		s.run(ctx)
 	}()
 	return s.events, done
 }
 
+// TriggerPoll requests one immediate polling cycle outside the regular schedule.
+// It never blocks and is safe to call from any goroutine. Requests made while one
+// is already pending coalesce into a single poll; requests made before polling
+// starts run as soon as it does. The triggered poll counts towards WithMaxPollCycles
+// and restarts the poll interval.
+func (s *Service) TriggerPoll() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// emit sends an event to subscribers. It must only be called from run or the
+// polling goroutine it starts.
+func (s *Service) emit(ev Event) {
+	s.events <- ev
+}
+
+// startHeartbeat emits a Heartbeat every heartbeat interval until ctx is done or the
+// returned stop is called; stop waits for the heartbeat goroutine to exit
+func (s *Service) startHeartbeat(ctx context.Context) (stop func()) {
+	if s.heartbeat <= 0 {
+		return func() {}
+	}
+
+	quit := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-quit:
+				return
+			case at := <-s.clock.After(s.heartbeat):
+				select {
+				case s.events <- Heartbeat{At: at, CheckpointID: s.reportedCheckpoint()}:
+				case <-quit:
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(quit)
+		<-stopped
+	}
+}
+
+// recordCheckpoint remembers the highest checkpoint reported by a sync, for heartbeats
+func (s *Service) recordCheckpoint(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpointID = max(s.checkpointID, id)
+}
+
+func (s *Service) reportedCheckpoint() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpointID
+}
+
 // run orchestrates the backfill and polling, respecting context cancellation
 // -------------------------------------------------------------------------
 func (s *Service) run(ctx context.Context) {
 	// Backfill
 	start := s.clock.Now()
 
+	if s.startupProbe {
+		if err := s.probe(ctx); err != nil {
+			s.emit(BackfillError{Err: err})
+			return
+		}
+	}
+
+	if s.reconcile {
+		if err := s.reconcileCheckpoint(ctx); err != nil {
+			s.emit(BackfillError{Err: err})
+			return
+		}
+	}
+
 	// Get starting checkpoint ID for observability
-	startingCheckpointID, err := s.store.LastProcessedID(ctx)
+	startingCheckpointID, err := s.lastProcessedID(ctx)
 	if err != nil {
-		s.events <- BackfillError{Err: fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)}
+		s.emit(BackfillError{Err: fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)})
 		return
 	}
 
-	s.events <- BackfillStarted{
+	s.recordCheckpoint(startingCheckpointID)
+
+	coldStart, err := s.isColdStart(ctx, startingCheckpointID)
+	if err != nil {
+		s.emit(BackfillError{Err: fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)})
+		return
+	}
+
+	s.emit(BackfillStarted{
 		StartedAt:    start,
 		CheckpointID: startingCheckpointID,
+		ColdStart:    coldStart,
+	})
+
+	if s.concurrentPolling {
+		s.runConcurrently(ctx, start, startingCheckpointID)
+		return
+	}
+
+	total, _, err := s.backfill(ctx, startingCheckpointID, 0)
+	if err != nil {
+		s.emit(BackfillError{Err: err})
+		return
+	}
+
+	s.emit(BackfillDone{
+		TotalProcessed: total,
+		Duration:       s.clock.Now().Sub(start),
+	})
+
+	// Polling
+	s.runPolling(ctx, func(ctx context.Context) (SyncResult, error) {
+		return s.syncBatch(ctx, s.chunkSize)
+	})
+}
+
+// runConcurrently polls from the newest delegation while backfill walks up to it.
+// Whichever loop ends first stops the other; a failed backfill waits for polling to
+// shut down so BackfillError stays the last event.
+func (s *Service) runConcurrently(ctx context.Context, start time.Time, startingCheckpointID int64) {
+	head, err := s.newestID(ctx)
+	if err != nil {
+		s.emit(BackfillError{Err: err})
+		return
 	}
 
-	var total int64
+	backfillCtx, stopBackfill := context.WithCancel(ctx)
+	defer stopBackfill()
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	defer stopPolling()
+
+	// An ascending backfill stopping short of head hands its cursor over to polling
+	handover := make(chan int64, 1)
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		defer stopBackfill()
+
+		cursor := max(head, startingCheckpointID)
+		s.runPolling(pollCtx, func(ctx context.Context) (SyncResult, error) {
+			select {
+			case reached := <-handover:
+				cursor = min(cursor, reached)
+			default:
+			}
+
+			result, next, err := s.syncAfter(ctx, s.chunkSize, cursor, 0)
+			cursor = next
+			return result, err
+		})
+	}()
+
+	total, reached, err := s.backfill(backfillCtx, startingCheckpointID, head+1)
+	if err != nil {
+		stopPolling()
+		<-polled
+		s.emit(BackfillError{Err: err})
+		return
+	}
+	if s.direction != DescendingByID && reached < head {
+		handover <- reached
+	}
+
+	s.emit(BackfillDone{
+		TotalProcessed: total,
+		Duration:       s.clock.Now().Sub(start),
+	})
+	<-polled
+}
+
+// backfill syncs batches until none is left, emitting BackfillSyncCompleted for each,
+// and returns how many delegations it fetched. A positive bound keeps it below that ID:
+// an ascending walk then continues from an in-memory cursor rather than the stored
+// checkpoint, and reached reports the highest ID it fetched.
+func (s *Service) backfill(ctx context.Context, startingCheckpointID, bound int64) (total, reached int64, err error) {
+	ctx, stop := s.withBackfillDeadline(ctx)
+	defer stop()
+
+	floor := bound // lowest ID saved by a descending backfill; the bound (0 = none) until the first batch
+	reached = startingCheckpointID
 	for {
-		result, err := s.syncBatch(ctx, s.chunkSize)
+		if err := backfillTimedOut(ctx); err != nil {
+			return total, reached, err
+		}
+
+		var result SyncResult
+		chunkSize := s.backfillChunkSize(total)
+		switch {
+		case s.direction == DescendingByID:
+			result, err = s.syncBatchDescending(ctx, chunkSize, startingCheckpointID, floor)
+			floor = result.FloorID
+		case bound > 0:
+			result, reached, err = s.syncAfter(ctx, chunkSize, reached, bound)
+		default:
+			result, err = s.syncBatch(ctx, chunkSize)
+		}
 		if err != nil {
-			s.events <- BackfillError{Err: err}
-			return
+			if timeoutErr := backfillTimedOut(ctx); timeoutErr != nil {
+				return total, reached, timeoutErr
+			}
+			return total, reached, err
 		}
+		s.recordCheckpoint(result.CheckpointID)
 		if result.Count == 0 {
-			break
+			return total, reached, nil
 		}
 		total += int64(result.Count)
 
 		// Emit sync completed event for each batch
-		s.events <- BackfillSyncCompleted{
+		s.emit(BackfillSyncCompleted{
 			Fetched:      result.Count,
 			CheckpointID: result.CheckpointID,
+			FloorID:      result.FloorID,
 			ChunkSize:    s.chunkSize,
+		})
+
+		if s.maxBackfillRecords > 0 && uint64(total) >= s.maxBackfillRecords {
+			return total, reached, nil
 		}
 	}
+}
 
-	stop := s.clock.Now().Sub(start)
-	s.events <- BackfillDone{
-		TotalProcessed: total,
-		Duration:       stop,
+// runPolling emits PollingStarted, then runs a polling cycle with syncCycle every poll
+// interval (or on TriggerPoll) until it emits PollingShutdown
+func (s *Service) runPolling(ctx context.Context, syncCycle func(context.Context) (SyncResult, error)) {
+	s.emit(PollingStarted{Interval: s.pollInterval})
+	failures := 0
+	interval := s.pollInterval
+	wait := interval
+	burst := false
+	for cycles := 0; s.maxPollCycles <= 0 || cycles < s.maxPollCycles; cycles++ {
+		if !s.waitForPoll(ctx, wait, burst) {
+			s.emit(PollingShutdown{Reason: ctx.Err()})
+			return
+		}
+
+		var err error
+		interval, burst, err = s.poll(ctx, interval, syncCycle)
+		wait = retryWait(interval, err)
+		switch {
+		case err == nil:
+			failures = 0
+		case ctx.Err() != nil:
+			// Cancelled mid-poll: the next select reports the shutdown
+		default:
+			failures++
+			if s.maxPollErrors > 0 && failures >= s.maxPollErrors {
+				s.emit(PollingShutdown{Reason: fmt.Errorf("%w: %d in a row: %w", ErrTooManyPollErrors, failures, err)})
+				return
+			}
+		}
 	}
+	s.emit(PollingShutdown{Reason: ErrMaxPollCyclesReached})
+}
 
-	// Polling
-	s.events <- PollingStarted{Interval: s.pollInterval}
-	for {
+// waitForPoll blocks until the next poll is due: after wait, on TriggerPoll, or right
+// away during a catch-up burst. It reports false once ctx is done.
+func (s *Service) waitForPoll(ctx context.Context, wait time.Duration, burst bool) bool {
+	if burst {
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.clock.After(wait):
+	case <-s.trigger:
+	}
+	return true
+}
+
+// poll runs a single polling cycle with syncCycle after waiting interval and emits its
+// outcome. It returns the interval to wait before the next cycle, whether the next cycle
+// should run right away as part of a catch-up burst, and the error it emitted.
+func (s *Service) poll(ctx context.Context, interval time.Duration, syncCycle func(context.Context) (SyncResult, error)) (time.Duration, bool, error) {
+	result, err := syncCycle(ctx)
+	if err != nil {
+		s.emit(PollingError{Err: err})
+		return interval, false, err
+	}
+	s.recordCheckpoint(result.CheckpointID)
+
+	next := s.nextPollInterval(interval, result.Count)
+	burst := s.catchUpBurst && result.Count > 0 && uint64(result.Count) >= s.chunkSize
+	if result.Count == 0 && s.suppressEmpty {
+		return next, false, nil
+	}
+
+	nextWait := next
+	if burst {
+		nextWait = 0
+	}
+	s.emit(PollingSyncCompleted{
+		Fetched:      result.Count,
+		CheckpointID: result.CheckpointID,
+		ChunkSize:    s.chunkSize,
+		NextInterval: nextWait,
+	})
+	return next, burst, nil
+}
+
+// retryWait returns how long to wait before the next poll: the poll interval, or the
+// Retry-After of a rate-limited poll when that asks for longer. The interval itself is
+// kept, so the idle backoff is not inflated by a rate limit.
+func retryWait(interval time.Duration, err error) time.Duration {
+	var rateLimited *tzkt.RateLimitError
+	if errors.As(err, &rateLimited) {
+		return max(interval, rateLimited.RetryAfter)
+	}
+	return interval
+}
+
+// nextPollInterval applies the idle backoff: after an empty poll the interval grows by
+// the idle factor up to the idle maximum; a poll that fetched data resets it
+func (s *Service) nextPollInterval(interval time.Duration, fetched int) time.Duration {
+	if fetched > 0 || s.idleFactor <= 1 || s.idleMaxInterval <= s.pollInterval {
+		return s.pollInterval
+	}
+	return min(time.Duration(float64(interval)*s.idleFactor), s.idleMaxInterval)
+}
+
+// backfillChunkSize returns the limit for the next backfill request, trimmed so the
+// backfill never fetches more than WithMaxBackfillRecords in total
+func (s *Service) backfillChunkSize(fetched int64) uint64 {
+	if s.maxBackfillRecords == 0 {
+		return s.chunkSize
+	}
+	return min(s.chunkSize, s.maxBackfillRecords-uint64(fetched))
+}
+
+// withBackfillDeadline derives a context that is cancelled with an ErrBackfillTimeout
+// cause once the backfill timeout elapses on the clock, so a hung request or save is
+// interrupted too. The context is returned as is when the timeout is disabled.
+func (s *Service) withBackfillDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.backfillTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	timeout := s.clock.After(s.backfillTimeout)
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
 		select {
+		case <-timeout:
+			cancel(fmt.Errorf("%w: after %v", ErrBackfillTimeout, s.backfillTimeout))
 		case <-ctx.Done():
-			s.events <- PollingShutdown{Reason: ctx.Err()}
-			return
-		case <-s.clock.After(s.pollInterval):
-			result, err := s.syncBatch(ctx, s.chunkSize)
-			if err != nil {
-				s.events <- PollingError{Err: err}
-				continue
-			}
-
-			// Always emit polling sync completed event
-			s.events <- PollingSyncCompleted{
-				Fetched:      result.Count,
-				CheckpointID: result.CheckpointID,
-				ChunkSize:    s.chunkSize,
-			}
 		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// backfillTimedOut returns the timeout error once the backfill deadline of ctx has passed
+func backfillTimedOut(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrBackfillTimeout) {
+		return cause
 	}
+	return nil
 }
 
 // syncBatch fetches the next batch, saves it atomically, and returns sync result
@@ -161,7 +658,7 @@ func (s *Service) syncBatch(ctx context.Context, chunkSize uint64) (SyncResult,
 	}
 
 	// load checkpoint
-	checkpointID, err := s.store.LastProcessedID(ctx)
+	checkpointID, err := s.lastProcessedID(ctx)
 	if err != nil {
 		return SyncResult{}, fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)
 	}
@@ -171,45 +668,433 @@ func (s *Service) syncBatch(ctx context.Context, chunkSize uint64) (SyncResult,
 		Limit:         chunkSize,
 		IDGreaterThan: &checkpointID,
 	}
+
+	var since time.Time
+	if s.timestampCheckpoint {
+		since, err = s.lastProcessedTimestamp(ctx)
+		if err != nil {
+			return SyncResult{}, fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)
+		}
+		if !since.IsZero() {
+			req.IDGreaterThan, req.TimestampGE = nil, &since
+		}
+	}
+
 	batch, err := s.api.GetDelegations(ctx, req)
 	if err != nil {
 		return SyncResult{}, fmt.Errorf("%w: %w", ErrAPIRequestFailed, err)
 	}
+	if req.TimestampGE != nil {
+		batch = skipProcessed(batch, since, checkpointID)
+	}
+	batch, err = s.confirmedOnly(ctx, batch)
+	if err != nil {
+		return SyncResult{}, err
+	}
 
 	if len(batch) == 0 {
 		return SyncResult{Count: 0, CheckpointID: checkpointID}, nil
 	}
 
 	// Convert API delegations to domain delegations
-	domainDelegations := convertTzktDelegations(batch)
+	domainDelegations, err := convertTzktDelegations(batch)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	// Optionally enrich with block hashes before saving
+	if err := s.enrichWithBlockHashes(ctx, domainDelegations); err != nil {
+		return SyncResult{}, err
+	}
+
+	// save batch; the store advances the checkpoint and reports it back
+	newCheckpointID, err := s.saveBatch(ctx, domainDelegations)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("%w: %w", ErrSaveBatchFailed, err)
+	}
+
+	return SyncResult{
+		Count:        len(domainDelegations),
+		CheckpointID: newCheckpointID,
+	}, nil
+}
+
+// confirmedOnly cuts a batch sorted by ID ascending before its first delegation within
+// the confirmation lag of the current head; without a lag the batch is returned as is
+func (s *Service) confirmedOnly(ctx context.Context, batch []tzkt.Delegation) ([]tzkt.Delegation, error) {
+	if s.confirmationLag <= 0 || len(batch) == 0 {
+		return batch, nil
+	}
+
+	heads, ok := s.api.(HeadLevelClient)
+	if !ok {
+		return nil, ErrConfirmationLagUnsupported
+	}
+	head, err := heads.GetHeadLevel(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHeadLevel, err)
+	}
+
+	confirmed := head - s.confirmationLag
+	for i, d := range batch {
+		if d.Level > confirmed {
+			return batch[:i], nil
+		}
+	}
+	return batch, nil
+}
+
+// syncAfter fetches the next batch above cursor, and below bound when bound is positive,
+// then saves it. Unlike syncBatch it continues from the given cursor rather than the
+// stored checkpoint, so concurrent loops can each walk their own range. It returns the
+// cursor to continue from: the highest ID fetched, or cursor when nothing was saved.
+func (s *Service) syncAfter(ctx context.Context, chunkSize uint64, cursor, bound int64) (SyncResult, int64, error) {
+	// respect cancellation
+	select {
+	case <-ctx.Done():
+		return SyncResult{}, cursor, ctx.Err()
+	default:
+	}
+
+	req := tzkt.DelegationsRequest{
+		Limit:         chunkSize,
+		IDGreaterThan: &cursor,
+	}
+	if bound > 0 {
+		req.IDLessThan = &bound
+	}
+	batch, err := s.api.GetDelegations(ctx, req)
+	if err != nil {
+		return SyncResult{}, cursor, fmt.Errorf("%w: %w", ErrAPIRequestFailed, err)
+	}
+	batch, err = s.confirmedOnly(ctx, batch)
+	if err != nil {
+		return SyncResult{}, cursor, err
+	}
+
+	if len(batch) == 0 {
+		checkpointID, err := s.lastProcessedID(ctx)
+		if err != nil {
+			return SyncResult{}, cursor, fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)
+		}
+		return SyncResult{Count: 0, CheckpointID: checkpointID}, cursor, nil
+	}
+
+	domainDelegations, err := convertTzktDelegations(batch)
+	if err != nil {
+		return SyncResult{}, cursor, err
+	}
+
+	if err := s.enrichWithBlockHashes(ctx, domainDelegations); err != nil {
+		return SyncResult{}, cursor, err
+	}
+
+	// The store keeps the higher checkpoint, whichever loop saved it
+	newCheckpointID, err := s.saveBatch(ctx, domainDelegations)
+	if err != nil {
+		return SyncResult{}, cursor, fmt.Errorf("%w: %w", ErrSaveBatchFailed, err)
+	}
+
+	return SyncResult{
+		Count:        len(domainDelegations),
+		CheckpointID: newCheckpointID,
+	}, domainDelegations[len(domainDelegations)-1].ID, nil
+}
+
+// probe makes the smallest possible request to check the API is reachable
+func (s *Service) probe(ctx context.Context) error {
+	if _, err := s.api.GetDelegations(ctx, tzkt.DelegationsRequest{Limit: 1}); err != nil {
+		return fmt.Errorf("%w: %w: %w", ErrStartupProbeFailed, ErrAPIRequestFailed, err)
+	}
+	return nil
+}
+
+// newestID returns the ID of the newest delegation known to the API, or 0 when there are none
+func (s *Service) newestID(ctx context.Context) (int64, error) {
+	batch, err := s.api.GetDelegations(ctx, tzkt.DelegationsRequest{
+		Limit:        1,
+		SortDescByID: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrAPIRequestFailed, err)
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	return batch[0].ID, nil
+}
+
+// syncBatchDescending fetches the next batch below floor (newest-first) and above
+// lowerBound, then saves it. A zero floor starts from the newest delegation.
+// The forward checkpoint is left at the highest ID ever saved, so polling
+// resumes from the top once the descending walk reaches lowerBound.
+func (s *Service) syncBatchDescending(ctx context.Context, chunkSize uint64, lowerBound, floor int64) (SyncResult, error) {
+	// respect cancellation
+	select {
+	case <-ctx.Done():
+		return SyncResult{}, ctx.Err()
+	default:
+	}
+
+	// load forward checkpoint for reporting
+	checkpointID, err := s.lastProcessedID(ctx)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)
+	}
+
+	req := tzkt.DelegationsRequest{
+		Limit:         chunkSize,
+		IDGreaterThan: &lowerBound,
+		SortDescByID:  true,
+	}
+	if floor > 0 {
+		req.IDLessThan = &floor
+	}
+	batch, err := s.api.GetDelegations(ctx, req)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("%w: %w", ErrAPIRequestFailed, err)
+	}
+
+	if len(batch) == 0 {
+		return SyncResult{Count: 0, CheckpointID: checkpointID, FloorID: floor}, nil
+	}
 
-	// save batch; store updates checkpoint internally
-	err = s.store.SaveBatch(ctx, domainDelegations)
+	domainDelegations, err := convertTzktDelegations(batch)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	// Store expects ascending order
+	slices.SortFunc(domainDelegations, func(a, b Delegation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	if err := s.enrichWithBlockHashes(ctx, domainDelegations); err != nil {
+		return SyncResult{}, err
+	}
+
+	// The store never lowers the forward checkpoint, so it reports the highest ID ever saved
+	newCheckpointID, err := s.saveBatch(ctx, domainDelegations)
 	if err != nil {
 		return SyncResult{}, fmt.Errorf("%w: %w", ErrSaveBatchFailed, err)
 	}
 
-	// Return the count and new checkpoint ID (highest ID in the batch)
-	newCheckpointID := domainDelegations[len(domainDelegations)-1].ID
 	return SyncResult{
-		Count:        len(batch),
+		Count:        len(domainDelegations),
 		CheckpointID: newCheckpointID,
+		FloorID:      domainDelegations[0].ID,
 	}, nil
 }
 
-// convertTzktDelegations converts API delegations to domain delegations
-func convertTzktDelegations(tzktDelegations []tzkt.Delegation) []Delegation {
-	delegations := make([]Delegation, len(tzktDelegations))
+// lastProcessedID returns the checkpoint to continue from. In dry-run mode the
+// stored checkpoint is read once and then tracked in memory.
+func (s *Service) lastProcessedID(ctx context.Context) (int64, error) {
+	if !s.dryRun {
+		return s.store.LastProcessedID(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dryRunStarted {
+		checkpointID, err := s.store.LastProcessedID(ctx)
+		if err != nil {
+			return 0, err
+		}
+		s.dryRunCursor, s.dryRunStarted = checkpointID, true
+	}
+	return s.dryRunCursor, nil
+}
+
+// lastProcessedTimestamp returns the timestamp checkpoint to continue from. In dry-run
+// mode the stored timestamp is read once and then tracked in memory.
+func (s *Service) lastProcessedTimestamp(ctx context.Context) (time.Time, error) {
+	checkpointer, ok := storeAs[TimestampCheckpointer](s.store)
+	if !ok {
+		return time.Time{}, ErrTimestampCheckpointUnsupported
+	}
+
+	if !s.dryRun {
+		return checkpointer.LastProcessedTimestamp(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dryRunTimestampSet {
+		since, err := checkpointer.LastProcessedTimestamp(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		s.dryRunTimestamp, s.dryRunTimestampSet = since, true
+	}
+	return s.dryRunTimestamp, nil
+}
+
+// skipProcessed drops delegations refetched by a timestamp.ge query: those older than
+// since, and those at since whose ID is not above the ID checkpoint
+func skipProcessed(batch []tzkt.Delegation, since time.Time, checkpointID int64) []tzkt.Delegation {
+	return slices.DeleteFunc(batch, func(d tzkt.Delegation) bool {
+		return d.Timestamp.Before(since) || (d.Timestamp.Equal(since) && d.ID <= checkpointID)
+	})
+}
+
+// isColdStart reports whether backfill starts from scratch: the checkpoint is 0 and,
+// when the store implements CheckpointReconciler, no delegations are stored either
+func (s *Service) isColdStart(ctx context.Context, checkpointID int64) (bool, error) {
+	if checkpointID != 0 {
+		return false, nil
+	}
+
+	reconciler, ok := storeAs[CheckpointReconciler](s.store)
+	if !ok {
+		return true, nil
+	}
+
+	maxID, err := reconciler.MaxDelegationID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return maxID == 0, nil
+}
+
+// reconcileCheckpoint advances the checkpoint to the highest stored delegation ID
+// when it lags behind. In dry-run mode only the in-memory checkpoint moves.
+func (s *Service) reconcileCheckpoint(ctx context.Context) error {
+	reconciler, ok := storeAs[CheckpointReconciler](s.store)
+	if !ok {
+		return fmt.Errorf("%w: store does not implement CheckpointReconciler", ErrCheckpointReconcile)
+	}
+
+	maxID, err := reconciler.MaxDelegationID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckpointReconcile, err)
+	}
+
+	checkpointID, err := s.lastProcessedID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckpointRetrieval, err)
+	}
+	if maxID <= checkpointID {
+		return nil
+	}
+
+	if s.dryRun {
+		s.mu.Lock()
+		s.dryRunCursor = maxID
+		s.mu.Unlock()
+		return nil
+	}
+	if err := reconciler.AdvanceCheckpoint(ctx, maxID); err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckpointReconcile, err)
+	}
+	return nil
+}
+
+// saveBatch persists a batch sorted by ID ascending and returns the resulting checkpoint;
+// in dry-run mode it only advances the in-memory checkpoint
+func (s *Service) saveBatch(ctx context.Context, delegations []Delegation) (int64, error) {
+	if s.dryRun {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.dryRunCursor = max(s.dryRunCursor, delegations[len(delegations)-1].ID)
+		for _, d := range delegations {
+			if d.Timestamp.After(s.dryRunTimestamp) {
+				s.dryRunTimestamp = d.Timestamp
+			}
+		}
+		return s.dryRunCursor, nil
+	}
+	if s.skipExisting {
+		skipped, err := s.skipExistingBatch(ctx, delegations)
+		if err != nil {
+			return 0, err
+		}
+		if skipped {
+			return s.store.LastProcessedID(ctx)
+		}
+	}
+	return s.store.SaveBatch(ctx, delegations)
+}
+
+// skipExistingBatch advances the checkpoint past a batch that is stored in full and reports
+// whether it did; a batch with any new delegation is left for SaveBatch
+func (s *Service) skipExistingBatch(ctx context.Context, delegations []Delegation) (bool, error) {
+	checker, ok := storeAs[ExistenceChecker](s.store)
+	if !ok {
+		return false, ErrSkipExistingUnsupported
+	}
+	reconciler, ok := storeAs[CheckpointReconciler](s.store)
+	if !ok {
+		return false, ErrSkipExistingUnsupported
+	}
+
+	ids := make([]int64, len(delegations))
+	for i, d := range delegations {
+		ids[i] = d.ID
+	}
+	existing, err := checker.CountExisting(ctx, ids)
+	if err != nil {
+		return false, err
+	}
+	if existing < int64(len(ids)) {
+		return false, nil
+	}
+
+	// The checkpoint never moves backwards, matching what SaveBatch would have done
+	return true, reconciler.AdvanceCheckpoint(ctx, delegations[len(delegations)-1].ID)
+}
+
+// enrichWithBlockHashes sets BlockHash on each delegation when enrichment is enabled
+func (s *Service) enrichWithBlockHashes(ctx context.Context, delegations []Delegation) error {
+	if s.blockHashes == nil {
+		return nil
+	}
+
+	levels := make([]int64, len(delegations))
+	for i, d := range delegations {
+		levels[i] = d.Level
+	}
+
+	hashes, err := s.blockHashes.GetBlockHashes(ctx, levels)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEnrichmentFailed, err)
+	}
 
-	for i, tzktDel := range tzktDelegations {
-		delegations[i] = Delegation{
+	for i := range delegations {
+		delegations[i].BlockHash = hashes[delegations[i].Level]
+	}
+
+	return nil
+}
+
+// convertTzktDelegations converts API delegations to domain delegations.
+// The whole batch is rejected if any delegation carries a negative amount.
+// An ID repeated within the batch is kept once, at its first occurrence, so the
+// reported count and the checkpoint taken from the last delegation stay correct.
+func convertTzktDelegations(tzktDelegations []tzkt.Delegation) ([]Delegation, error) {
+	delegations := make([]Delegation, 0, len(tzktDelegations))
+	seen := make(map[int64]struct{}, len(tzktDelegations))
+
+	for _, tzktDel := range tzktDelegations {
+		if tzktDel.Amount < 0 {
+			return nil, fmt.Errorf("%w: delegation %d: %w (%d)", ErrConversionFailed, tzktDel.ID, ErrNegativeAmount, tzktDel.Amount)
+		}
+
+		if _, ok := seen[tzktDel.ID]; ok {
+			continue
+		}
+		seen[tzktDel.ID] = struct{}{}
+
+		delegations = append(delegations, Delegation{
 			ID:        tzktDel.ID,
 			Level:     tzktDel.Level,
 			Timestamp: tzktDel.Timestamp,
 			Delegator: tzktDel.Sender.Address,
 			Amount:    tzktDel.Amount,
-		}
+		})
 	}
 
-	return delegations
+	return delegations, nil
 }
@@ -14,23 +14,42 @@ type Delegation struct {
 	Delegator string    `db:"delegator"`
 	Level     int64     `db:"level"`
 	Year      int       `db:"year"`
+	BlockHash *string   `db:"block_hash"` // NULL unless block hash enrichment is enabled
 	// created_at is handled by database DEFAULT CURRENT_TIMESTAMP
 }
 
 // ScraperDelegationsToRows converts scraper delegations directly to [][]any for pgx.CopyFromRows
+// Timestamps are normalized to UTC so the derived year matches the year filter at Dec 31 / Jan 1 boundaries
 func ScraperDelegationsToRows(delegations []scraper.Delegation) [][]any {
 	rows := make([][]any, len(delegations))
 
 	for i, d := range delegations {
-		rows[i] = []any{
-			d.ID,
-			d.Timestamp,
-			d.Amount,
-			d.Delegator,
-			d.Level,
-			d.Timestamp.Year(),
-		}
+		rows[i] = AppendScraperDelegationRow(nil, d)
 	}
 
 	return rows
 }
+
+// AppendScraperDelegationRow appends the column values of a single delegation to row,
+// in ScraperDelegationsToRows order. Passing row[:0] reuses its storage, which lets a
+// streaming copy encode one delegation at a time without materializing every row.
+func AppendScraperDelegationRow(row []any, d scraper.Delegation) []any {
+	timestamp := d.Timestamp.UTC()
+	return append(row,
+		d.ID,
+		timestamp,
+		d.Amount,
+		d.Delegator,
+		d.Level,
+		timestamp.Year(),
+		blockHashOrNil(d.BlockHash),
+	)
+}
+
+// blockHashOrNil maps a missing block hash to NULL
+func blockHashOrNil(hash string) any {
+	if hash == "" {
+		return nil
+	}
+	return hash
+}
@@ -0,0 +1,62 @@
+package dbrow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/scraper"
+	"github.com/screwyprof/delegator/scraper/store/dbrow"
+)
+
+func TestScraperDelegationsToRows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it normalizes timestamps to UTC before deriving the year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		eastOfUTC := time.FixedZone("UTC+2", 2*60*60)
+		delegations := []scraper.Delegation{{
+			ID:        1,
+			Timestamp: time.Date(2025, 1, 1, 1, 30, 0, 0, eastOfUTC),
+		}}
+
+		// Act
+		rows := dbrow.ScraperDelegationsToRows(delegations)
+
+		// Assert
+		require.Len(t, rows, 1)
+		timestamp, ok := rows[0][1].(time.Time)
+		require.True(t, ok)
+		assert.Equal(t, time.UTC, timestamp.Location())
+		assert.Equal(t, time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC), timestamp)
+		assert.Equal(t, 2024, rows[0][5], "Year should be derived from the UTC timestamp")
+	})
+}
+
+func TestAppendScraperDelegationRow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it produces the same row as the batch conversion", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		delegations := []scraper.Delegation{
+			{ID: 1, Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 10, Delegator: "tz1a", Level: 100, BlockHash: "BLockHash1"},
+			{ID: 2, Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 20, Delegator: "tz1b", Level: 101},
+		}
+		expected := dbrow.ScraperDelegationsToRows(delegations)
+
+		// Act
+		var row []any
+		for i, d := range delegations {
+			row = dbrow.AppendScraperDelegationRow(row[:0], d)
+
+			// Assert
+			assert.Equal(t, expected[i], row)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package pgxstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/scraper"
+)
+
+// ErrReconnectFailed is returned when the pool could not be recreated after repeated failures
+var ErrReconnectFailed = errors.New("store reconnect failed")
+
+const (
+	// DefaultReconnectThreshold is the number of consecutive transaction failures that trigger a reconnect
+	DefaultReconnectThreshold = 3
+	// DefaultReconnectAttempts is the number of times a new pool is tried before giving up
+	DefaultReconnectAttempts = 3
+	// DefaultReconnectBackoffBase is the delay before the second attempt; it doubles after each one
+	DefaultReconnectBackoffBase = time.Second
+)
+
+// Connector opens a fresh connection pool, typically from the original connection string
+type Connector func(ctx context.Context) (*pgxpool.Pool, error)
+
+// ReconnectOption configures the ReconnectingStore
+type ReconnectOption func(*ReconnectingStore)
+
+// WithStoreOptions applies opts to every Store built on a (re)created pool
+func WithStoreOptions(opts ...Option) ReconnectOption {
+	return func(r *ReconnectingStore) { r.storeOpts = opts }
+}
+
+// WithReconnectThreshold sets how many SaveBatch calls in a row must fail with
+// ErrTransactionFailed before the pool is recreated
+func WithReconnectThreshold(n int) ReconnectOption {
+	return func(r *ReconnectingStore) {
+		if n > 0 {
+			r.threshold = n
+		}
+	}
+}
+
+// WithReconnectAttempts sets how many times a new pool is tried before the failure is returned
+func WithReconnectAttempts(n int) ReconnectOption {
+	return func(r *ReconnectingStore) {
+		if n > 0 {
+			r.attempts = n
+		}
+	}
+}
+
+// WithReconnectBackoff sets the delay before each retried attempt; attempt starts at 1
+// for the first retry
+func WithReconnectBackoff(backoff func(attempt int) time.Duration) ReconnectOption {
+	return func(r *ReconnectingStore) { r.backoff = backoff }
+}
+
+// ReconnectingStore wraps Store and recreates its pool when writes keep failing to
+// start a transaction, e.g. after the database credentials were rotated and every
+// pooled connection is gone for good. pgxpool recovers from ordinary restarts by itself.
+// A replaced pool is closed once the calls still using it have returned.
+type ReconnectingStore struct {
+	connect   Connector
+	storeOpts []Option
+	threshold int
+	attempts  int
+	backoff   func(attempt int) time.Duration
+
+	mu       sync.RWMutex
+	gen      *generation
+	failures int
+	draining sync.WaitGroup // Replaced pools not closed yet
+}
+
+// generation is a pool with the store built on it and the calls currently using them
+type generation struct {
+	pool  *pgxpool.Pool
+	store *Store
+	users sync.WaitGroup
+}
+
+// NewReconnecting creates a store on pool that falls back to connect once SaveBatch
+// fails with ErrTransactionFailed threshold times in a row.
+// Returns the store and a closer function for whichever pool is current.
+func NewReconnecting(pool *pgxpool.Pool, connect Connector, opts ...ReconnectOption) (*ReconnectingStore, func()) {
+	r := &ReconnectingStore{
+		connect:   connect,
+		threshold: DefaultReconnectThreshold,
+		attempts:  DefaultReconnectAttempts,
+		backoff:   httpkit.ExponentialBackoff(DefaultReconnectBackoffBase),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.gen = r.newGeneration(pool)
+
+	closer := func() {
+		r.mu.Lock()
+		r.gen.pool.Close()
+		r.mu.Unlock()
+		r.draining.Wait()
+	}
+	return r, closer
+}
+
+// LastProcessedID returns the last processed delegation ID (checkpoint)
+func (r *ReconnectingStore) LastProcessedID(ctx context.Context) (int64, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.LastProcessedID(ctx)
+}
+
+// CheckpointExists reports whether the checkpoint row has been initialized
+func (r *ReconnectingStore) CheckpointExists(ctx context.Context) (bool, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.CheckpointExists(ctx)
+}
+
+// LastProcessedTimestamp returns the timestamp of the newest stored delegation
+func (r *ReconnectingStore) LastProcessedTimestamp(ctx context.Context) (time.Time, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.LastProcessedTimestamp(ctx)
+}
+
+// MaxDelegationID returns the highest stored delegation ID, or 0 when the table is empty
+func (r *ReconnectingStore) MaxDelegationID(ctx context.Context) (int64, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.MaxDelegationID(ctx)
+}
+
+// CountExisting returns how many of the given delegation IDs are already stored
+func (r *ReconnectingStore) CountExisting(ctx context.Context, ids []int64) (int64, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.CountExisting(ctx, ids)
+}
+
+// AdvanceCheckpoint raises the checkpoint to id; a higher stored checkpoint is kept
+func (r *ReconnectingStore) AdvanceCheckpoint(ctx context.Context, id int64) error {
+	store, release := r.acquire()
+	defer release()
+	return store.AdvanceCheckpoint(ctx, id)
+}
+
+// AdvanceCheckpointIf moves the checkpoint from expected to newID and reports whether it did
+func (r *ReconnectingStore) AdvanceCheckpointIf(ctx context.Context, expected, newID int64) (bool, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.AdvanceCheckpointIf(ctx, expected, newID)
+}
+
+// FindIDGaps streams every hole in the stored delegation IDs to yield
+func (r *ReconnectingStore) FindIDGaps(ctx context.Context, yield func(scraper.Gap) error) error {
+	store, release := r.acquire()
+	defer release()
+	return store.FindIDGaps(ctx, yield)
+}
+
+// SaveBatch saves a batch like Store.SaveBatch. When the failure threshold is reached it
+// recreates the pool, with backoff between attempts, and retries the batch once on it.
+func (r *ReconnectingStore) SaveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
+	checkpointID, err := r.saveBatch(ctx, delegations)
+	if !r.shouldReconnect(ctx, err) {
+		return checkpointID, err
+	}
+
+	if reconnectErr := r.reconnect(ctx); reconnectErr != nil {
+		return 0, fmt.Errorf("%w: %w", reconnectErr, err)
+	}
+	return r.saveBatch(ctx, delegations)
+}
+
+// saveBatch saves on the current store, keeping its pool open until the save returns
+func (r *ReconnectingStore) saveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
+	store, release := r.acquire()
+	defer release()
+	return store.SaveBatch(ctx, delegations)
+}
+
+// acquire returns the store for the current pool and a release function the caller
+// must call once done with it; the pool is not closed before that, even if replaced
+func (r *ReconnectingStore) acquire() (*Store, func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gen := r.gen
+	gen.users.Add(1)
+	return gen.store, gen.users.Done
+}
+
+// shouldReconnect records the outcome of a write and reports whether the threshold was reached.
+// Only ErrTransactionFailed counts: any other outcome proves the pool can still reach the database.
+// Failures while ctx is done are left out, as they come from the caller giving up, not the pool.
+func (r *ReconnectingStore) shouldReconnect(ctx context.Context, err error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+	if !errors.Is(err, ErrTransactionFailed) {
+		r.failures = 0
+		return false
+	}
+	r.failures++
+	return r.failures >= r.threshold
+}
+
+// reconnect replaces the pool with a freshly connected one. The old pool is closed in
+// the background once every call that acquired it has returned.
+func (r *ReconnectingStore) reconnect(ctx context.Context) error {
+	var lastErr error
+	for attempt := range r.attempts {
+		if attempt > 0 {
+			if err := r.wait(ctx, attempt); err != nil {
+				return fmt.Errorf("%w: %w", ErrReconnectFailed, err)
+			}
+		}
+
+		pool, err := r.connect(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.mu.Lock()
+		old := r.gen
+		r.gen, r.failures = r.newGeneration(pool), 0
+		r.draining.Add(1)
+		r.mu.Unlock()
+
+		go func() {
+			defer r.draining.Done()
+			old.users.Wait()
+			old.pool.Close()
+		}()
+		return nil
+	}
+	return fmt.Errorf("%w: after %d attempts: %w", ErrReconnectFailed, r.attempts, lastErr)
+}
+
+// wait sleeps for the backoff of the given retry attempt, or until ctx is done
+func (r *ReconnectingStore) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newGeneration builds a Store on pool with the configured store options
+func (r *ReconnectingStore) newGeneration(pool *pgxpool.Pool) *generation {
+	store, _ := New(pool, r.storeOpts...)
+	return &generation{pool: pool, store: store}
+}
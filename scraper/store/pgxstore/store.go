@@ -1,9 +1,13 @@
 package pgxstore
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,30 +18,108 @@ import (
 
 // Sentinel errors for store operations
 var (
-	ErrTransactionFailed     = errors.New("transaction failed")
-	ErrTempTableFailed       = errors.New("temporary table operation failed")
-	ErrCopyFailed            = errors.New("bulk copy operation failed")
-	ErrInsertFailed          = errors.New("insert operation failed")
-	ErrCheckpointFailed      = errors.New("checkpoint update failed")
-	ErrLastProcessedIDFailed = errors.New("failed to get last processed ID")
+	ErrTransactionFailed      = errors.New("transaction failed")
+	ErrTempTableFailed        = errors.New("temporary table operation failed")
+	ErrCopyFailed             = errors.New("bulk copy operation failed")
+	ErrInsertFailed           = errors.New("insert operation failed")
+	ErrCheckpointFailed       = errors.New("checkpoint update failed")
+	ErrLastProcessedIDFailed  = errors.New("failed to get last processed ID")
+	ErrMaxDelegationIDFailed  = errors.New("failed to get max delegation ID")
+	ErrLastTimestampFailed    = errors.New("failed to get last processed timestamp")
+	ErrFindGapsFailed         = errors.New("failed to find delegation ID gaps")
+	ErrCountExistingFailed    = errors.New("failed to count existing delegations")
+	ErrCheckpointLookupFailed = errors.New("failed to look up checkpoint")
+	ErrNotifyFailed           = errors.New("write notification failed")
 )
 
+// WriteChannel is the channel notified when a saved batch commits, letting readers in other
+// processes such as the web count cache drop what they derived from the old rows
+const WriteChannel = "delegations_written"
+
+// advanceCheckpointSQL raises the checkpoint to $1, never moving it backwards, and returns it
+const advanceCheckpointSQL = `
+	INSERT INTO scraper_checkpoint (single_row, last_id) VALUES (TRUE, $1)
+	ON CONFLICT (single_row) DO UPDATE SET last_id = GREATEST(scraper_checkpoint.last_id, $1)
+	RETURNING last_id
+`
+
+// advanceCheckpointIfSQL moves the checkpoint to $2 only while it still equals $1
+const advanceCheckpointIfSQL = `UPDATE scraper_checkpoint SET last_id = $2 WHERE last_id = $1`
+
+// idGapsSQL pairs each stored ID with the next one and keeps the non-adjacent pairs,
+// numbering them by how many IDs are stored up to the first of the pair
+const idGapsSQL = `
+	SELECT id, next_id, stored_up_to FROM (
+		SELECT id,
+			LEAD(id) OVER (ORDER BY id) AS next_id,
+			ROW_NUMBER() OVER (ORDER BY id) AS stored_up_to
+		FROM delegations
+	) AS ids
+	WHERE next_id > id + 1
+	ORDER BY id
+`
+
+// delegationColumns lists the columns written for each delegation, in row order. It is an
+// array so its length is a constant that MaxSmallBatchThreshold can be derived from.
+var delegationColumns = [...]string{"id", "timestamp", "amount", "delegator", "level", "year", "block_hash"}
+
+// maxBindParameters is the most bind parameters PostgreSQL accepts in one statement
+const maxBindParameters = 65535
+
+// MaxSmallBatchThreshold is the largest threshold WithSmallBatchThreshold accepts: a
+// direct INSERT binds a parameter for each delegation column of every row
+const MaxSmallBatchThreshold = maxBindParameters / len(delegationColumns)
+
+// Option configures the Store
+type Option func(*Store)
+
+// WithSmallBatchThreshold enables a direct multi-row INSERT for batches with fewer
+// than n delegations, skipping the temporary table and CopyFrom overhead.
+// Batches of n or more delegations keep using the temp-table path. Zero disables it.
+// n is capped at MaxSmallBatchThreshold so the INSERT stays within PostgreSQL's limit
+// on bind parameters.
+func WithSmallBatchThreshold(n int) Option {
+	return func(s *Store) { s.smallBatchThreshold = min(n, MaxSmallBatchThreshold) }
+}
+
+// WithStreamingCopy feeds the temp-table CopyFrom one delegation at a time through a
+// single reused row instead of first converting the whole batch to [][]any, so peak
+// memory for large chunks stays close to the batch itself. Stored results are identical.
+func WithStreamingCopy(enabled bool) Option {
+	return func(s *Store) { s.streamingCopy = enabled }
+}
+
+// WithSortBeforeInsert sorts each batch by ID ascending before it is written, so the
+// checkpoint is right even when a caller passes unsorted delegations. The caller's slice
+// is left untouched; batches that are already sorted are written as is.
+func WithSortBeforeInsert(enabled bool) Option {
+	return func(s *Store) { s.sortBeforeInsert = enabled }
+}
+
 // Store implements scraper.Store interface using pgx
 type Store struct {
-	pool *pgxpool.Pool
+	pool                *pgxpool.Pool
+	smallBatchThreshold int
+	streamingCopy       bool
+	sortBeforeInsert    bool
 }
 
 // New creates a new PostgreSQL store with an existing connection pool
 // Returns the store and a closer function
-func New(pool *pgxpool.Pool) (*Store, func()) {
+func New(pool *pgxpool.Pool, opts ...Option) (*Store, func()) {
 	store := &Store{pool: pool}
+	for _, opt := range opts {
+		opt(store)
+	}
 	closer := func() {
 		pool.Close()
 	}
 	return store, closer
 }
 
-// LastProcessedID returns the last processed delegation ID (checkpoint)
+// LastProcessedID returns the last processed delegation ID (checkpoint). It is 0 both
+// when the checkpoint row is missing and when the checkpoint is 0; use CheckpointExists
+// to tell an uninitialized database apart.
 func (s *Store) LastProcessedID(ctx context.Context) (int64, error) {
 	var lastID int64
 	err := s.pool.QueryRow(ctx, "SELECT COALESCE(last_id, 0) FROM scraper_checkpoint").Scan(&lastID)
@@ -50,42 +132,245 @@ func (s *Store) LastProcessedID(ctx context.Context) (int64, error) {
 	return lastID, nil
 }
 
+// CheckpointExists reports whether the checkpoint row has been initialized, e.g. by the
+// migrator or the first saved batch
+func (s *Store) CheckpointExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := s.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM scraper_checkpoint)").Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrCheckpointLookupFailed, err)
+	}
+	return exists, nil
+}
+
+// LastProcessedTimestamp returns the timestamp of the newest stored delegation,
+// or the zero time when the table is empty
+func (s *Store) LastProcessedTimestamp(ctx context.Context) (time.Time, error) {
+	var lastTimestamp time.Time
+	err := s.pool.QueryRow(ctx, "SELECT timestamp FROM delegations ORDER BY timestamp DESC, id DESC LIMIT 1").Scan(&lastTimestamp)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrLastTimestampFailed, err)
+	}
+	return lastTimestamp.UTC(), nil
+}
+
+// MaxDelegationID returns the highest stored delegation ID, or 0 when the table is empty
+func (s *Store) MaxDelegationID(ctx context.Context) (int64, error) {
+	var maxID int64
+	err := s.pool.QueryRow(ctx, "SELECT COALESCE(MAX(id), 0) FROM delegations").Scan(&maxID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrMaxDelegationIDFailed, err)
+	}
+	return maxID, nil
+}
+
+// CountExisting returns how many of the given delegation IDs are already stored,
+// using a single primary key lookup per ID. Duplicate IDs are counted once.
+func (s *Store) CountExisting(ctx context.Context, ids []int64) (int64, error) {
+	var count int64
+	err := s.pool.QueryRow(ctx, "SELECT count(*) FROM delegations WHERE id = ANY($1)", ids).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCountExistingFailed, err)
+	}
+	return count, nil
+}
+
+// AdvanceCheckpoint raises the checkpoint to id; a higher stored checkpoint is kept
+func (s *Store) AdvanceCheckpoint(ctx context.Context, id int64) error {
+	if _, err := s.pool.Exec(ctx, advanceCheckpointSQL, id); err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckpointFailed, err)
+	}
+	return nil
+}
+
+// AdvanceCheckpointIf moves the checkpoint from expected to newID as a single optimistic
+// update and reports whether it did. It returns false without error when another writer
+// already moved the checkpoint away from expected, so concurrent scrapers sharing a
+// database can agree on who advances without leader election. The checkpoint row must
+// exist, as the migrator initializes it.
+func (s *Store) AdvanceCheckpointIf(ctx context.Context, expected, newID int64) (bool, error) {
+	tag, err := s.pool.Exec(ctx, advanceCheckpointIfSQL, expected, newID)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrCheckpointFailed, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FindIDGaps streams every hole in the stored delegation IDs to yield in a single
+// index-ordered scan, without loading them all into memory. An error from yield stops
+// the scan and is returned as is.
+func (s *Store) FindIDGaps(ctx context.Context, yield func(scraper.Gap) error) error {
+	rows, err := s.pool.Query(ctx, idGapsSQL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFindGapsFailed, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gap scraper.Gap
+		if err := rows.Scan(&gap.AfterID, &gap.BeforeID, &gap.StoredUpTo); err != nil {
+			return fmt.Errorf("%w: %w", ErrFindGapsFailed, err)
+		}
+		if err := yield(gap); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFindGapsFailed, err)
+	}
+	return nil
+}
+
 // SaveBatch saves a batch of delegations using pgx CopyFrom for maximum performance
-// Uses a temporary table approach to handle duplicate detection efficiently
-func (s *Store) SaveBatch(ctx context.Context, delegations []scraper.Delegation) error {
+// Uses a temporary table approach to handle duplicate detection efficiently.
+// Batches below the small batch threshold are written with a single multi-row INSERT instead.
+// Errors keep their sentinel and name the batch size and ID range that failed; a save
+// interrupted by ctx also unwraps to ctx.Err(), so shutdown is told apart from failures.
+// Returns the checkpoint as persisted, which is the stored one for an empty batch.
+func (s *Store) SaveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
 	if len(delegations) == 0 {
-		return nil
+		return s.LastProcessedID(ctx)
+	}
+	if s.sortBeforeInsert {
+		delegations = sortedByID(delegations)
 	}
 
-	// Convert scraper.Delegation to [][]any format for pgx.CopyFromRows
-	rows := dbrow.ScraperDelegationsToRows(delegations)
+	checkpointID, err := s.saveBatch(ctx, delegations)
+	if err != nil {
+		return 0, fmt.Errorf("batch of %d rows [%d..%d]: %w",
+			len(delegations), delegations[0].ID, delegations[len(delegations)-1].ID, withContextErr(ctx, err))
+	}
+	return checkpointID, nil
+}
+
+// sortedByID returns delegations ordered by ID ascending, copying only when out of order
+func sortedByID(delegations []scraper.Delegation) []scraper.Delegation {
+	byID := func(a, b scraper.Delegation) int { return cmp.Compare(a.ID, b.ID) }
+	if slices.IsSortedFunc(delegations, byID) {
+		return delegations
+	}
+	sorted := slices.Clone(delegations)
+	slices.SortFunc(sorted, byID)
+	return sorted
+}
+
+// withContextErr adds ctx.Err() to err when ctx is done and pgx did not already wrap it.
+// A query cancelled mid-transaction surfaces as a server or connection error otherwise.
+func withContextErr(ctx context.Context, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", err, ctxErr)
+}
 
+// saveBatch writes a non-empty batch, advances the checkpoint and notifies WriteChannel in one transaction
+func (s *Store) saveBatch(ctx context.Context, delegations []scraper.Delegation) (int64, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
+		return 0, fmt.Errorf("%w: %w", ErrTransactionFailed, err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }() // No-op if commit succeeds
 
-	if err := s.createTempTable(ctx, tx); err != nil {
-		return err
+	if s.isSmallBatch(len(delegations)) {
+		if err := s.insertRowsDirectly(ctx, tx, dbrow.ScraperDelegationsToRows(delegations)); err != nil {
+			return 0, err
+		}
+	} else {
+		if err := s.copyViaTempTable(ctx, tx, s.copySource(delegations)); err != nil {
+			return 0, err
+		}
 	}
 
-	if err := s.bulkCopyToTemp(ctx, tx, rows); err != nil {
-		return err
+	checkpointID, err := s.updateCheckpoint(ctx, tx, delegations)
+	if err != nil {
+		return 0, err
+	}
+
+	// Delivered by PostgreSQL on commit only, so listeners never see a rolled back batch
+	if _, err := tx.Exec(ctx, "SELECT pg_notify($1, '')", WriteChannel); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrNotifyFailed, err)
 	}
 
-	if err := s.insertFromTempToMain(ctx, tx); err != nil {
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrTransactionFailed, err)
+	}
+
+	return checkpointID, nil
+}
+
+// isSmallBatch reports whether a batch of n rows should skip the temp-table path
+func (s *Store) isSmallBatch(n int) bool {
+	return n < s.smallBatchThreshold
+}
+
+// copySource returns the CopyFrom rows for delegations, streamed or fully materialized
+func (s *Store) copySource(delegations []scraper.Delegation) pgx.CopyFromSource {
+	if s.streamingCopy {
+		return streamDelegationRows(delegations)
+	}
+	// Convert scraper.Delegation to [][]any format for pgx.CopyFromRows
+	return pgx.CopyFromRows(dbrow.ScraperDelegationsToRows(delegations))
+}
+
+// streamDelegationRows yields one delegation per row, reusing a single row slice.
+// This is safe because CopyFrom encodes each row before asking for the next one.
+func streamDelegationRows(delegations []scraper.Delegation) pgx.CopyFromSource {
+	row := make([]any, 0, len(delegationColumns))
+	next := 0
+	return pgx.CopyFromFunc(func() ([]any, error) {
+		if next == len(delegations) {
+			return nil, nil
+		}
+		row = dbrow.AppendScraperDelegationRow(row[:0], delegations[next])
+		next++
+		return row, nil
+	})
+}
+
+// copyViaTempTable bulk copies rows into a temporary table and merges them into the main table
+func (s *Store) copyViaTempTable(ctx context.Context, tx pgx.Tx, rows pgx.CopyFromSource) error {
+	if err := s.createTempTable(ctx, tx); err != nil {
 		return err
 	}
 
-	if err := s.updateCheckpoint(ctx, tx, delegations); err != nil {
+	if err := s.bulkCopyToTemp(ctx, tx, rows); err != nil {
 		return err
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
+	return s.insertFromTempToMain(ctx, tx)
+}
+
+// insertRowsDirectly writes rows to the main table with a single multi-row INSERT and conflict resolution
+func (s *Store) insertRowsDirectly(ctx context.Context, tx pgx.Tx, rows [][]any) error {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO delegations (")
+	sb.WriteString(strings.Join(delegationColumns[:], ", "))
+	sb.WriteString(") VALUES ")
+
+	args := make([]any, 0, len(rows)*len(delegationColumns))
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			fmt.Fprintf(&sb, "$%d", len(args))
+		}
+		sb.WriteString(")")
 	}
+	sb.WriteString(" ON CONFLICT (id) DO NOTHING")
 
+	if _, err := tx.Exec(ctx, sb.String(), args...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInsertFailed, err)
+	}
 	return nil
 }
 
@@ -98,7 +383,8 @@ func (s *Store) createTempTable(ctx context.Context, tx pgx.Tx) error {
 			amount BIGINT,
 			delegator TEXT,
 			level BIGINT,
-			year INTEGER
+			year INTEGER,
+			block_hash TEXT
 		) ON COMMIT DROP
 	`)
 	if err != nil {
@@ -108,12 +394,12 @@ func (s *Store) createTempTable(ctx context.Context, tx pgx.Tx) error {
 }
 
 // bulkCopyToTemp performs bulk insert into temporary table using CopyFrom
-func (s *Store) bulkCopyToTemp(ctx context.Context, tx pgx.Tx, rows [][]any) error {
+func (s *Store) bulkCopyToTemp(ctx context.Context, tx pgx.Tx, rows pgx.CopyFromSource) error {
 	_, err := tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"temp_delegations"},
-		[]string{"id", "timestamp", "amount", "delegator", "level", "year"},
-		pgx.CopyFromRows(rows),
+		delegationColumns[:],
+		rows,
 	)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrCopyFailed, err)
@@ -124,8 +410,8 @@ func (s *Store) bulkCopyToTemp(ctx context.Context, tx pgx.Tx, rows [][]any) err
 // insertFromTempToMain transfers data from temporary table to main table with conflict resolution
 func (s *Store) insertFromTempToMain(ctx context.Context, tx pgx.Tx) error {
 	_, err := tx.Exec(ctx, `
-		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
-		SELECT id, timestamp, amount, delegator, level, year
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year, block_hash)
+		SELECT id, timestamp, amount, delegator, level, year, block_hash
 		FROM temp_delegations
 		ON CONFLICT (id) DO NOTHING
 	`)
@@ -136,16 +422,14 @@ func (s *Store) insertFromTempToMain(ctx context.Context, tx pgx.Tx) error {
 }
 
 // updateCheckpoint updates the scraper checkpoint with the highest delegation ID
-func (s *Store) updateCheckpoint(ctx context.Context, tx pgx.Tx, delegations []scraper.Delegation) error {
+// The checkpoint never moves backwards, so saving an older batch (e.g. during a
+// newest-first backfill) leaves the forward checkpoint intact. Returns the resulting checkpoint.
+func (s *Store) updateCheckpoint(ctx context.Context, tx pgx.Tx, delegations []scraper.Delegation) (int64, error) {
 	// Since delegations are sorted by ID, the last one has the highest ID
 	checkpointID := delegations[len(delegations)-1].ID
 
-	_, err := tx.Exec(ctx, `
-		INSERT INTO scraper_checkpoint (single_row, last_id) VALUES (TRUE, $1) 
-		ON CONFLICT (single_row) DO UPDATE SET last_id = $1
-	`, checkpointID)
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrCheckpointFailed, err)
+	if err := tx.QueryRow(ctx, advanceCheckpointSQL, checkpointID).Scan(&checkpointID); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCheckpointFailed, err)
 	}
-	return nil
+	return checkpointID, nil
 }
@@ -0,0 +1,999 @@
+//go:build acceptance
+
+package pgxstore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/migrator/migratortest"
+	"github.com/screwyprof/delegator/pkg/pgxdb"
+	"github.com/screwyprof/delegator/scraper"
+	"github.com/screwyprof/delegator/scraper/store/pgxstore"
+)
+
+const migrationsDir = "../../../migrator/migrations"
+
+// TestStoreSaveBatchStrategies verifies both write paths persist identical data
+func TestStoreSaveBatchStrategies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stores identical rows via direct insert and temp table", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		firstBatch := delegations(1, 5)
+		overlappingBatch := delegations(4, 8) // ids 4 and 5 are duplicates
+
+		tempTableDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer tempTableDB.Close()
+		tempTableStore, _ := pgxstore.New(tempTableDB)
+
+		directInsertDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer directInsertDB.Close()
+		directInsertStore, _ := pgxstore.New(directInsertDB, pgxstore.WithSmallBatchThreshold(100))
+
+		// Act
+		for _, batch := range [][]scraper.Delegation{firstBatch, overlappingBatch} {
+			mustSaveBatch(t, tempTableStore, batch)
+			mustSaveBatch(t, directInsertStore, batch)
+		}
+
+		// Assert
+		assertStoredRowsEqual(t, tempTableDB, directInsertDB)
+		assertCheckpointsEqual(t, tempTableStore, directInsertStore, 8)
+	})
+
+	t.Run("it keeps direct inserts within the bind parameter limit for any threshold", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db, pgxstore.WithSmallBatchThreshold(math.MaxInt))
+
+		largestDirect := int64(pgxstore.MaxSmallBatchThreshold - 1)
+		tooLargeForDirect := largestDirect + 1000
+
+		// Act
+		_, directErr := store.SaveBatch(t.Context(), delegations(1, largestDirect))
+		_, tempTableErr := store.SaveBatch(t.Context(), delegations(largestDirect+1, largestDirect+tooLargeForDirect))
+
+		// Assert
+		require.NoError(t, directErr, "The largest direct insert should fit the parameter limit")
+		require.NoError(t, tempTableErr, "Larger batches should fall back to the temp table")
+		assert.Len(t, selectDelegationRows(t, db), int(largestDirect+tooLargeForDirect))
+	})
+
+	t.Run("it stores identical rows via streaming and materialized copy", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		firstBatch := delegations(1, 500)
+		overlappingBatch := delegations(400, 1000) // ids 400..500 are duplicates
+		firstBatch[0].BlockHash = "BLockHash1"
+
+		materializedDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer materializedDB.Close()
+		materializedStore, _ := pgxstore.New(materializedDB)
+
+		streamingDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer streamingDB.Close()
+		streamingStore, _ := pgxstore.New(streamingDB, pgxstore.WithStreamingCopy(true))
+
+		// Act
+		for _, batch := range [][]scraper.Delegation{firstBatch, overlappingBatch} {
+			mustSaveBatch(t, materializedStore, batch)
+			mustSaveBatch(t, streamingStore, batch)
+		}
+
+		// Assert
+		assertStoredRowsEqual(t, materializedDB, streamingDB)
+		assertCheckpointsEqual(t, materializedStore, streamingStore, 1000)
+	})
+}
+
+// TestStoreBlockHash verifies optional block hashes are persisted
+func TestStoreBlockHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stores block hashes and NULL when missing", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		batch := delegations(1, 2)
+		batch[0].BlockHash = "BLockHashOne"
+
+		// Act
+		_, err := store.SaveBatch(t.Context(), batch)
+
+		// Assert
+		require.NoError(t, err)
+		assertStoredBlockHash(t, db, 1, "BLockHashOne")
+		assertStoredBlockHash(t, db, 2, "")
+	})
+}
+
+// TestStoreWriteNotification verifies listeners are notified of committed batches
+func TestStoreWriteNotification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it notifies the write channel when a batch commits", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		listener, err := db.Acquire(t.Context())
+		require.NoError(t, err)
+		defer listener.Release()
+		_, err = listener.Exec(t.Context(), "LISTEN "+pgxstore.WriteChannel)
+		require.NoError(t, err)
+
+		// Act
+		_, err = store.SaveBatch(t.Context(), delegations(1, 2))
+
+		// Assert
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		notification, err := listener.Conn().WaitForNotification(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, pgxstore.WriteChannel, notification.Channel)
+	})
+}
+
+// TestStoreTimestampNormalization verifies timestamps are stored in UTC with the UTC year
+func TestStoreTimestampNormalization(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it files a local-zone new year timestamp under its UTC year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		// 2025-01-01 01:30 in UTC+2 is still 2024-12-31 23:30 UTC
+		eastOfUTC := time.FixedZone("UTC+2", 2*60*60)
+		batch := delegations(1, 1)
+		batch[0].Timestamp = time.Date(2025, 1, 1, 1, 30, 0, 0, eastOfUTC)
+
+		// Act
+		_, err := store.SaveBatch(t.Context(), batch)
+
+		// Assert
+		require.NoError(t, err)
+		assertStoredYear(t, db, 1, 2024)
+		assertStoredTimestamp(t, db, 1, time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC))
+	})
+}
+
+// TestStoreSaveBatchCancellation verifies a save cancelled mid-transaction is rolled back
+// and reported as context.Canceled
+func TestStoreSaveBatchCancellation(t *testing.T) {
+	t.Parallel()
+
+	strategies := []struct {
+		name string
+		opts []pgxstore.Option
+	}{
+		{name: "it rolls back a cancelled temp table save"},
+		{name: "it rolls back a cancelled direct insert save", opts: []pgxstore.Option{pgxstore.WithSmallBatchThreshold(100)}},
+	}
+
+	for _, strategy := range strategies {
+		t.Run(strategy.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+			defer db.Close()
+			store, _ := pgxstore.New(db, strategy.opts...)
+
+			release := lockCheckpoint(t, db)
+			defer release()
+			ctx, cancel := context.WithCancel(t.Context())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			// Act
+			_, err := store.SaveBatch(ctx, delegations(1, 5))
+
+			// Assert
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Empty(t, selectDelegationRows(t, db), "A cancelled save should store nothing")
+		})
+	}
+}
+
+// lockCheckpoint holds the checkpoint row lock until release is called, so any save blocks
+// on its checkpoint update with the batch already written inside its transaction
+func lockCheckpoint(t *testing.T, db *pgxpool.Pool) (release func()) {
+	t.Helper()
+
+	tx, err := db.Begin(t.Context())
+	require.NoError(t, err)
+	release = func() { _ = tx.Rollback(context.Background()) }
+
+	if _, err = tx.Exec(t.Context(), "SELECT last_id FROM scraper_checkpoint FOR UPDATE"); err != nil {
+		release()
+		require.NoError(t, err)
+	}
+	return release
+}
+
+// TestStoreCheckpointNeverRegresses verifies an out-of-order batch cannot move the checkpoint backwards
+func TestStoreCheckpointNeverRegresses(t *testing.T) {
+	t.Parallel()
+
+	strategies := []struct {
+		name string
+		opts []pgxstore.Option
+	}{
+		{name: "it keeps the higher checkpoint via temp table"},
+		{name: "it keeps the higher checkpoint via direct insert", opts: []pgxstore.Option{pgxstore.WithSmallBatchThreshold(100)}},
+	}
+
+	for _, strategy := range strategies {
+		t.Run(strategy.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+			defer db.Close()
+			store, _ := pgxstore.New(db, strategy.opts...)
+
+			mustSaveBatch(t, store, delegations(6, 10))
+
+			// Act
+			checkpoint, err := store.SaveBatch(t.Context(), delegations(1, 5))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, int64(10), checkpoint, "SaveBatch should report the persisted checkpoint")
+			assertCheckpointNeverBelow(t, store, 10)
+		})
+	}
+}
+
+// TestStoreSortBeforeInsert verifies unsorted batches are stored and checkpointed like sorted ones
+func TestStoreSortBeforeInsert(t *testing.T) {
+	t.Parallel()
+
+	strategies := []struct {
+		name string
+		opts []pgxstore.Option
+	}{
+		{name: "it sorts shuffled batches via temp table"},
+		{name: "it sorts shuffled batches via streaming copy", opts: []pgxstore.Option{pgxstore.WithStreamingCopy(true)}},
+		{name: "it sorts shuffled batches via direct insert", opts: []pgxstore.Option{pgxstore.WithSmallBatchThreshold(1000)}},
+	}
+
+	for _, strategy := range strategies {
+		t.Run(strategy.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			sortedDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+			defer sortedDB.Close()
+			sortedStore, _ := pgxstore.New(sortedDB, strategy.opts...)
+
+			shuffledDB := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+			defer shuffledDB.Close()
+			shuffledStore, _ := pgxstore.New(shuffledDB, append(strategy.opts, pgxstore.WithSortBeforeInsert(true))...)
+
+			batch := shuffled(delegations(1, 200))
+			require.NotEqual(t, int64(200), batch[len(batch)-1].ID, "The highest ID must not come last")
+			original := slices.Clone(batch)
+
+			// Act
+			mustSaveBatch(t, sortedStore, delegations(1, 200))
+			checkpoint, err := shuffledStore.SaveBatch(t.Context(), batch)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, int64(200), checkpoint, "The checkpoint should be the highest ID in the batch")
+			assert.Equal(t, original, batch, "The caller's batch should not be reordered")
+			assertStoredRowsEqual(t, sortedDB, shuffledDB)
+			assertCheckpointsEqual(t, sortedStore, shuffledStore, 200)
+		})
+	}
+}
+
+// TestStoreFindIDGaps verifies holes in the stored ID sequence are reported
+func TestStoreFindIDGaps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports each pair of non-adjacent stored IDs", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 2))
+		mustSaveBatch(t, store, delegations(5, 6))
+		mustSaveBatch(t, store, delegations(10, 10))
+
+		// Act
+		gaps, err := collectIDGaps(t, store)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []scraper.Gap{
+			{AfterID: 2, BeforeID: 5, StoredUpTo: 2},
+			{AfterID: 6, BeforeID: 10, StoredUpTo: 4},
+		}, gaps)
+	})
+
+	t.Run("it reports no gaps for a contiguous sequence", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 5))
+
+		// Act
+		gaps, err := collectIDGaps(t, store)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, gaps)
+	})
+
+	t.Run("it stops the scan at the first error returned by yield", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 2))
+		mustSaveBatch(t, store, delegations(5, 6))
+		mustSaveBatch(t, store, delegations(10, 10))
+
+		stop := errors.New("stop")
+		var yielded int
+
+		// Act
+		err := store.FindIDGaps(t.Context(), func(scraper.Gap) error {
+			yielded++
+			return stop
+		})
+
+		// Assert
+		require.ErrorIs(t, err, stop)
+		assert.NotErrorIs(t, err, pgxstore.ErrFindGapsFailed)
+		assert.Equal(t, 1, yielded)
+	})
+}
+
+// collectIDGaps gathers every gap FindIDGaps streams
+func collectIDGaps(t *testing.T, store *pgxstore.Store) ([]scraper.Gap, error) {
+	t.Helper()
+
+	var gaps []scraper.Gap
+	err := store.FindIDGaps(t.Context(), func(gap scraper.Gap) error {
+		gaps = append(gaps, gap)
+		return nil
+	})
+	return gaps, err
+}
+
+// TestStoreCheckpointReconciliation verifies a stale checkpoint can be raised to the stored rows
+func TestStoreCheckpointReconciliation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports the highest stored delegation ID", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 5))
+
+		// Act
+		maxID, err := store.MaxDelegationID(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), maxID)
+	})
+
+	t.Run("it raises a stale checkpoint but never lowers it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 5))
+		forceCheckpoint(t, db, 2)
+
+		// Act
+		require.NoError(t, store.AdvanceCheckpoint(t.Context(), 5))
+		require.NoError(t, store.AdvanceCheckpoint(t.Context(), 3))
+
+		// Assert
+		checkpoint, err := store.LastProcessedID(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), checkpoint)
+	})
+}
+
+// TestStoreCheckpointExists verifies a missing checkpoint row is told apart from a checkpoint of 0
+func TestStoreCheckpointExists(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports a missing checkpoint row although LastProcessedID is 0", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		deleteCheckpoint(t, db)
+
+		// Act
+		exists, err := store.CheckpointExists(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, exists)
+		checkpoint, err := store.LastProcessedID(t.Context())
+		require.NoError(t, err)
+		assert.Zero(t, checkpoint)
+	})
+
+	t.Run("it reports a checkpoint initialized at 0", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		// Act
+		exists, err := store.CheckpointExists(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, exists)
+		checkpoint, err := store.LastProcessedID(t.Context())
+		require.NoError(t, err)
+		assert.Zero(t, checkpoint)
+	})
+
+	t.Run("it reports the checkpoint created by the first saved batch", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		deleteCheckpoint(t, db)
+
+		// Act
+		mustSaveBatch(t, store, delegations(1, 3))
+		exists, err := store.CheckpointExists(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
+
+// TestStoreCountExisting verifies the bulk existence check used to skip stored batches
+func TestStoreCountExisting(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts every ID of a fully stored batch", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 5))
+
+		// Act
+		count, err := store.CountExisting(t.Context(), []int64{1, 2, 3, 4, 5})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+	})
+
+	t.Run("it counts only the stored IDs of a partially new batch", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+		mustSaveBatch(t, store, delegations(1, 3))
+
+		// Act
+		count, err := store.CountExisting(t.Context(), []int64{2, 3, 4, 5})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+// TestStoreAdvanceCheckpointIf verifies the optimistic checkpoint update
+func TestStoreAdvanceCheckpointIf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it advances when the checkpoint matches the expected value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 5)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		// Act
+		advanced, err := store.AdvanceCheckpointIf(t.Context(), 5, 10)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, advanced)
+		assertCheckpointNeverBelow(t, store, 10)
+	})
+
+	t.Run("it leaves the checkpoint alone once another writer moved it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 5)
+		defer db.Close()
+		winner, _ := pgxstore.New(db)
+		loser, _ := pgxstore.New(db)
+		advanced, err := winner.AdvanceCheckpointIf(t.Context(), 5, 10)
+		require.NoError(t, err)
+		require.True(t, advanced)
+
+		// Act
+		advanced, err = loser.AdvanceCheckpointIf(t.Context(), 5, 8)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, advanced, "A stale expected value must not advance the checkpoint")
+		assertCheckpointNeverBelow(t, loser, 10)
+	})
+
+	t.Run("it lets exactly one of many concurrent writers advance", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		const writers = 8
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 5)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		// Act
+		var (
+			wg   sync.WaitGroup
+			wins atomic.Int64
+		)
+		for i := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				advanced, err := store.AdvanceCheckpointIf(t.Context(), 5, int64(10+i))
+				assert.NoError(t, err)
+				if advanced {
+					wins.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		assert.Equal(t, int64(1), wins.Load())
+		checkpoint, err := store.LastProcessedID(t.Context())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, checkpoint, int64(10))
+	})
+}
+
+// TestReconnectingStore verifies writes recover once the pool is recreated
+func TestReconnectingStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it recreates a closed pool and saves the batch", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		closedPool := closedPoolFor(t, db)
+		store, closer := pgxstore.NewReconnecting(closedPool, connectorFor(db),
+			pgxstore.WithReconnectThreshold(1),
+			pgxstore.WithReconnectBackoff(func(int) time.Duration { return 0 }),
+		)
+		defer closer()
+
+		// Act
+		saved, err := store.SaveBatch(t.Context(), delegations(1, 5))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), saved)
+		assert.Len(t, selectDelegationRows(t, db), 5)
+		checkpoint, err := store.LastProcessedID(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), checkpoint, "Reads should use the recreated pool")
+	})
+
+	t.Run("it keeps failing until the threshold is reached", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, closer := pgxstore.NewReconnecting(closedPoolFor(t, db), connectorFor(db),
+			pgxstore.WithReconnectThreshold(2),
+			pgxstore.WithReconnectBackoff(func(int) time.Duration { return 0 }),
+		)
+		defer closer()
+
+		// Act
+		_, first := store.SaveBatch(t.Context(), delegations(1, 5))
+		_, second := store.SaveBatch(t.Context(), delegations(1, 5))
+
+		// Assert
+		assert.ErrorIs(t, first, pgxstore.ErrTransactionFailed)
+		require.NoError(t, second)
+		assert.Len(t, selectDelegationRows(t, db), 5)
+	})
+
+	t.Run("it keeps the replaced pool open for calls still using it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		seed, _ := pgxstore.New(db)
+		mustSaveBatch(t, seed, delegations(1, 2))
+		mustSaveBatch(t, seed, delegations(5, 6))
+		mustSaveBatch(t, seed, delegations(10, 10))
+
+		pool, failDials := poolFailingNewConnections(t, db)
+		store, closer := pgxstore.NewReconnecting(pool, connectorFor(db),
+			pgxstore.WithReconnectThreshold(1),
+			pgxstore.WithReconnectBackoff(func(int) time.Duration { return 0 }),
+		)
+		defer closer()
+
+		// Act: the scan holds the old pool's only connection while a save forces a reconnect
+		var gaps []scraper.Gap
+		var saveErr error
+		err := store.FindIDGaps(t.Context(), func(gap scraper.Gap) error {
+			if len(gaps) == 0 {
+				failDials()
+				_, saveErr = store.SaveBatch(t.Context(), delegations(3, 4))
+			}
+			gaps = append(gaps, gap)
+			return nil
+		})
+
+		// Assert
+		require.NoError(t, saveErr, "The save should succeed on the recreated pool")
+		require.NoError(t, err, "The scan should finish on the replaced pool")
+		assert.Equal(t, []scraper.Gap{
+			{AfterID: 2, BeforeID: 5, StoredUpTo: 2},
+			{AfterID: 6, BeforeID: 10, StoredUpTo: 4},
+		}, gaps)
+		assert.Len(t, selectDelegationRows(t, db), 7)
+	})
+}
+
+// poolFailingNewConnections returns a pool for db whose new connections fail once failDials was called
+func poolFailingNewConnections(t *testing.T, db *pgxpool.Pool) (pool *pgxpool.Pool, failDials func()) {
+	t.Helper()
+
+	cfg, err := pgxpool.ParseConfig(db.Config().ConnString())
+	require.NoError(t, err)
+
+	var failing atomic.Bool
+	dial := cfg.ConnConfig.DialFunc
+	cfg.ConnConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if failing.Load() {
+			return nil, errors.New("connection refused")
+		}
+		return dial(ctx, network, addr)
+	}
+
+	pool, err = pgxpool.NewWithConfig(t.Context(), cfg)
+	require.NoError(t, err)
+	return pool, func() { failing.Store(true) }
+}
+
+// closedPoolFor returns an already closed pool for db, simulating a pool that can never recover
+func closedPoolFor(t *testing.T, db *pgxpool.Pool) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(t.Context(), db.Config().ConnString())
+	require.NoError(t, err)
+	pool.Close()
+	return pool
+}
+
+// connectorFor opens fresh pools to the same database as db
+func connectorFor(db *pgxpool.Pool) pgxstore.Connector {
+	return func(ctx context.Context) (*pgxpool.Pool, error) {
+		return pgxdb.NewConnection(ctx, db.Config().ConnString())
+	}
+}
+
+// TestStoreLastProcessedTimestamp verifies the timestamp checkpoint is read from stored delegations
+func TestStoreLastProcessedTimestamp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports the newest stored timestamp in UTC", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		batch := delegations(1, 3)
+		batch[1].Timestamp = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) // newest, though not the highest ID
+		mustSaveBatch(t, store, batch)
+
+		// Act
+		lastTimestamp, err := store.LastProcessedTimestamp(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), lastTimestamp)
+	})
+
+	t.Run("it reports the zero time when nothing is stored", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		store, _ := pgxstore.New(db)
+
+		// Act
+		lastTimestamp, err := store.LastProcessedTimestamp(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, lastTimestamp.IsZero())
+	})
+}
+
+// BenchmarkStoreSaveBatch compares the temp-table and direct insert paths for small batches
+func BenchmarkStoreSaveBatch(b *testing.B) {
+	const batchSize = 50
+
+	strategies := []struct {
+		name string
+		opts []pgxstore.Option
+	}{
+		{name: "temp table", opts: nil},
+		{name: "direct insert", opts: []pgxstore.Option{pgxstore.WithSmallBatchThreshold(batchSize + 1)}},
+	}
+
+	for _, strategy := range strategies {
+		b.Run(strategy.name, func(b *testing.B) {
+			db := migratortest.CreateScraperTestDatabase(b, migrationsDir, 0)
+			defer db.Close()
+			store, _ := pgxstore.New(db, strategy.opts...)
+
+			b.ResetTimer()
+			for i := range b.N {
+				startID := int64(i*batchSize + 1)
+				batch := delegations(startID, startID+batchSize-1)
+				if _, err := store.SaveBatch(b.Context(), batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStoreSaveBatchCopy compares allocations of the materialized and streaming
+// temp-table copy for a production-sized chunk
+func BenchmarkStoreSaveBatchCopy(b *testing.B) {
+	const batchSize = int64(scraper.DefaultChunkSize)
+
+	strategies := []struct {
+		name string
+		opts []pgxstore.Option
+	}{
+		{name: "materialized copy", opts: nil},
+		{name: "streaming copy", opts: []pgxstore.Option{pgxstore.WithStreamingCopy(true)}},
+	}
+
+	for _, strategy := range strategies {
+		b.Run(strategy.name, func(b *testing.B) {
+			db := migratortest.CreateScraperTestDatabase(b, migrationsDir, 0)
+			defer db.Close()
+			store, _ := pgxstore.New(db, strategy.opts...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range int64(b.N) {
+				b.StopTimer()
+				startID := i*batchSize + 1
+				batch := delegations(startID, startID+batchSize-1)
+				b.StartTimer()
+
+				if _, err := store.SaveBatch(b.Context(), batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// mustSaveBatch saves batch and fails the test on error
+func mustSaveBatch(t *testing.T, store scraper.Store, batch []scraper.Delegation) {
+	t.Helper()
+
+	_, err := store.SaveBatch(t.Context(), batch)
+	require.NoError(t, err)
+}
+
+// shuffled returns batch in a fixed pseudo-random order
+func shuffled(batch []scraper.Delegation) []scraper.Delegation {
+	rng := rand.New(rand.NewPCG(1, 2))
+	rng.Shuffle(len(batch), func(i, j int) { batch[i], batch[j] = batch[j], batch[i] })
+	return batch
+}
+
+// delegations builds sequential test delegations with ids in [from, to]
+func delegations(from, to int64) []scraper.Delegation {
+	result := make([]scraper.Delegation, 0, to-from+1)
+	for id := from; id <= to; id++ {
+		result = append(result, scraper.Delegation{
+			ID:        id,
+			Level:     100 + id,
+			Timestamp: time.Date(2024, 1, 1, 0, 0, int(id%60), 0, time.UTC),
+			Delegator: fmt.Sprintf("tz1%05d", id),
+			Amount:    1000000 + id,
+		})
+	}
+	return result
+}
+
+// assertStoredRowsEqual verifies both databases contain exactly the same delegations
+func assertStoredRowsEqual(t *testing.T, expectedDB, actualDB *pgxpool.Pool) {
+	t.Helper()
+
+	expected := selectDelegationRows(t, expectedDB)
+	actual := selectDelegationRows(t, actualDB)
+
+	require.NotEmpty(t, expected, "Expected delegations to be stored")
+	assert.Equal(t, expected, actual, "Both write paths should store identical rows")
+}
+
+// assertCheckpointsEqual verifies both stores advanced the checkpoint to the same ID
+func assertCheckpointsEqual(t *testing.T, expected, actual *pgxstore.Store, expectedID int64) {
+	t.Helper()
+
+	expectedCheckpoint, err := expected.LastProcessedID(t.Context())
+	require.NoError(t, err)
+	actualCheckpoint, err := actual.LastProcessedID(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedID, expectedCheckpoint)
+	assert.Equal(t, expectedCheckpoint, actualCheckpoint, "Both write paths should advance the checkpoint identically")
+}
+
+// assertStoredBlockHash verifies the stored block hash for a delegation; empty means NULL
+func assertStoredBlockHash(t *testing.T, db *pgxpool.Pool, id int64, expected string) {
+	t.Helper()
+
+	var hash *string
+	err := db.QueryRow(t.Context(), "SELECT block_hash FROM delegations WHERE id = $1", id).Scan(&hash)
+	require.NoError(t, err)
+
+	if expected == "" {
+		assert.Nil(t, hash, "Delegation %d should have NULL block hash", id)
+		return
+	}
+	require.NotNil(t, hash, "Delegation %d should have a block hash", id)
+	assert.Equal(t, expected, *hash)
+}
+
+// assertStoredYear verifies the year column of a stored delegation
+func assertStoredYear(t *testing.T, db *pgxpool.Pool, id int64, expected int) {
+	t.Helper()
+
+	var year int
+	err := db.QueryRow(t.Context(), "SELECT year FROM delegations WHERE id = $1", id).Scan(&year)
+	require.NoError(t, err)
+	assert.Equal(t, expected, year, "Delegation %d should be filed under year %d", id, expected)
+}
+
+// assertStoredTimestamp verifies the stored instant of a delegation
+func assertStoredTimestamp(t *testing.T, db *pgxpool.Pool, id int64, expected time.Time) {
+	t.Helper()
+
+	var timestamp time.Time
+	err := db.QueryRow(t.Context(), "SELECT timestamp FROM delegations WHERE id = $1", id).Scan(&timestamp)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(timestamp), "Delegation %d should be stored at %s, got %s", id, expected, timestamp)
+}
+
+// assertCheckpointNeverBelow verifies the stored checkpoint did not regress below the highest saved ID
+func assertCheckpointNeverBelow(t *testing.T, store *pgxstore.Store, highestSavedID int64) {
+	t.Helper()
+
+	checkpoint, err := store.LastProcessedID(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, highestSavedID, checkpoint, "Checkpoint must never move backwards after saving an older batch")
+}
+
+// forceCheckpoint overwrites the checkpoint to simulate manual edits
+func forceCheckpoint(t *testing.T, db *pgxpool.Pool, id int64) {
+	t.Helper()
+
+	_, err := db.Exec(t.Context(), "UPDATE scraper_checkpoint SET last_id = $1", id)
+	require.NoError(t, err)
+}
+
+// deleteCheckpoint removes the checkpoint row to simulate a database the migrator never initialized
+func deleteCheckpoint(t *testing.T, db *pgxpool.Pool) {
+	t.Helper()
+
+	_, err := db.Exec(t.Context(), "DELETE FROM scraper_checkpoint")
+	require.NoError(t, err)
+}
+
+// selectDelegationRows reads all stored delegations as comparable strings
+func selectDelegationRows(t *testing.T, db *pgxpool.Pool) []string {
+	t.Helper()
+
+	rows, err := db.Query(t.Context(), "SELECT id, timestamp, amount, delegator, level, year, COALESCE(block_hash, '') FROM delegations ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var (
+			id, amount, level int64
+			timestamp         time.Time
+			delegator, hash   string
+			year              int
+		)
+		require.NoError(t, rows.Scan(&id, &timestamp, &amount, &delegator, &level, &year, &hash))
+		result = append(result, fmt.Sprintf("%d|%s|%d|%s|%d|%d|%s", id, timestamp.UTC().Format(time.RFC3339), amount, delegator, level, year, hash))
+	}
+	require.NoError(t, rows.Err())
+
+	return result
+}
@@ -1,8 +1,14 @@
 package scraper
 
+import "sync"
+
+// workerQueueSize bounds how many events may wait for each worker
+const workerQueueSize = 16
+
 // Subscriber handles event subscriptions.
 type Subscriber struct {
 	done                   chan struct{}
+	workers                int
 	backfillHandler        func(BackfillDone)
 	backfillStartedHandler func(BackfillStarted)
 	backfillSyncHandler    func(BackfillSyncCompleted)
@@ -11,6 +17,7 @@ type Subscriber struct {
 	pollStartedHandler     func(PollingStarted)
 	pollShutdownHandler    func(PollingShutdown)
 	pollingErrorHandler    func(PollingError)
+	heartbeatHandler       func(Heartbeat)
 }
 
 // OnBackfillDone sets the handler for BackfillDone events
@@ -53,6 +60,28 @@ func OnPollingError(fn func(PollingError)) func(*Subscriber) {
 	return func(s *Subscriber) { s.pollingErrorHandler = fn }
 }
 
+// OnHeartbeat sets the handler for Heartbeat events
+func OnHeartbeat(fn func(Heartbeat)) func(*Subscriber) {
+	return func(s *Subscriber) { s.heartbeatHandler = fn }
+}
+
+// WithWorkers dispatches events to a bounded pool of n workers instead of handling
+// them on the single dispatch goroutine, so a slow handler for one event type does
+// not hold up the others.
+//
+// Ordering trade-offs:
+//   - Events of the same type always go to the same worker and are handled in order.
+//   - Events of different types may be handled concurrently and out of order, e.g. a
+//     BackfillDone handler can run before the last BackfillSyncCompleted handler.
+//   - Handlers for different types must therefore be safe to run concurrently.
+//   - Event types are assigned to workers by a fixed index, so n above the number of
+//     event types adds no concurrency; when a worker's queue is full, dispatch blocks.
+//
+// n <= 1 keeps the default sequential dispatch.
+func WithWorkers(n int) func(*Subscriber) {
+	return func(s *Subscriber) { s.workers = n }
+}
+
 // NewSubscriber creates a Subscriber with the given options and starts the dispatch loop.
 // Returns a closer function that waits for all events to be processed.
 //
@@ -81,6 +110,7 @@ func NewSubscriber(events <-chan Event, opts ...func(*Subscriber)) func() {
 		pollStartedHandler:     func(PollingStarted) {},        // nop by default
 		pollShutdownHandler:    func(PollingShutdown) {},       // nop by default
 		pollingErrorHandler:    func(PollingError) {},          // nop by default
+		heartbeatHandler:       func(Heartbeat) {},             // nop by default
 	}
 
 	for _, opt := range opts {
@@ -90,29 +120,91 @@ func NewSubscriber(events <-chan Event, opts ...func(*Subscriber)) func() {
 	// Start the dispatch loop immediately
 	go func() {
 		defer close(s.done)
-		for ev := range events {
-			switch e := ev.(type) {
-			case BackfillStarted:
-				s.backfillStartedHandler(e)
-			case BackfillSyncCompleted:
-				s.backfillSyncHandler(e)
-			case BackfillDone:
-				s.backfillHandler(e)
-			case BackfillError:
-				s.backfillErrorHandler(e)
-			case PollingStarted:
-				s.pollStartedHandler(e)
-			case PollingSyncCompleted:
-				s.pollingSyncHandler(e)
-			case PollingShutdown:
-				s.pollShutdownHandler(e)
-			case PollingError:
-				s.pollingErrorHandler(e)
+		if s.workers <= 1 {
+			for ev := range events {
+				s.handle(ev)
 			}
+			return
 		}
+		s.dispatchToWorkers(events)
 	}()
 
 	return func() {
 		<-s.done
 	}
 }
+
+// dispatchToWorkers routes each event to the worker owning its type and waits until all are handled
+func (s *Subscriber) dispatchToWorkers(events <-chan Event) {
+	queues := make([]chan Event, s.workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan Event, workerQueueSize)
+		wg.Add(1)
+		go func(queue <-chan Event) {
+			defer wg.Done()
+			for ev := range queue {
+				s.handle(ev)
+			}
+		}(queues[i])
+	}
+
+	for ev := range events {
+		queues[eventTypeIndex(ev)%len(queues)] <- ev
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+}
+
+// handle invokes the handler registered for the event type
+func (s *Subscriber) handle(ev Event) {
+	switch e := ev.(type) {
+	case BackfillStarted:
+		s.backfillStartedHandler(e)
+	case BackfillSyncCompleted:
+		s.backfillSyncHandler(e)
+	case BackfillDone:
+		s.backfillHandler(e)
+	case BackfillError:
+		s.backfillErrorHandler(e)
+	case PollingStarted:
+		s.pollStartedHandler(e)
+	case PollingSyncCompleted:
+		s.pollingSyncHandler(e)
+	case PollingShutdown:
+		s.pollShutdownHandler(e)
+	case PollingError:
+		s.pollingErrorHandler(e)
+	case Heartbeat:
+		s.heartbeatHandler(e)
+	}
+}
+
+// eventTypeIndex returns a stable index per event type, used to pin each type to one worker
+func eventTypeIndex(ev Event) int {
+	switch ev.(type) {
+	case BackfillStarted:
+		return 0
+	case BackfillSyncCompleted:
+		return 1
+	case BackfillDone:
+		return 2
+	case BackfillError:
+		return 3
+	case PollingStarted:
+		return 4
+	case PollingSyncCompleted:
+		return 5
+	case PollingShutdown:
+		return 6
+	case PollingError:
+		return 7
+	case Heartbeat:
+		return 8
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,161 @@
+package scraper_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/scraper"
+)
+
+func TestSubscriberWorkers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it preserves ordering within an event type", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		const count = 100
+		events := make(chan scraper.Event)
+
+		var handled []int
+		closer := scraper.NewSubscriber(events,
+			scraper.WithWorkers(4),
+			scraper.OnPollingSyncCompleted(func(e scraper.PollingSyncCompleted) {
+				handled = append(handled, e.Fetched)
+			}),
+			scraper.OnBackfillSyncCompleted(func(scraper.BackfillSyncCompleted) {}),
+		)
+
+		// Act
+		for i := range count {
+			events <- scraper.PollingSyncCompleted{Fetched: i}
+			events <- scraper.BackfillSyncCompleted{Fetched: i}
+		}
+		close(events)
+		closer()
+
+		// Assert
+		assertSequence(t, handled, count)
+	})
+
+	t.Run("it handles different event types concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		events := make(chan scraper.Event, 2)
+		released := make(chan struct{})
+
+		var releasedInTime bool
+		closer := scraper.NewSubscriber(events,
+			scraper.WithWorkers(8),
+			scraper.OnPollingSyncCompleted(func(scraper.PollingSyncCompleted) {
+				// Blocks until the other type's handler runs; sequential dispatch would time out
+				select {
+				case <-released:
+					releasedInTime = true
+				case <-time.After(time.Second):
+				}
+			}),
+			scraper.OnBackfillSyncCompleted(func(scraper.BackfillSyncCompleted) {
+				close(released)
+			}),
+		)
+
+		// Act
+		events <- scraper.PollingSyncCompleted{}
+		events <- scraper.BackfillSyncCompleted{}
+		close(events)
+		closer()
+
+		// Assert
+		assert.True(t, releasedInTime, "A slow handler should not block handlers of other event types")
+	})
+}
+
+// Compile-time proof that every lifecycle event satisfies the sealed Event interface
+var _ = []scraper.Event{
+	scraper.BackfillStarted{},
+	scraper.BackfillSyncCompleted{},
+	scraper.BackfillDone{},
+	scraper.BackfillError{},
+	scraper.PollingStarted{},
+	scraper.PollingSyncCompleted{},
+	scraper.PollingShutdown{},
+	scraper.PollingError{},
+	scraper.Heartbeat{},
+}
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it dispatches every event type to its handler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		events := make(chan scraper.Event, 9)
+
+		var handled []string
+		record := func(name string) { handled = append(handled, name) }
+		closer := scraper.NewSubscriber(events,
+			scraper.OnBackfillStarted(func(scraper.BackfillStarted) { record("BackfillStarted") }),
+			scraper.OnBackfillSyncCompleted(func(scraper.BackfillSyncCompleted) { record("BackfillSyncCompleted") }),
+			scraper.OnBackfillDone(func(scraper.BackfillDone) { record("BackfillDone") }),
+			scraper.OnBackfillError(func(scraper.BackfillError) { record("BackfillError") }),
+			scraper.OnPollingStarted(func(scraper.PollingStarted) { record("PollingStarted") }),
+			scraper.OnPollingSyncCompleted(func(scraper.PollingSyncCompleted) { record("PollingSyncCompleted") }),
+			scraper.OnPollingShutdown(func(scraper.PollingShutdown) { record("PollingShutdown") }),
+			scraper.OnPollingError(func(scraper.PollingError) { record("PollingError") }),
+			scraper.OnHeartbeat(func(scraper.Heartbeat) { record("Heartbeat") }),
+		)
+
+		// Act
+		events <- scraper.BackfillStarted{}
+		events <- scraper.BackfillSyncCompleted{}
+		events <- scraper.BackfillDone{}
+		events <- scraper.BackfillError{}
+		events <- scraper.PollingStarted{}
+		events <- scraper.PollingSyncCompleted{}
+		events <- scraper.PollingShutdown{}
+		events <- scraper.PollingError{}
+		events <- scraper.Heartbeat{}
+		close(events)
+		closer()
+
+		// Assert
+		assert.Equal(t, []string{
+			"BackfillStarted", "BackfillSyncCompleted", "BackfillDone", "BackfillError",
+			"PollingStarted", "PollingSyncCompleted", "PollingShutdown", "PollingError",
+			"Heartbeat",
+		}, handled)
+	})
+
+	t.Run("it cannot be implemented outside the package", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		eventType := reflect.TypeFor[scraper.Event]()
+
+		// Act & Assert
+		for _, nonEvent := range []reflect.Type{
+			reflect.TypeFor[string](),
+			reflect.TypeFor[error](),
+			reflect.TypeFor[struct{ Fetched int }](),
+			reflect.TypeFor[scraper.SyncResult](),
+		} {
+			assert.False(t, nonEvent.Implements(eventType), "%s should not be an Event", nonEvent)
+		}
+	})
+}
+
+func assertSequence(t *testing.T, handled []int, count int) {
+	t.Helper()
+
+	expected := make([]int, count)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(t, expected, handled, "Events of one type should be handled in order")
+}
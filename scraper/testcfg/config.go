@@ -20,8 +20,9 @@ type Config struct {
 	ShutdownTimeout time.Duration `env:"SCRAPER_TEST_SHUTDOWN_TIMEOUT" envDefault:"2s"`
 
 	// Test database setup (for migrator/migratortest)
-	Checkpoint  int64         `env:"SCRAPER_TEST_CHECKPOINT" envDefault:"1939557726552064"`
-	SeedTimeout time.Duration `env:"SCRAPER_TEST_SEED_TIMEOUT" envDefault:"5s"`
+	Checkpoint     int64         `env:"SCRAPER_TEST_CHECKPOINT" envDefault:"1939557726552064"`
+	SeedTimeout    time.Duration `env:"SCRAPER_TEST_SEED_TIMEOUT" envDefault:"5s"`
+	SeedMaxRecords uint64        `env:"SCRAPER_TEST_SEED_MAX_RECORDS" envDefault:"0"` // 0 = seed until the API runs dry
 }
 
 // parseConfig wraps env.Parse to return (Config, error) for use with env.Must
@@ -2,20 +2,177 @@ package api
 
 // DelegationsRequest represents the query parameters for GET /xtz/delegations
 type DelegationsRequest struct {
-	Year    uint64 `query:"year"`     // Optional year filter in YYYY format
-	Page    uint64 `query:"page"`     // Page number for pagination (default: 1)
-	PerPage uint64 `query:"per_page"` // Number of items per page (default: 50, max: 100)
+	Year         uint64   `query:"year"`          // Optional year filter in YYYY format
+	Page         uint64   `query:"page"`          // Page number for pagination (default: 1)
+	PerPage      uint64   `query:"per_page"`      // Number of items per page (default: 50, max: 100)
+	EchoCriteria bool     `query:"echo_criteria"` // Echo the applied criteria in the response (default: false)
+	Fields       []string `query:"fields"`        // Comma-separated subset of DelegationFields to return (default: all)
+	MinLevel     uint64   `query:"min_level"`     // Optional inclusive lower block level bound
+	MaxLevel     uint64   `query:"max_level"`     // Optional inclusive upper block level bound
+
+	IncludeCreatedAt bool `query:"include_created_at"` // Add created_at to the returned fields (default: false)
+}
+
+// DelegationsSinceRequest represents the query parameters for GET /xtz/delegations/since
+type DelegationsSinceRequest struct {
+	ID    uint64 `query:"id"`    // Return delegations with ID greater than this value (default: 0)
+	Limit uint64 `query:"limit"` // Maximum number of items to return (default: 50, max: 100)
+}
+
+// RecentDelegationsRequest represents the query parameters for GET /xtz/delegations/recent
+type RecentDelegationsRequest struct {
+	Limit uint64 `query:"limit"` // Number of most recent items to return (default: 20, capped at 100)
+}
+
+// TopDelegationsRequest represents the query parameters for GET /xtz/delegations/top
+type TopDelegationsRequest struct {
+	Limit uint64 `query:"limit"` // Number of largest items to return (default: 10, capped at 100)
+	Year  uint64 `query:"year"`  // Optional year filter in YYYY format
+}
+
+// StreamCursor is the data of the "latest" event opening GET /xtz/delegations/stream:
+// delegations with a higher ID follow as they are stored
+type StreamCursor struct {
+	ID int64 `json:"id"`
+}
+
+// AmountHistogramRequest represents the query parameters for GET /xtz/delegations/histogram
+type AmountHistogramRequest struct {
+	Buckets  uint64 `query:"buckets"`   // Number of equal-width amount buckets (default: 10, max: 100)
+	Year     uint64 `query:"year"`      // Optional year filter in YYYY format
+	MinLevel uint64 `query:"min_level"` // Optional inclusive lower block level bound
+	MaxLevel uint64 `query:"max_level"` // Optional inclusive upper block level bound
+}
+
+// DelegatorsCountRequest represents the query parameters for GET /xtz/delegators/count
+type DelegatorsCountRequest struct {
+	Year uint64 `query:"year"` // Optional year filter in YYYY format
+}
+
+// DelegatorsCountResponse represents the API response format for GET /xtz/delegators/count
+type DelegatorsCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// AmountBucket counts delegations with amounts in mutez between Min and Max, both inclusive
+type AmountBucket struct {
+	Min   string `json:"min"`
+	Max   string `json:"max"`
+	Count int64  `json:"count"`
+}
+
+// AmountHistogramResponse represents the API response format for GET /xtz/delegations/histogram
+type AmountHistogramResponse struct {
+	Buckets []AmountBucket `json:"buckets"` // Ordered by amount; empty when no delegation matches
+}
+
+// YearCount is the number of delegations filed under a year
+type YearCount struct {
+	Year  uint64 `json:"year"`
+	Count int64  `json:"count"`
+}
+
+// DelegationYearsResponse represents the API response format for GET /xtz/delegations/years
+type DelegationYearsResponse struct {
+	Years []YearCount `json:"years"` // Ordered by year ascending; empty when there are no delegations
 }
 
+// DailyCountsRequest represents the query parameters for GET /xtz/delegations/daily
+type DailyCountsRequest struct {
+	Year uint64 `query:"year"` // Required year in YYYY format
+}
+
+// DayCount is the number of delegations made on a UTC calendar day
+type DayCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
+// DailyCountsResponse represents the API response format for GET /xtz/delegations/daily
+type DailyCountsResponse struct {
+	Year uint64     `json:"year"`
+	Days []DayCount `json:"days"` // Ordered by date ascending; days without delegations are omitted
+}
+
+// CumulativeAmountsRequest represents the query parameters for GET /xtz/delegations/tvl
+type CumulativeAmountsRequest struct {
+	Year     uint64 `query:"year"`      // Optional year filter in YYYY format
+	MinLevel uint64 `query:"min_level"` // Optional inclusive lower block level bound
+	MaxLevel uint64 `query:"max_level"` // Optional inclusive upper block level bound
+}
+
+// MonthlyAmount is the amount delegated during a UTC calendar month and the running total
+type MonthlyAmount struct {
+	Month      string `json:"month"`      // YYYY-MM
+	Amount     string `json:"amount"`     // Delegated during the month, in mutez
+	Cumulative string `json:"cumulative"` // Delegated up to and including the month, in mutez
+}
+
+// CumulativeAmountsResponse represents the API response format for GET /xtz/delegations/tvl
+type CumulativeAmountsResponse struct {
+	Months []MonthlyAmount `json:"months"` // Ordered by month ascending; months without delegations are omitted
+}
+
+// CompareYearsRequest represents the query parameters for GET /xtz/delegations/compare
+type CompareYearsRequest struct {
+	YearA uint64 `query:"year_a"` // Required baseline year in YYYY format
+	YearB uint64 `query:"year_b"` // Required year compared against year_a
+}
+
+// YearTotals is the number and summed amount of delegations filed under a year
+type YearTotals struct {
+	Year   uint64 `json:"year"`
+	Count  int64  `json:"count"`
+	Amount string `json:"amount"` // Total amount in mutez
+}
+
+// YearComparisonResponse represents the API response format for GET /xtz/delegations/compare
+type YearComparisonResponse struct {
+	YearA       YearTotals `json:"year_a"`
+	YearB       YearTotals `json:"year_b"`
+	CountDelta  int64      `json:"count_delta"`  // year_b count minus year_a count
+	AmountDelta string     `json:"amount_delta"` // year_b amount minus year_a amount, in mutez
+}
+
+// Delegation field names that can be selected with the fields query parameter
+const (
+	FieldTimestamp = "timestamp"
+	FieldAmount    = "amount"
+	FieldDelegator = "delegator"
+	FieldLevel     = "level"
+	FieldAmountTez = "amount_tez"
+	FieldCreatedAt = "created_at"
+)
+
+// DelegationFields lists every selectable delegation field
+var DelegationFields = []string{FieldTimestamp, FieldAmount, FieldDelegator, FieldLevel, FieldAmountTez, FieldCreatedAt}
+
+// DefaultDelegationFields are returned when no fields are requested; amount_tez and created_at are opt-in
+var DefaultDelegationFields = []string{FieldTimestamp, FieldAmount, FieldDelegator, FieldLevel}
+
 // Delegation represents a single delegation in the API response
+// Fields not selected via the fields query parameter are left empty and omitted
 type Delegation struct {
-	Timestamp string `json:"timestamp"`
-	Amount    string `json:"amount"`
-	Delegator string `json:"delegator"`
-	Level     string `json:"level"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Amount    string `json:"amount,omitempty"`
+	Delegator string `json:"delegator,omitempty"`
+	Level     string `json:"level,omitempty"`
+	AmountTez string `json:"amount_tez,omitempty"` // Amount in tez with 6 decimals, only when requested
+	CreatedAt string `json:"created_at,omitempty"` // When the delegation was stored, only when requested
+}
+
+// AppliedCriteria echoes the effective criteria used after defaulting and validation
+type AppliedCriteria struct {
+	Year     uint64 `json:"year"`                // 0 means no year filtering
+	MinLevel int64  `json:"min_level,omitempty"` // Omitted when there is no lower level bound
+	MaxLevel int64  `json:"max_level,omitempty"` // Omitted when there is no upper level bound
+	Page     uint64 `json:"page"`
+	PerPage  uint64 `json:"per_page"`
+	Sort     string `json:"sort"`
 }
 
 // DelegationsResponse represents the API response format for GET /xtz/delegations
 type DelegationsResponse struct {
-	Data []Delegation `json:"data"`
+	Data    []Delegation     `json:"data"`
+	Applied *AppliedCriteria `json:"applied,omitempty"` // Present only when echo_criteria=true
 }
@@ -10,6 +10,7 @@ import (
 var (
 	ErrBadRequest          = errors.New(http.StatusText(http.StatusBadRequest))
 	ErrInternalServerError = errors.New(http.StatusText(http.StatusInternalServerError))
+	ErrServiceUnavailable  = errors.New(http.StatusText(http.StatusServiceUnavailable))
 )
 
 // Error represents a structured API error response
@@ -70,6 +71,14 @@ func InternalServerError(cause error) *Error {
 	}
 }
 
+func ServiceUnavailable(cause error) *Error {
+	return &Error{
+		cause:    cause,
+		message:  http.StatusText(http.StatusServiceUnavailable), // Never expose dependency failures
+		httpCode: http.StatusServiceUnavailable,
+	}
+}
+
 // Wrap transforms any error into a safe API error
 // If the error is already an API error, it returns it unchanged
 func Wrap(err error) *Error {
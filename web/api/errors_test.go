@@ -45,6 +45,21 @@ func TestAPIErrorHandling(t *testing.T) {
 		assert.Equal(t, internalErr, apiErr.Cause())             // Original error still available for logging
 	})
 
+	t.Run("it hides dependency failures for ServiceUnavailable", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		pingErr := errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+
+		// Act
+		apiErr := api.ServiceUnavailable(pingErr)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.HTTPCode())
+		assert.Equal(t, "Service Unavailable", apiErr.Error())
+		assert.Equal(t, pingErr, apiErr.Cause())
+	})
+
 	t.Run("it classifies unknown errors as InternalServerError", func(t *testing.T) {
 		t.Parallel()
 
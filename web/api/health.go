@@ -0,0 +1,6 @@
+package api
+
+// HealthStatus is the response of GET /healthz and of GET /readyz once the service is ready
+type HealthStatus struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+// DefaultBatchSize is the number of delegations fetched per keyset page
+const DefaultBatchSize = uint64(1000)
+
+// Sentinel errors for export failures
+var (
+	ErrFetchFailed = errors.New("export fetch failed")
+	ErrWriteFailed = errors.New("export write failed")
+)
+
+// Header is the first CSV record of every export
+var Header = []string{"id", "timestamp", "amount", "delegator", "level"}
+
+// WriteCSV streams delegations of year (0 = all years) to w as CSV, ordered by ID.
+// Delegations are fetched batchSize at a time using the highest ID seen as the cursor,
+// so memory use is bounded by one batch. Returns the number of data rows written.
+func WriteCSV(ctx context.Context, w io.Writer, finder tezos.DelegationsKeysetFinder, year tezos.Year, batchSize uint64) (int64, error) {
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(Header); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWriteFailed, err)
+	}
+
+	var (
+		cursor  int64
+		written int64
+	)
+	for {
+		delegations, err := finder.FindSinceIDInYear(ctx, year, cursor, batchSize)
+		if err != nil {
+			return written, fmt.Errorf("%w: after id %d: %w", ErrFetchFailed, cursor, err)
+		}
+		if len(delegations) == 0 {
+			break
+		}
+
+		for _, d := range delegations {
+			if err := out.Write(toRecord(d)); err != nil {
+				return written, fmt.Errorf("%w: %w", ErrWriteFailed, err)
+			}
+			written++
+		}
+		cursor = delegations[len(delegations)-1].ID
+
+		// Flush per batch so output streams instead of buffering the whole export
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return written, fmt.Errorf("%w: %w", ErrWriteFailed, err)
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return written, fmt.Errorf("%w: %w", ErrWriteFailed, err)
+	}
+	return written, nil
+}
+
+// toRecord converts a delegation to a CSV record matching Header
+func toRecord(d tezos.Delegation) []string {
+	return []string{
+		strconv.FormatInt(d.ID, 10),
+		d.Timestamp.UTC().Format(time.RFC3339),
+		strconv.FormatInt(d.Amount.Mutez(), 10),
+		d.Delegator,
+		strconv.FormatInt(d.Level, 10),
+	}
+}
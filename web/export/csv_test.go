@@ -0,0 +1,113 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/export"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it streams every delegation in batches after the header", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &fakeKeysetFinder{delegations: delegationsWithIDs(1, 2, 3, 4, 5)}
+		var out bytes.Buffer
+
+		// Act
+		written, err := export.WriteCSV(t.Context(), &out, finder, 0, 2)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), written)
+		assert.Equal(t, []int64{0, 2, 4, 5}, finder.cursors, "Each batch should resume after the highest ID seen")
+		records := parseCSV(t, &out)
+		assert.Equal(t, export.Header, records[0])
+		assert.Equal(t, []string{"3", "2025-01-15T10:30:00Z", "1000003", "tz1TestDelegator3", "4500003"}, records[3])
+		assert.Len(t, records, 6)
+	})
+
+	t.Run("it writes only the header when there is nothing to export", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &fakeKeysetFinder{}
+		var out bytes.Buffer
+
+		// Act
+		written, err := export.WriteCSV(t.Context(), &out, finder, 0, 2)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, written)
+		assert.Equal(t, [][]string{export.Header}, parseCSV(t, &out))
+	})
+
+	t.Run("it reports fetch failures", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &fakeKeysetFinder{err: errors.New("connection reset")}
+
+		// Act
+		_, err := export.WriteCSV(t.Context(), &bytes.Buffer{}, finder, 0, 2)
+
+		// Assert
+		assert.ErrorIs(t, err, export.ErrFetchFailed)
+	})
+}
+
+// fakeKeysetFinder serves delegations sorted by ID and records every cursor it is asked for
+type fakeKeysetFinder struct {
+	delegations []tezos.Delegation
+	cursors     []int64
+	err         error
+}
+
+func (f *fakeKeysetFinder) FindSinceIDInYear(_ context.Context, _ tezos.Year, id int64, limit uint64) ([]tezos.Delegation, error) {
+	f.cursors = append(f.cursors, id)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var page []tezos.Delegation
+	for _, d := range f.delegations {
+		if d.ID > id && uint64(len(page)) < limit {
+			page = append(page, d)
+		}
+	}
+	return page, nil
+}
+
+func delegationsWithIDs(ids ...int64) []tezos.Delegation {
+	result := make([]tezos.Delegation, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, tezos.Delegation{
+			ID:        id,
+			Timestamp: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			Amount:    tezos.Amount(1000000 + id),
+			Delegator: "tz1TestDelegator" + string(rune('0'+id)),
+			Level:     4500000 + id,
+		})
+	}
+	return result
+}
+
+func parseCSV(t *testing.T, out *bytes.Buffer) [][]string {
+	t.Helper()
+
+	records, err := csv.NewReader(out).ReadAll()
+	require.NoError(t, err)
+	return records
+}
@@ -0,0 +1,96 @@
+//go:build acceptance
+
+package export_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/migrator/migratortest"
+	"github.com/screwyprof/delegator/pkg/pgxdb"
+	"github.com/screwyprof/delegator/web/export"
+	"github.com/screwyprof/delegator/web/store/pgxstore"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const migrationsDir = "../../migrator/migrations"
+
+// TestWriteCSVAcceptance exports delegations from a real PostgreSQL database
+func TestWriteCSVAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it exports every seeded delegation after the header", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, migrationsDir)
+		defer seededDB.Close()
+
+		finder := finderFor(t, seededDB)
+		var out bytes.Buffer
+
+		// Act
+		written, err := export.WriteCSV(t.Context(), &out, finder, 0, 100)
+
+		// Assert
+		require.NoError(t, err)
+		records := parseCSV(t, &out)
+		assert.Equal(t, export.Header, records[0])
+		assert.Equal(t, countDelegations(t, seededDB), written)
+		assert.Len(t, records, int(written)+1, "CSV should hold one row per delegation plus the header")
+	})
+
+	t.Run("it exports only the requested year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		_, err := db.Exec(t.Context(), `
+			INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+			VALUES
+				(1, '2024-06-01T10:30:00Z', 1000000, 'tz1Alice', 4000000, 2024),
+				(2, '2025-01-15T10:30:00Z', 2000000, 'tz1Bob', 4500000, 2025),
+				(3, '2025-01-16T10:30:00Z', 3000000, 'tz1Carol', 4500100, 2025)
+		`)
+		require.NoError(t, err)
+
+		finder := finderFor(t, db)
+		var out bytes.Buffer
+
+		// Act
+		written, err := export.WriteCSV(t.Context(), &out, finder, tezos.Year(2025), 1)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), written)
+		records := parseCSV(t, &out)
+		require.Len(t, records, 3)
+		assert.Equal(t, "2", records[1][0])
+		assert.Equal(t, "3", records[2][0])
+	})
+}
+
+// finderFor returns a finder with its own connection pool to db
+func finderFor(t *testing.T, db *pgxpool.Pool) *pgxstore.DelegationsFinder {
+	t.Helper()
+
+	conn, err := pgxdb.NewConnection(t.Context(), db.Config().ConnString())
+	require.NoError(t, err)
+
+	finder, closer := pgxstore.New(conn)
+	t.Cleanup(closer)
+	return finder
+}
+
+func countDelegations(t *testing.T, db *pgxpool.Pool) int64 {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, db.QueryRow(t.Context(), "SELECT COUNT(*) FROM delegations").Scan(&count))
+	return count
+}
@@ -9,6 +9,7 @@ require (
 	github.com/screwyprof/delegator/pkg v0.0.0
 	github.com/stretchr/testify v1.11.1
 	github.com/zolstein/pgx-collect v0.0.0-20240326220938-c46e0ea05ad2
+	golang.org/x/sync v0.19.0
 )
 
 require (
@@ -22,7 +23,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rubenv/sql-migrate v1.8.1 // indirect
 	github.com/screwyprof/delegator/scraper v0.0.0 // indirect
-	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/text v0.33.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,10 +3,14 @@ package bind
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/screwyprof/delegator/pkg/httpkit"
 	"github.com/screwyprof/delegator/web/api"
 	"github.com/screwyprof/delegator/web/tezos"
 )
@@ -16,10 +20,56 @@ var (
 	ErrInvalidYear    = errors.New("invalid year parameter")
 	ErrInvalidPage    = errors.New("invalid page parameter")
 	ErrInvalidPerPage = errors.New("invalid per_page parameter")
+	ErrInvalidID      = errors.New("invalid id parameter")
+	ErrInvalidLimit   = errors.New("invalid limit parameter")
+	ErrInvalidEcho    = errors.New("invalid echo_criteria parameter")
+	ErrInvalidFields  = errors.New("invalid fields parameter")
+	ErrInvalidBuckets = errors.New("invalid buckets parameter")
+
+	ErrInvalidIncludeCreatedAt = errors.New("invalid include_created_at parameter")
+
+	ErrInvalidLevelRange = errors.New("invalid min_level/max_level parameters")
+
+	ErrInvalidCallback = errors.New("invalid callback parameter")
+)
+
+// PageBase is the page number clients use for the first page
+type PageBase uint64
+
+const (
+	ZeroBasedPages PageBase = 0 // page=0 is the first page
+	OneBasedPages  PageBase = 1 // page=1 is the first page; page=0 also falls back to it
 )
 
+// PageParam converts an internal 1-based page to the number clients use for it
+func (b PageBase) PageParam(page tezos.Page) uint64 {
+	return page.Uint64() - 1 + uint64(b)
+}
+
+// Option configures request binding
+type Option func(*options)
+
+type options struct {
+	pageBase PageBase
+}
+
+// WithPageBase sets the page number clients use for the first page (OneBasedPages by default).
+// Under ZeroBasedPages an explicit page=N is bound as the internal page N+1.
+func WithPageBase(base PageBase) Option {
+	return func(o *options) { o.pageBase = base }
+}
+
+func newOptions(opts []Option) options {
+	o := options{pageBase: OneBasedPages}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // GetDelegationsRequest binds HTTP request to DelegationsRequest
-func GetDelegationsRequest(r *http.Request) (api.DelegationsRequest, error) {
+func GetDelegationsRequest(r *http.Request, opts ...Option) (api.DelegationsRequest, error) {
+	o := newOptions(opts)
 	query := r.URL.Query()
 
 	year, err := parseUintEmptyAsZero(query.Get("year"))
@@ -27,7 +77,7 @@ func GetDelegationsRequest(r *http.Request) (api.DelegationsRequest, error) {
 		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
 	}
 
-	page, err := parseUintEmptyAsZero(query.Get("page"))
+	page, err := parsePage(query.Get("page"), o.pageBase)
 	if err != nil {
 		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidPage, err)
 	}
@@ -37,13 +87,226 @@ func GetDelegationsRequest(r *http.Request) (api.DelegationsRequest, error) {
 		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidPerPage, err)
 	}
 
+	echoCriteria, err := parseBoolEmptyAsFalse(query.Get("echo_criteria"))
+	if err != nil {
+		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidEcho, err)
+	}
+
+	fields, err := parseFields(query.Get("fields"))
+	if err != nil {
+		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidFields, err)
+	}
+
+	minLevel, err := parseUintEmptyAsZero(query.Get("min_level"))
+	if err != nil {
+		return api.DelegationsRequest{}, fmt.Errorf("%w: min_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	maxLevel, err := parseUintEmptyAsZero(query.Get("max_level"))
+	if err != nil {
+		return api.DelegationsRequest{}, fmt.Errorf("%w: max_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	includeCreatedAt, err := parseBoolEmptyAsFalse(query.Get("include_created_at"))
+	if err != nil {
+		return api.DelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidIncludeCreatedAt, err)
+	}
+	if includeCreatedAt {
+		fields = withField(fields, api.FieldCreatedAt)
+	}
+
 	return api.DelegationsRequest{
-		Year:    year,
-		Page:    page,
-		PerPage: perPage,
+		Year:             year,
+		Page:             page,
+		PerPage:          perPage,
+		EchoCriteria:     echoCriteria,
+		Fields:           fields,
+		MinLevel:         minLevel,
+		MaxLevel:         maxLevel,
+		IncludeCreatedAt: includeCreatedAt,
+	}, nil
+}
+
+// GetJSONPCallback binds the JSONP callback parameter; an absent one yields "".
+// Only JavaScript identifiers and dotted paths of them are accepted.
+func GetJSONPCallback(r *http.Request) (string, error) {
+	callback := r.URL.Query().Get("callback")
+	if callback != "" && !httpkit.ValidJSONPCallback(callback) {
+		return "", fmt.Errorf("%w: must be a JavaScript identifier such as app.onData", ErrInvalidCallback)
+	}
+	return callback, nil
+}
+
+// GetDelegatorsCountRequest binds HTTP request to DelegatorsCountRequest
+func GetDelegatorsCountRequest(r *http.Request) (api.DelegatorsCountRequest, error) {
+	year, err := parseUintEmptyAsZero(r.URL.Query().Get("year"))
+	if err != nil {
+		return api.DelegatorsCountRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
+	}
+
+	return api.DelegatorsCountRequest{
+		Year: year,
+	}, nil
+}
+
+// GetDelegationsSinceRequest binds HTTP request to DelegationsSinceRequest
+func GetDelegationsSinceRequest(r *http.Request) (api.DelegationsSinceRequest, error) {
+	query := r.URL.Query()
+
+	id, err := parseUintEmptyAsZero(query.Get("id"))
+	if err != nil {
+		return api.DelegationsSinceRequest{}, fmt.Errorf("%w: %w", ErrInvalidID, err)
+	}
+	if id > math.MaxInt64 {
+		return api.DelegationsSinceRequest{}, fmt.Errorf("%w: %w", ErrInvalidID, strconv.ErrRange)
+	}
+
+	limit, err := parseUintEmptyAsZero(query.Get("limit"))
+	if err != nil {
+		return api.DelegationsSinceRequest{}, fmt.Errorf("%w: %w", ErrInvalidLimit, err)
+	}
+
+	return api.DelegationsSinceRequest{
+		ID:    id,
+		Limit: limit,
+	}, nil
+}
+
+// GetRecentDelegationsRequest binds HTTP request to RecentDelegationsRequest
+func GetRecentDelegationsRequest(r *http.Request) (api.RecentDelegationsRequest, error) {
+	limit, err := parseUintEmptyAsZero(r.URL.Query().Get("limit"))
+	if err != nil {
+		return api.RecentDelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidLimit, err)
+	}
+
+	return api.RecentDelegationsRequest{
+		Limit: limit,
+	}, nil
+}
+
+// GetTopDelegationsRequest binds HTTP request to TopDelegationsRequest
+func GetTopDelegationsRequest(r *http.Request) (api.TopDelegationsRequest, error) {
+	query := r.URL.Query()
+
+	limit, err := parseUintEmptyAsZero(query.Get("limit"))
+	if err != nil {
+		return api.TopDelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidLimit, err)
+	}
+
+	year, err := parseUintEmptyAsZero(query.Get("year"))
+	if err != nil {
+		return api.TopDelegationsRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
+	}
+
+	return api.TopDelegationsRequest{
+		Limit: limit,
+		Year:  year,
+	}, nil
+}
+
+// GetAmountHistogramRequest binds HTTP request to AmountHistogramRequest
+func GetAmountHistogramRequest(r *http.Request) (api.AmountHistogramRequest, error) {
+	query := r.URL.Query()
+
+	buckets, err := parseUintEmptyAsZero(query.Get("buckets"))
+	if err != nil {
+		return api.AmountHistogramRequest{}, fmt.Errorf("%w: %w", ErrInvalidBuckets, err)
+	}
+
+	year, err := parseUintEmptyAsZero(query.Get("year"))
+	if err != nil {
+		return api.AmountHistogramRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
+	}
+
+	minLevel, err := parseUintEmptyAsZero(query.Get("min_level"))
+	if err != nil {
+		return api.AmountHistogramRequest{}, fmt.Errorf("%w: min_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	maxLevel, err := parseUintEmptyAsZero(query.Get("max_level"))
+	if err != nil {
+		return api.AmountHistogramRequest{}, fmt.Errorf("%w: max_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	return api.AmountHistogramRequest{
+		Buckets:  buckets,
+		Year:     year,
+		MinLevel: minLevel,
+		MaxLevel: maxLevel,
+	}, nil
+}
+
+// GetDailyCountsRequest binds HTTP request to DailyCountsRequest
+func GetDailyCountsRequest(r *http.Request) (api.DailyCountsRequest, error) {
+	year, err := parseUintEmptyAsZero(r.URL.Query().Get("year"))
+	if err != nil {
+		return api.DailyCountsRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
+	}
+
+	return api.DailyCountsRequest{
+		Year: year,
+	}, nil
+}
+
+// GetCumulativeAmountsRequest binds HTTP request to CumulativeAmountsRequest
+func GetCumulativeAmountsRequest(r *http.Request) (api.CumulativeAmountsRequest, error) {
+	query := r.URL.Query()
+
+	year, err := parseUintEmptyAsZero(query.Get("year"))
+	if err != nil {
+		return api.CumulativeAmountsRequest{}, fmt.Errorf("%w: %w", ErrInvalidYear, err)
+	}
+
+	minLevel, err := parseUintEmptyAsZero(query.Get("min_level"))
+	if err != nil {
+		return api.CumulativeAmountsRequest{}, fmt.Errorf("%w: min_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	maxLevel, err := parseUintEmptyAsZero(query.Get("max_level"))
+	if err != nil {
+		return api.CumulativeAmountsRequest{}, fmt.Errorf("%w: max_level: %w", ErrInvalidLevelRange, err)
+	}
+
+	return api.CumulativeAmountsRequest{
+		Year:     year,
+		MinLevel: minLevel,
+		MaxLevel: maxLevel,
+	}, nil
+}
+
+// GetCompareYearsRequest binds HTTP request to CompareYearsRequest
+func GetCompareYearsRequest(r *http.Request) (api.CompareYearsRequest, error) {
+	query := r.URL.Query()
+
+	yearA, err := parseUintEmptyAsZero(query.Get("year_a"))
+	if err != nil {
+		return api.CompareYearsRequest{}, fmt.Errorf("%w: year_a: %w", ErrInvalidYear, err)
+	}
+
+	yearB, err := parseUintEmptyAsZero(query.Get("year_b"))
+	if err != nil {
+		return api.CompareYearsRequest{}, fmt.Errorf("%w: year_b: %w", ErrInvalidYear, err)
+	}
+
+	return api.CompareYearsRequest{
+		YearA: yearA,
+		YearB: yearB,
 	}, nil
 }
 
+// parsePage parses the page parameter into the internal 1-based page. An absent page
+// stays 0, which selects the default page.
+func parsePage(s string, base PageBase) (uint64, error) {
+	page, err := parseUintEmptyAsZero(s)
+	if err != nil || s == "" || base != ZeroBasedPages {
+		return page, err
+	}
+	if page == math.MaxUint64 {
+		return 0, strconv.ErrRange
+	}
+	return page + 1, nil
+}
+
 // parseUintEmptyAsZero parses string to uint64, treats empty string as 0
 func parseUintEmptyAsZero(s string) (uint64, error) {
 	if s == "" {
@@ -52,19 +315,178 @@ func parseUintEmptyAsZero(s string) (uint64, error) {
 	return strconv.ParseUint(s, 10, 64)
 }
 
-// GetDelegationsResponse binds domain delegations to API response format
-func GetDelegationsResponse(delegations []tezos.Delegation) api.DelegationsResponse {
+// parseBoolEmptyAsFalse parses string to bool, treats empty string as false
+func parseBoolEmptyAsFalse(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// parseFields parses a comma-separated field list, validated against api.DelegationFields.
+// An empty string selects all fields and yields nil.
+func parseFields(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(s, ",")
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(api.DelegationFields, field) {
+			return nil, fmt.Errorf("unknown field %q: must be one of %s", field, strings.Join(api.DelegationFields, ", "))
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+// withField adds field to the selection unless already present.
+// An empty selection stands for the default fields, so those are kept alongside it.
+func withField(fields []string, field string) []string {
+	if len(fields) == 0 {
+		fields = api.DefaultDelegationFields
+	}
+	if slices.Contains(fields, field) {
+		return fields
+	}
+	return append(slices.Clone(fields), field)
+}
+
+// GetAppliedCriteria binds domain criteria to the API applied criteria format,
+// reporting the page in the client's page base
+func GetAppliedCriteria(criteria tezos.DelegationsCriteria, opts ...Option) *api.AppliedCriteria {
+	o := newOptions(opts)
+	return &api.AppliedCriteria{
+		Year:     criteria.Year.Uint64(),
+		MinLevel: criteria.Levels.Min,
+		MaxLevel: criteria.Levels.Max,
+		Page:     o.pageBase.PageParam(criteria.Page),
+		PerPage:  criteria.Size.Uint64(),
+		Sort:     criteria.Sort(),
+	}
+}
+
+// GetDelegationsResponse binds domain delegations to API response format.
+// When fields are given, only those are populated; the rest are omitted from the JSON.
+func GetDelegationsResponse(delegations []tezos.Delegation, fields ...string) api.DelegationsResponse {
 	apiDelegations := make([]api.Delegation, len(delegations))
 	for i, del := range delegations {
-		apiDelegations[i] = api.Delegation{
-			Timestamp: del.Timestamp.Format(time.RFC3339),
-			Amount:    fmt.Sprintf("%d", del.Amount),
-			Delegator: del.Delegator,
-			Level:     fmt.Sprintf("%d", del.Level),
-		}
+		apiDelegations[i] = GetDelegation(del, fields...)
 	}
 
 	return api.DelegationsResponse{
 		Data: apiDelegations,
 	}
 }
+
+// GetDelegation binds a single domain delegation to the API format, populating only
+// the given fields (the default fields when none are given)
+func GetDelegation(del tezos.Delegation, fields ...string) api.Delegation {
+	if len(fields) == 0 {
+		fields = api.DefaultDelegationFields
+	}
+	selected := func(field string) bool {
+		return slices.Contains(fields, field)
+	}
+
+	var d api.Delegation
+	if selected(api.FieldTimestamp) {
+		d.Timestamp = del.Timestamp.Format(time.RFC3339)
+	}
+	if selected(api.FieldAmount) {
+		d.Amount = fmt.Sprintf("%d", del.Amount.Mutez())
+	}
+	if selected(api.FieldDelegator) {
+		d.Delegator = del.Delegator
+	}
+	if selected(api.FieldLevel) {
+		d.Level = fmt.Sprintf("%d", del.Level)
+	}
+	if selected(api.FieldAmountTez) {
+		d.AmountTez = del.Amount.String()
+	}
+	if selected(api.FieldCreatedAt) {
+		d.CreatedAt = del.CreatedAt.Format(time.RFC3339)
+	}
+	return d
+}
+
+// GetAmountHistogramResponse binds domain buckets to API response format
+func GetAmountHistogramResponse(histogram []tezos.Bucket) api.AmountHistogramResponse {
+	buckets := make([]api.AmountBucket, len(histogram))
+	for i, b := range histogram {
+		buckets[i] = api.AmountBucket{
+			Min:   fmt.Sprintf("%d", b.Min.Mutez()),
+			Max:   fmt.Sprintf("%d", b.Max.Mutez()),
+			Count: b.Count,
+		}
+	}
+
+	return api.AmountHistogramResponse{
+		Buckets: buckets,
+	}
+}
+
+// GetDelegationYearsResponse binds domain year counts to API response format
+func GetDelegationYearsResponse(years []tezos.YearCount) api.DelegationYearsResponse {
+	resp := api.DelegationYearsResponse{
+		Years: make([]api.YearCount, len(years)),
+	}
+	for i, y := range years {
+		resp.Years[i] = api.YearCount{
+			Year:  y.Year.Uint64(),
+			Count: y.Count,
+		}
+	}
+	return resp
+}
+
+// GetDailyCountsResponse binds domain day counts of a year to API response format
+func GetDailyCountsResponse(year tezos.Year, days []tezos.DayCount) api.DailyCountsResponse {
+	resp := api.DailyCountsResponse{
+		Year: year.Uint64(),
+		Days: make([]api.DayCount, len(days)),
+	}
+	for i, d := range days {
+		resp.Days[i] = api.DayCount{
+			Date:  d.Day.UTC().Format(time.DateOnly),
+			Count: d.Count,
+		}
+	}
+	return resp
+}
+
+// GetCumulativeAmountsResponse binds domain monthly amounts to API response format
+func GetCumulativeAmountsResponse(months []tezos.MonthlyAmount) api.CumulativeAmountsResponse {
+	resp := api.CumulativeAmountsResponse{
+		Months: make([]api.MonthlyAmount, len(months)),
+	}
+	for i, m := range months {
+		resp.Months[i] = api.MonthlyAmount{
+			Month:      m.Month.UTC().Format("2006-01"),
+			Amount:     fmt.Sprintf("%d", m.Amount.Mutez()),
+			Cumulative: fmt.Sprintf("%d", m.Cumulative.Mutez()),
+		}
+	}
+	return resp
+}
+
+// GetYearComparisonResponse binds a domain year comparison to API response format
+func GetYearComparisonResponse(comparison tezos.YearComparison) api.YearComparisonResponse {
+	return api.YearComparisonResponse{
+		YearA:       getYearTotals(comparison.A),
+		YearB:       getYearTotals(comparison.B),
+		CountDelta:  comparison.CountDelta(),
+		AmountDelta: fmt.Sprintf("%d", comparison.AmountDelta().Mutez()),
+	}
+}
+
+// getYearTotals binds domain year totals to the API format
+func getYearTotals(totals tezos.YearTotals) api.YearTotals {
+	return api.YearTotals{
+		Year:   totals.Year.Uint64(),
+		Count:  totals.Count,
+		Amount: fmt.Sprintf("%d", totals.Amount.Mutez()),
+	}
+}
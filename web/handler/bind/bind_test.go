@@ -0,0 +1,688 @@
+package bind_test
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestGetAppliedCriteria(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it echoes defaults when the client omitted them", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?echo_criteria=true", nil)
+
+		// Act
+		applied := appliedCriteriaFor(t, r)
+
+		// Assert
+		assert.Equal(t, &api.AppliedCriteria{
+			Year:    0,
+			Page:    tezos.DefaultPage,
+			PerPage: tezos.DefaultPerPage,
+			Sort:    tezos.SortNewestFirst,
+		}, applied)
+	})
+
+	t.Run("it echoes the criteria supplied by the client", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?year=2024&page=3&per_page=10&echo_criteria=true", nil)
+
+		// Act
+		applied := appliedCriteriaFor(t, r)
+
+		// Assert
+		assert.Equal(t, &api.AppliedCriteria{
+			Year:    2024,
+			Page:    3,
+			PerPage: 10,
+			Sort:    tezos.SortNewestFirst,
+		}, applied)
+	})
+}
+
+func TestGetDelegationsRequestEchoCriteria(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it is disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil)
+
+		// Act
+		req, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, req.EchoCriteria)
+	})
+
+	t.Run("it rejects a non-boolean value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?echo_criteria=maybe", nil)
+
+		// Act
+		_, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidEcho)
+	})
+}
+
+func TestGetDelegationsRequestLevelRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds both level bounds", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?min_level=100&max_level=200", nil)
+
+		// Act
+		req, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), req.MinLevel)
+		assert.Equal(t, uint64(200), req.MaxLevel)
+	})
+
+	t.Run("it leaves absent bounds open", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil)
+
+		// Act
+		req, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, req.MinLevel)
+		assert.Zero(t, req.MaxLevel)
+	})
+
+	t.Run("it rejects non-numeric or negative levels", func(t *testing.T) {
+		t.Parallel()
+
+		for _, query := range []string{"min_level=abc", "max_level=-5", "min_level=1.5"} {
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?"+query, nil)
+
+			// Act
+			_, err := bind.GetDelegationsRequest(r)
+
+			// Assert
+			assert.ErrorIs(t, err, bind.ErrInvalidLevelRange, query)
+		}
+	})
+}
+
+func TestGetDelegationsRequestPageBase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		opts         []bind.Option
+		query        string
+		expectedPage uint64
+		expectedSkip uint64
+	}{
+		{name: "it treats page=1 as the first page by default", query: "page=1&per_page=10", expectedPage: 1, expectedSkip: 0},
+		{name: "it keeps page=0 as the first page by default", query: "page=0&per_page=10", expectedPage: 1, expectedSkip: 0},
+		{name: "it skips earlier pages by default", query: "page=3&per_page=10", expectedPage: 3, expectedSkip: 20},
+		{name: "it treats page=0 as the first page when 0-based", opts: []bind.Option{bind.WithPageBase(bind.ZeroBasedPages)}, query: "page=0&per_page=10", expectedPage: 1, expectedSkip: 0},
+		{name: "it shifts later pages when 0-based", opts: []bind.Option{bind.WithPageBase(bind.ZeroBasedPages)}, query: "page=2&per_page=10", expectedPage: 3, expectedSkip: 20},
+		{name: "it defaults an absent page to the first page when 0-based", opts: []bind.Option{bind.WithPageBase(bind.ZeroBasedPages)}, query: "per_page=10", expectedPage: 1, expectedSkip: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?"+tc.query, nil)
+
+			// Act
+			req, err := bind.GetDelegationsRequest(r, tc.opts...)
+			require.NoError(t, err)
+			criteria, err := tezos.NewDelegationsCriteria(req.Year, req.Page, req.PerPage)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPage, criteria.Page.Uint64())
+			assert.Equal(t, tc.expectedSkip, criteria.ItemsToSkip())
+		})
+	}
+
+	t.Run("it rejects a 0-based page that has no 1-based equivalent", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?page=18446744073709551615", nil)
+
+		// Act
+		_, err := bind.GetDelegationsRequest(r, bind.WithPageBase(bind.ZeroBasedPages))
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidPage)
+	})
+
+	t.Run("it echoes the page in the client's page base", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(0, 3, 10)
+		require.NoError(t, err)
+
+		// Act
+		oneBased := bind.GetAppliedCriteria(criteria)
+		zeroBased := bind.GetAppliedCriteria(criteria, bind.WithPageBase(bind.ZeroBasedPages))
+
+		// Assert
+		assert.Equal(t, uint64(3), oneBased.Page)
+		assert.Equal(t, uint64(2), zeroBased.Page)
+	})
+}
+
+func TestGetAmountHistogramRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds the bucket count and filters", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/histogram?buckets=5&year=2025&min_level=100&max_level=200", nil)
+
+		// Act
+		req, err := bind.GetAmountHistogramRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, api.AmountHistogramRequest{Buckets: 5, Year: 2025, MinLevel: 100, MaxLevel: 200}, req)
+	})
+
+	t.Run("it rejects a non-numeric bucket count", func(t *testing.T) {
+		t.Parallel()
+
+		for _, query := range []string{"buckets=abc", "buckets=-1", "buckets=2.5"} {
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/histogram?"+query, nil)
+
+			// Act
+			_, err := bind.GetAmountHistogramRequest(r)
+
+			// Assert
+			assert.ErrorIs(t, err, bind.ErrInvalidBuckets, query)
+		}
+	})
+}
+
+func TestGetAmountHistogramResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders bucket boundaries in mutez", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		histogram := []tezos.Bucket{{Min: 1_000_000, Max: 1_499_999, Count: 3}, {Min: 1_500_000, Max: 1_999_999}}
+
+		// Act
+		resp := bind.GetAmountHistogramResponse(histogram)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"buckets":[{"min":"1000000","max":"1499999","count":3},{"min":"1500000","max":"1999999","count":0}]}`, string(body))
+	})
+
+	t.Run("it renders no matches as an empty list", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		resp := bind.GetAmountHistogramResponse([]tezos.Bucket{})
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"buckets":[]}`, string(body))
+	})
+}
+
+func TestGetDelegationYearsResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders years with their counts", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		years := []tezos.YearCount{{Year: 2018, Count: 12}, {Year: 2019, Count: 3}}
+
+		// Act
+		resp := bind.GetDelegationYearsResponse(years)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"years":[{"year":2018,"count":12},{"year":2019,"count":3}]}`, string(body))
+	})
+
+	t.Run("it renders no years as an empty list", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		resp := bind.GetDelegationYearsResponse(nil)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"years":[]}`, string(body))
+	})
+}
+
+func TestGetTopDelegationsRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds the limit and year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/top?limit=10&year=2024", nil)
+
+		// Act
+		req, err := bind.GetTopDelegationsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, api.TopDelegationsRequest{Limit: 10, Year: 2024}, req)
+	})
+
+	t.Run("it rejects a non-numeric limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/top?limit=all", nil)
+
+		// Act
+		_, err := bind.GetTopDelegationsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidLimit)
+	})
+}
+
+func TestGetDailyCountsRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds the year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/daily?year=2024", nil)
+
+		// Act
+		req, err := bind.GetDailyCountsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, api.DailyCountsRequest{Year: 2024}, req)
+	})
+
+	t.Run("it rejects a non-numeric year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/daily?year=last", nil)
+
+		// Act
+		_, err := bind.GetDailyCountsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidYear)
+	})
+}
+
+func TestGetDailyCountsResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders each day as a UTC date", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		days := []tezos.DayCount{
+			{Day: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Count: 3},
+			{Day: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), Count: 1},
+		}
+
+		// Act
+		resp := bind.GetDailyCountsResponse(2024, days)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"year": 2024,
+			"days": [{"date": "2024-01-01", "count": 3}, {"date": "2024-02-29", "count": 1}]
+		}`, string(body))
+	})
+
+	t.Run("it renders an empty list for a year without delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		resp := bind.GetDailyCountsResponse(2019, []tezos.DayCount{})
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"year": 2019, "days": []}`, string(body))
+	})
+}
+
+func TestGetCumulativeAmountsRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds the optional filters", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/tvl?year=2024&min_level=100&max_level=200", nil)
+
+		// Act
+		req, err := bind.GetCumulativeAmountsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, api.CumulativeAmountsRequest{Year: 2024, MinLevel: 100, MaxLevel: 200}, req)
+	})
+
+	t.Run("it rejects a non-numeric level bound", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/tvl?max_level=top", nil)
+
+		// Act
+		_, err := bind.GetCumulativeAmountsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidLevelRange)
+		assert.ErrorContains(t, err, "max_level")
+	})
+}
+
+func TestGetCumulativeAmountsResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders months and amounts in mutez", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		months := []tezos.MonthlyAmount{
+			{Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 2_500_000, Cumulative: 2_500_000},
+			{Month: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 1_000_000, Cumulative: 3_500_000},
+		}
+
+		// Act
+		resp := bind.GetCumulativeAmountsResponse(months)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"months": [
+			{"month": "2024-01", "amount": "2500000", "cumulative": "2500000"},
+			{"month": "2024-03", "amount": "1000000", "cumulative": "3500000"}
+		]}`, string(body))
+	})
+}
+
+func TestGetCompareYearsRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it binds both years", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/compare?year_a=2023&year_b=2024", nil)
+
+		// Act
+		req, err := bind.GetCompareYearsRequest(r)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, api.CompareYearsRequest{YearA: 2023, YearB: 2024}, req)
+	})
+
+	t.Run("it rejects a non-numeric year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations/compare?year_a=2023&year_b=last", nil)
+
+		// Act
+		_, err := bind.GetCompareYearsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidYear)
+		assert.ErrorContains(t, err, "year_b")
+	})
+}
+
+func TestGetYearComparisonResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders totals and deltas in mutez", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		comparison := tezos.YearComparison{
+			A: tezos.YearTotals{Year: 2023, Count: 5, Amount: 9_000_000},
+			B: tezos.YearTotals{Year: 2024, Count: 3, Amount: 4_000_000},
+		}
+
+		// Act
+		resp := bind.GetYearComparisonResponse(comparison)
+
+		// Assert
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"year_a": {"year": 2023, "count": 5, "amount": "9000000"},
+			"year_b": {"year": 2024, "count": 3, "amount": "4000000"},
+			"count_delta": -2,
+			"amount_delta": "-5000000"
+		}`, string(body))
+	})
+}
+
+// appliedCriteriaFor runs the request through binding and criteria validation
+func appliedCriteriaFor(t *testing.T, r *http.Request) *api.AppliedCriteria {
+	t.Helper()
+
+	req, err := bind.GetDelegationsRequest(r)
+	require.NoError(t, err)
+	require.True(t, req.EchoCriteria)
+
+	criteria, err := tezos.NewDelegationsCriteria(req.Year, req.Page, req.PerPage)
+	require.NoError(t, err)
+
+	return bind.GetAppliedCriteria(criteria)
+}
+
+func TestGetDelegationsResponseFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns exactly the requested keys", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?fields=timestamp,amount", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assertDelegationKeys(t, resp, "amount", "timestamp")
+	})
+
+	t.Run("it returns all keys when no fields are requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assertDelegationKeys(t, resp, "amount", "delegator", "level", "timestamp")
+	})
+
+	t.Run("it renders amount_tez only when requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?fields=amount,amount_tez", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assertDelegationKeys(t, resp, "amount", "amount_tez")
+		assert.Equal(t, "1000000", resp.Data[0].Amount)
+		assert.Equal(t, "1.000000", resp.Data[0].AmountTez)
+	})
+
+	t.Run("it rejects unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?fields=timestamp,password", nil)
+
+		// Act
+		_, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidFields)
+	})
+}
+
+func TestGetDelegationsRequestIncludeCreatedAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it omits created_at by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assert.False(t, req.IncludeCreatedAt)
+		assertDelegationKeys(t, resp, "amount", "delegator", "level", "timestamp")
+	})
+
+	t.Run("it adds created_at to the default fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?include_created_at=true", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assert.True(t, req.IncludeCreatedAt)
+		assertDelegationKeys(t, resp, "amount", "created_at", "delegator", "level", "timestamp")
+		assert.Equal(t, "2025-01-15T10:31:00Z", resp.Data[0].CreatedAt)
+	})
+
+	t.Run("it adds created_at to the requested fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?fields=level&include_created_at=true", nil)
+		req, err := bind.GetDelegationsRequest(r)
+		require.NoError(t, err)
+
+		// Act
+		resp := bind.GetDelegationsResponse([]tezos.Delegation{testDelegation()}, req.Fields...)
+
+		// Assert
+		assertDelegationKeys(t, resp, "created_at", "level")
+	})
+
+	t.Run("it rejects a non-boolean value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?include_created_at=maybe", nil)
+
+		// Act
+		_, err := bind.GetDelegationsRequest(r)
+
+		// Assert
+		assert.ErrorIs(t, err, bind.ErrInvalidIncludeCreatedAt)
+	})
+}
+
+func testDelegation() tezos.Delegation {
+	return tezos.Delegation{
+		ID:        1,
+		Timestamp: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+		Amount:    1000000,
+		Delegator: "tz1TestDelegator1",
+		Level:     4500000,
+		CreatedAt: time.Date(2025, 1, 15, 10, 31, 0, 0, time.UTC),
+	}
+}
+
+// assertDelegationKeys verifies every marshaled delegation has exactly the expected keys
+func assertDelegationKeys(t *testing.T, resp api.DelegationsResponse, expected ...string) {
+	t.Helper()
+
+	body, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	require.NotEmpty(t, decoded.Data)
+
+	for _, item := range decoded.Data {
+		keys := slices.Sorted(maps.Keys(item))
+		assert.Equal(t, expected, keys)
+	}
+}
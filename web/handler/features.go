@@ -0,0 +1,38 @@
+package handler
+
+import "net/http"
+
+// Optional endpoint features, named in WEB_ENABLED_FEATURES
+const (
+	FeatureSince     = "since"     // GET /xtz/delegations/since
+	FeatureCount     = "count"     // GET /xtz/delegators/count
+	FeatureRecent    = "recent"    // GET /xtz/delegations/recent
+	FeatureHistogram = "histogram" // GET /xtz/delegations/histogram
+	FeatureYears     = "years"     // GET /xtz/delegations/years
+	FeatureCompare   = "compare"   // GET /xtz/delegations/compare
+	FeatureTop       = "top"       // GET /xtz/delegations/top
+	FeatureStream    = "stream"    // GET /xtz/delegations/stream
+	FeatureDaily     = "daily"     // GET /xtz/delegations/daily
+	FeatureTVL       = "tvl"       // GET /xtz/delegations/tvl
+)
+
+// Features lists every optional endpoint feature
+var Features = []string{FeatureSince, FeatureCount, FeatureRecent, FeatureHistogram, FeatureYears, FeatureCompare, FeatureTop, FeatureStream, FeatureDaily, FeatureTVL}
+
+// RouteAdder registers a handler's routes on a mux
+type RouteAdder interface {
+	AddRoutes(m *http.ServeMux)
+}
+
+// FeatureRoutes maps each optional feature to the handler serving it
+type FeatureRoutes map[string]RouteAdder
+
+// AddRoutes registers the handlers whose feature is enabled. Disabled features are
+// never routed, so their paths fall through to the mux's 404 Not Found.
+func (fr FeatureRoutes) AddRoutes(m *http.ServeMux, enabled func(feature string) bool) {
+	for feature, h := range fr {
+		if enabled(feature) {
+			h.AddRoutes(m)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/web/config"
+	"github.com/screwyprof/delegator/web/handler"
+)
+
+func TestFeatureRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it routes only the enabled features", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		cfg := config.Config{EnabledFeatures: []string{handler.FeatureSince, handler.FeatureRecent}}
+		mux := newFeatureMux(cfg)
+
+		// Act & Assert
+		assert.Equal(t, http.StatusOK, serveStatus(mux, "/xtz/delegations/since"))
+		assert.Equal(t, http.StatusOK, serveStatus(mux, "/xtz/delegations/recent"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegators/count"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/histogram"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/years"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/compare"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/top"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/stream"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/daily"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/tvl"))
+	})
+
+	t.Run("it routes nothing when no features are enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := newFeatureMux(config.Config{})
+
+		// Act & Assert
+		for _, path := range []string{"/xtz/delegations/since", "/xtz/delegations/recent", "/xtz/delegators/count", "/xtz/delegations/histogram", "/xtz/delegations/years", "/xtz/delegations/compare", "/xtz/delegations/top", "/xtz/delegations/stream", "/xtz/delegations/daily", "/xtz/delegations/tvl"} {
+			assert.Equal(t, http.StatusNotFound, serveStatus(mux, path), path)
+		}
+	})
+
+	t.Run("it matches feature names case-insensitively, ignoring spaces", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		cfg := config.Config{EnabledFeatures: []string{" Count "}}
+		mux := newFeatureMux(cfg)
+
+		// Act & Assert
+		assert.Equal(t, http.StatusOK, serveStatus(mux, "/xtz/delegators/count"))
+		assert.Equal(t, http.StatusNotFound, serveStatus(mux, "/xtz/delegations/since"))
+	})
+}
+
+// stubRoute answers 200 on its single route
+type stubRoute string
+
+func (s stubRoute) AddRoutes(m *http.ServeMux) {
+	m.HandleFunc(string(s), func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func newFeatureMux(cfg config.Config) *http.ServeMux {
+	mux := http.NewServeMux()
+	handler.FeatureRoutes{
+		handler.FeatureSince:     stubRoute(handler.GetDelegationsSinceRoute),
+		handler.FeatureCount:     stubRoute(handler.CountDelegatorsRoute),
+		handler.FeatureRecent:    stubRoute(handler.GetRecentDelegationsRoute),
+		handler.FeatureHistogram: stubRoute(handler.GetAmountHistogramRoute),
+		handler.FeatureYears:     stubRoute(handler.GetDelegationYearsRoute),
+		handler.FeatureCompare:   stubRoute(handler.CompareYearsRoute),
+		handler.FeatureTop:       stubRoute(handler.GetTopDelegationsRoute),
+		handler.FeatureStream:    stubRoute(handler.StreamDelegationsRoute),
+		handler.FeatureDaily:     stubRoute(handler.GetDailyCountsRoute),
+		handler.FeatureTVL:       stubRoute(handler.GetCumulativeAmountsRoute),
+	}.AddRoutes(mux, cfg.FeatureEnabled)
+	return mux
+}
+
+func serveStatus(h http.Handler, path string) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
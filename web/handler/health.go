@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+)
+
+const (
+	HealthPath  = "/healthz"
+	ReadyPath   = "/readyz"
+	HealthRoute = http.MethodGet + " " + HealthPath
+	ReadyRoute  = http.MethodGet + " " + ReadyPath
+
+	// StatusOK is reported by /healthz while the process serves requests
+	StatusOK = "ok"
+	// StatusReady is reported by /readyz once the database has answered
+	StatusReady = "ready"
+)
+
+// Sentinel errors
+var (
+	ErrNotReady = errors.New("database not ready")
+)
+
+// Pinger checks that the database can be reached, e.g. *pgxpool.Pool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Health answers liveness and readiness probes. The service is live as soon as it
+// serves requests. It becomes ready with the first successful database ping, so load
+// balancers do not route traffic before the connection pool is warm, and then stays
+// ready without pinging again; later database failures are left to the circuit breaker.
+type Health struct {
+	db    Pinger
+	ready atomic.Bool
+}
+
+func NewHealth(db Pinger) *Health {
+	return &Health{
+		db: db,
+	}
+}
+
+func (h *Health) AddRoutes(m *http.ServeMux) {
+	m.Handle(HealthRoute, httpkit.HandlerFunc(h.Live))
+	m.Handle(ReadyRoute, httpkit.HandlerFunc(h.Ready))
+}
+
+// Live always reports ok: answering at all shows the process is up
+func (h *Health) Live(_ http.ResponseWriter, _ *http.Request) http.HandlerFunc {
+	return httpkit.JSON(api.HealthStatus{Status: StatusOK})
+}
+
+// Ready answers 503 until a database ping succeeds, then reports ready
+func (h *Health) Ready(_ http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	if !h.ready.Load() {
+		if err := h.db.Ping(r.Context()); err != nil {
+			return httpkit.JsonError(api.ServiceUnavailable(fmt.Errorf("%w: %w", ErrNotReady, err)))
+		}
+		h.ready.Store(true)
+	}
+
+	return httpkit.JSON(api.HealthStatus{Status: StatusReady})
+}
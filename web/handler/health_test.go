@@ -0,0 +1,124 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/web/handler"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports live while the database is unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := healthMux(&fakePinger{err: errors.New("connection refused")})
+
+		// Act
+		w := serveHealth(mux, "/healthz")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+
+	t.Run("it reports not ready until the first successful ping", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := &fakePinger{err: errors.New("connection refused")}
+		mux := healthMux(db)
+
+		// Act
+		starting := serveHealth(mux, "/readyz")
+		db.setErr(nil)
+		ready := serveHealth(mux, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, starting.Code)
+		assert.Equal(t, http.StatusOK, ready.Code)
+		assert.JSONEq(t, `{"status":"ready"}`, ready.Body.String())
+	})
+
+	t.Run("it stays ready without pinging again", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := &fakePinger{}
+		mux := healthMux(db)
+		serveHealth(mux, "/readyz")
+
+		// Act
+		db.setErr(errors.New("connection refused"))
+		w := serveHealth(mux, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int32(1), db.calls.Load())
+	})
+
+	t.Run("it becomes ready once under concurrent probes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := healthMux(&fakePinger{})
+
+		// Act
+		codes := make([]int, 10)
+		var wg sync.WaitGroup
+		for i := range codes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes[i] = serveHealth(mux, "/readyz").Code
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		for _, code := range codes {
+			assert.Equal(t, http.StatusOK, code)
+		}
+	})
+}
+
+// fakePinger fails with err while it is set and counts pings
+type fakePinger struct {
+	mu    sync.Mutex
+	err   error
+	calls atomic.Int32
+}
+
+func (p *fakePinger) Ping(context.Context) error {
+	p.calls.Add(1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *fakePinger) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func healthMux(db handler.Pinger) *http.ServeMux {
+	mux := http.NewServeMux()
+	handler.NewHealth(db).AddRoutes(mux)
+	return mux
+}
+
+func serveHealth(mux *http.ServeMux, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
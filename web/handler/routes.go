@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrDuplicateRoute is returned when a handler's route is already registered on the mux
+var ErrDuplicateRoute = errors.New("route already registered")
+
+// TryAddRoutes registers h's routes like h.AddRoutes, but returns ErrDuplicateRoute
+// instead of panicking when http.ServeMux rejects a pattern, e.g. because the same
+// handler was added twice. Routes registered before the conflicting one are kept.
+func TryAddRoutes(m *http.ServeMux, h RouteAdder) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrDuplicateRoute, r)
+		}
+	}()
+
+	h.AddRoutes(m)
+	return nil
+}
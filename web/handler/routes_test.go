@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/handler"
+)
+
+func TestTryAddRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it registers routes on a fresh mux", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := http.NewServeMux()
+
+		// Act
+		err := handler.TryAddRoutes(mux, handler.NewTezosGetDelegations(nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, serveStatus(mux, "/xtz/delegations?year=abc"), "Route should reach the handler's validation")
+	})
+
+	t.Run("it returns a descriptive error on a second registration", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := http.NewServeMux()
+		require.NoError(t, handler.TryAddRoutes(mux, handler.NewTezosGetDelegations(nil)))
+
+		// Act
+		err := handler.TryAddRoutes(mux, handler.NewTezosGetDelegations(nil))
+
+		// Assert
+		require.ErrorIs(t, err, handler.ErrDuplicateRoute)
+		assert.Contains(t, err.Error(), handler.GetDelegationsRoute, "Should name the conflicting pattern")
+	})
+
+	t.Run("it keeps serving the first registration", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := http.NewServeMux()
+		require.NoError(t, handler.TryAddRoutes(mux, stubRoute(handler.CountDelegatorsRoute)))
+
+		// Act
+		_ = handler.TryAddRoutes(mux, stubRoute(handler.CountDelegatorsRoute))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, serveStatus(mux, "/xtz/delegators/count"))
+	})
+}
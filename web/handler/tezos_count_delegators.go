@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const CountDelegatorsRoute = http.MethodGet + " " + "/xtz/delegators/count"
+
+// Sentinel errors
+var (
+	ErrCountFailed = errors.New("failed to count delegators")
+)
+
+type TezosCountDelegators struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosCountDelegators(finder tezos.DelegationsFinder) *TezosCountDelegators {
+	return &TezosCountDelegators{
+		finder: finder,
+	}
+}
+
+func (h *TezosCountDelegators) AddRoutes(m *http.ServeMux) {
+	m.Handle(CountDelegatorsRoute, httpkit.HandlerFunc(h.CountDelegators))
+}
+
+func (h *TezosCountDelegators) CountDelegators(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetDelegatorsCountRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// Reuse delegations criteria so filters are validated the same way; pagination is ignored
+	criteria, err := tezos.NewDelegationsCriteria(req.Year, 0, 0)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	count, err := h.finder.CountDistinctDelegators(r.Context(), criteria)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrCountFailed, err)))
+	}
+
+	// Tag the aggregate so clients can revalidate with If-None-Match and get 304 when unchanged
+	return httpkit.ConditionalJSON(api.DelegatorsCountResponse{Count: count})
+}
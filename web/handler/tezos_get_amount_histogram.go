@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetAmountHistogramRoute = http.MethodGet + " " + "/xtz/delegations/histogram"
+
+// Sentinel errors
+var (
+	ErrHistogramFailed = errors.New("failed to build amount histogram")
+)
+
+type TezosGetAmountHistogram struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetAmountHistogram(finder tezos.DelegationsFinder) *TezosGetAmountHistogram {
+	return &TezosGetAmountHistogram{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetAmountHistogram) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetAmountHistogramRoute, httpkit.HandlerFunc(h.GetAmountHistogram))
+}
+
+func (h *TezosGetAmountHistogram) GetAmountHistogram(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetAmountHistogramRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	buckets, err := tezos.HistogramBuckets(req.Buckets)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidBuckets, err)))
+	}
+
+	// Reuse delegations criteria so filters are validated the same way; pagination is ignored
+	criteria, err := tezos.NewDelegationsCriteria(req.Year, 0, 0)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	levels, err := tezos.NewLevelRange(req.MinLevel, req.MaxLevel)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidLevelRange, err)))
+	}
+	criteria = criteria.WithLevelRange(levels)
+
+	histogram, err := h.finder.AmountHistogram(r.Context(), criteria, buckets)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrHistogramFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetAmountHistogramResponse(histogram))
+}
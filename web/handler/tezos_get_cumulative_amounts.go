@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetCumulativeAmountsRoute = http.MethodGet + " " + "/xtz/delegations/tvl"
+
+// Sentinel errors
+var (
+	ErrCumulativeAmountsFailed = errors.New("failed to sum delegated amounts per month")
+)
+
+type TezosGetCumulativeAmounts struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetCumulativeAmounts(finder tezos.DelegationsFinder) *TezosGetCumulativeAmounts {
+	return &TezosGetCumulativeAmounts{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetCumulativeAmounts) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetCumulativeAmountsRoute, httpkit.HandlerFunc(h.GetCumulativeAmounts))
+}
+
+func (h *TezosGetCumulativeAmounts) GetCumulativeAmounts(_ http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetCumulativeAmountsRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// Reuse delegations criteria so filters are validated the same way; pagination is ignored
+	criteria, err := tezos.NewDelegationsCriteria(req.Year, 0, 0)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	levels, err := tezos.NewLevelRange(req.MinLevel, req.MaxLevel)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidLevelRange, err)))
+	}
+	criteria = criteria.WithLevelRange(levels)
+
+	months, err := h.finder.CumulativeAmountByMonth(r.Context(), criteria)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrCumulativeAmountsFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetCumulativeAmountsResponse(months))
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetDailyCountsRoute = http.MethodGet + " " + "/xtz/delegations/daily"
+
+// Sentinel errors
+var (
+	ErrDailyCountsFailed = errors.New("failed to count delegations per day")
+)
+
+type TezosGetDailyCounts struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetDailyCounts(finder tezos.DelegationsFinder) *TezosGetDailyCounts {
+	return &TezosGetDailyCounts{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetDailyCounts) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetDailyCountsRoute, httpkit.HandlerFunc(h.GetDailyCounts))
+}
+
+func (h *TezosGetDailyCounts) GetDailyCounts(_ http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetDailyCountsRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	year, err := tezos.ParseRequiredYear(req.Year)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidYear, err)))
+	}
+
+	days, err := h.finder.DailyCounts(r.Context(), year)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrDailyCountsFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetDailyCountsResponse(year, days))
+}
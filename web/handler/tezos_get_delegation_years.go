@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetDelegationYearsRoute = http.MethodGet + " " + "/xtz/delegations/years"
+
+// Sentinel errors
+var (
+	ErrYearsFailed = errors.New("failed to list delegation years")
+)
+
+type TezosGetDelegationYears struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetDelegationYears(finder tezos.DelegationsFinder) *TezosGetDelegationYears {
+	return &TezosGetDelegationYears{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetDelegationYears) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetDelegationYearsRoute, httpkit.HandlerFunc(h.GetDelegationYears))
+}
+
+func (h *TezosGetDelegationYears) GetDelegationYears(_ http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	years, err := h.finder.DistinctYears(r.Context())
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrYearsFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetDelegationYearsResponse(years))
+}
@@ -20,14 +20,37 @@ var (
 	ErrQueryFailed = errors.New("failed to query delegations")
 )
 
+// GetDelegationsOption configures the delegations listing handler
+type GetDelegationsOption func(*TezosGetDelegations)
+
+// WithPageBase sets the page number clients use for the first page, in requests,
+// echoed criteria and Link headers alike (bind.OneBasedPages by default)
+func WithPageBase(base bind.PageBase) GetDelegationsOption {
+	return func(h *TezosGetDelegations) { h.pageBase = base }
+}
+
+// WithJSONP answers requests carrying a callback parameter with a JSONP script instead
+// of JSON, for legacy browser clients that cannot use CORS. Off by default, in which
+// case callback is ignored like any unknown parameter.
+func WithJSONP(enabled bool) GetDelegationsOption {
+	return func(h *TezosGetDelegations) { h.jsonp = enabled }
+}
+
 type TezosGetDelegations struct {
-	finder tezos.DelegationsFinder
+	finder   tezos.DelegationsFinder
+	pageBase bind.PageBase
+	jsonp    bool
 }
 
-func NewTezosGetDelegations(finder tezos.DelegationsFinder) *TezosGetDelegations {
-	return &TezosGetDelegations{
-		finder: finder,
+func NewTezosGetDelegations(finder tezos.DelegationsFinder, opts ...GetDelegationsOption) *TezosGetDelegations {
+	h := &TezosGetDelegations{
+		finder:   finder,
+		pageBase: bind.OneBasedPages,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 func (h *TezosGetDelegations) AddRoutes(m *http.ServeMux) {
@@ -36,7 +59,7 @@ func (h *TezosGetDelegations) AddRoutes(m *http.ServeMux) {
 
 func (h *TezosGetDelegations) GetDelegations(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
 	// Parse query parameters using bind layer
-	req, err := bind.GetDelegationsRequest(r)
+	req, err := bind.GetDelegationsRequest(r, bind.WithPageBase(h.pageBase))
 	if err != nil {
 		return httpkit.JsonError(api.BadRequest(err))
 	}
@@ -47,6 +70,29 @@ func (h *TezosGetDelegations) GetDelegations(w http.ResponseWriter, r *http.Requ
 		return httpkit.JsonError(api.BadRequest(err))
 	}
 
+	levels, err := tezos.NewLevelRange(req.MinLevel, req.MaxLevel)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidLevelRange, err)))
+	}
+	criteria = criteria.WithLevelRange(levels)
+
+	var callback string
+	if h.jsonp {
+		callback, err = bind.GetJSONPCallback(r)
+		if err != nil {
+			return httpkit.JsonError(api.BadRequest(err))
+		}
+	}
+
+	// Clients sending Prefer: return=minimal only want the headers, so never stream a body
+	minimal := httpkit.PrefersMinimal(r)
+
+	// Stream NDJSON when the client asks for it and the finder supports row streaming;
+	// a JSONP callback asks for the whole page instead
+	if streamer, ok := h.finder.(tezos.DelegationsStreamer); ok && !minimal && callback == "" && httpkit.Accepts(r, httpkit.NDJSONContentType) {
+		return h.streamDelegations(w, r, streamer, criteria, req.Fields)
+	}
+
 	// Query delegations
 	page, err := h.finder.FindDelegations(r.Context(), criteria)
 	if err != nil {
@@ -54,42 +100,74 @@ func (h *TezosGetDelegations) GetDelegations(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Build GitHub-style Link header for navigation
-	if linkHeader := buildPaginationLinks(page, r.URL); linkHeader != "" {
+	if linkHeader := buildPaginationLinks(page, r.URL, h.pageBase); linkHeader != "" {
 		w.Header().Set("Link", linkHeader)
 	}
 
+	if minimal {
+		return httpkit.Minimal()
+	}
+
 	// Return JSON response
-	resp := bind.GetDelegationsResponse(page.Delegations)
+	resp := bind.GetDelegationsResponse(page.Delegations, req.Fields...)
+	if req.EchoCriteria {
+		resp.Applied = bind.GetAppliedCriteria(criteria, bind.WithPageBase(h.pageBase))
+	}
+	if callback != "" {
+		return httpkit.JSONP(callback, resp)
+	}
 	return httpkit.JSON(resp)
 }
 
+// streamDelegations writes the page as NDJSON, one delegation per line, straight from the
+// store rows. Link headers are omitted: whether more pages exist is unknown until the
+// last row, after the headers have gone out. A failure after the first line truncates
+// the stream; the error is still recorded for the logging middleware.
+func (h *TezosGetDelegations) streamDelegations(w http.ResponseWriter, r *http.Request, streamer tezos.DelegationsStreamer, criteria tezos.DelegationsCriteria, fields []string) http.HandlerFunc {
+	stream := httpkit.NewNDJSONStream(w)
+	err := streamer.FindDelegationsStream(r.Context(), criteria, func(d tezos.Delegation) error {
+		return stream.Write(bind.GetDelegation(d, fields...))
+	})
+	if err != nil {
+		apiErr := api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err))
+		if !stream.Started() {
+			return httpkit.JsonError(apiErr)
+		}
+		httpkit.SetError(r.Context(), apiErr)
+		return nil
+	}
+
+	stream.Start() // no rows: still answer 200 with an empty body
+	return nil
+}
+
 // buildPaginationLinks creates GitHub-style Link header for pagination navigation
-func buildPaginationLinks(page *tezos.DelegationsPage, baseURL *url.URL) string {
+func buildPaginationLinks(page *tezos.DelegationsPage, baseURL *url.URL, base bind.PageBase) string {
 	var links []string
 
-	// Build base URL with existing query params (like year filter)
-	u := *baseURL
-	query := u.Query()
-
 	// Previous page link
 	if page.HasPrevious() {
-		query.Set("page", fmt.Sprintf("%d", page.Number-1))
-		query.Set("per_page", fmt.Sprintf("%d", page.Size))
-		u.RawQuery = query.Encode()
-		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, u.String()))
+		links = append(links, pageLink(baseURL, base.PageParam(page.Number-1), page.Size, "prev"))
 	}
 
 	// Next page link (GitHub-style: only if we know there are more pages)
 	if page.HasNext() {
-		query.Set("page", fmt.Sprintf("%d", page.Number+1))
-		query.Set("per_page", fmt.Sprintf("%d", page.Size))
-		u.RawQuery = query.Encode()
-		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, u.String()))
+		links = append(links, pageLink(baseURL, base.PageParam(page.Number+1), page.Size, "next"))
 	}
 
 	// Note: We intentionally omit "first" and "last" links for simplicity and performance.
-	// rel="first" is redundant (always page=1) and rel="last" requires expensive count(*) queries.
+	// rel="first" is redundant (always the first page number) and rel="last" requires expensive count(*) queries.
 	// Essential navigation (prev/next) works perfectly without the overhead.
 
 	return strings.Join(links, ", ")
 }
+
+// pageLink builds a single Link entry, preserving existing query params (like year filter)
+func pageLink(baseURL *url.URL, number uint64, size tezos.PerPage, rel string) string {
+	u := *baseURL
+	query := u.Query()
+	query.Set("page", fmt.Sprintf("%d", number))
+	query.Set("per_page", fmt.Sprintf("%d", size))
+	u.RawQuery = query.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const CompareYearsRoute = http.MethodGet + " " + "/xtz/delegations/compare"
+
+// Sentinel errors
+var (
+	ErrCompareFailed = errors.New("failed to compare delegation years")
+)
+
+type TezosCompareYears struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosCompareYears(finder tezos.DelegationsFinder) *TezosCompareYears {
+	return &TezosCompareYears{
+		finder: finder,
+	}
+}
+
+func (h *TezosCompareYears) AddRoutes(m *http.ServeMux) {
+	m.Handle(CompareYearsRoute, httpkit.HandlerFunc(h.CompareYears))
+}
+
+func (h *TezosCompareYears) CompareYears(_ http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetCompareYearsRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	yearA, err := tezos.ParseRequiredYear(req.YearA)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: year_a: %w", bind.ErrInvalidYear, err)))
+	}
+
+	yearB, err := tezos.ParseRequiredYear(req.YearB)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: year_b: %w", bind.ErrInvalidYear, err)))
+	}
+
+	comparison, err := h.finder.CompareYears(r.Context(), yearA, yearB)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrCompareFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetYearComparisonResponse(comparison))
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const (
+	GetDelegationsSinceRoute = http.MethodGet + " " + "/xtz/delegations/since"
+
+	// MaxIDHeader carries the highest delegation ID in the response, to be used as the next "id" cursor
+	MaxIDHeader = "X-Max-ID"
+)
+
+type TezosGetDelegationsSince struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetDelegationsSince(finder tezos.DelegationsFinder) *TezosGetDelegationsSince {
+	return &TezosGetDelegationsSince{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetDelegationsSince) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetDelegationsSinceRoute, httpkit.HandlerFunc(h.GetDelegationsSince))
+}
+
+func (h *TezosGetDelegationsSince) GetDelegationsSince(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetDelegationsSinceRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// Reuse per_page validation for the limit: same default and maximum
+	limit, err := tezos.ParsePerPageFromUint64(req.Limit)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(fmt.Errorf("%w: %w", bind.ErrInvalidLimit, err)))
+	}
+
+	sinceID := int64(req.ID)
+
+	// Query delegations
+	delegations, err := h.finder.FindSinceID(r.Context(), sinceID, limit.Uint64())
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err)))
+	}
+
+	// Delegations are ordered by ID ascending, so the last one carries the next cursor.
+	// With no new delegations the cursor stays where the client left it.
+	maxID := sinceID
+	if len(delegations) > 0 {
+		maxID = delegations[len(delegations)-1].ID
+	}
+	w.Header().Set(MaxIDHeader, strconv.FormatInt(maxID, 10))
+
+	// Return JSON response
+	resp := bind.GetDelegationsResponse(delegations)
+	return httpkit.JSON(resp)
+}
@@ -0,0 +1,451 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestTezosGetDelegationsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	delegations := []tezos.Delegation{
+		{ID: 3, Timestamp: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 3_000_000, Delegator: "tz1c", Level: 300},
+		{ID: 2, Timestamp: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Amount: 2_000_000, Delegator: "tz1b", Level: 200},
+		{ID: 1, Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 1_000_000, Delegator: "tz1a", Level: 100},
+	}
+
+	t.Run("it streams one delegation per line", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations", httpkit.NDJSONContentType)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.NDJSONContentType, w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("Link"))
+
+		lines := decodeLines(t, w.Body.String())
+		require.Len(t, lines, len(delegations))
+		assert.Equal(t, api.Delegation{
+			Timestamp: "2025-03-01T00:00:00Z",
+			Amount:    "3000000",
+			Delegator: "tz1c",
+			Level:     "300",
+		}, lines[0])
+		assert.Equal(t, "tz1a", lines[2].Delegator)
+	})
+
+	t.Run("it applies field selection to each line", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations?fields=delegator", httpkit.NDJSONContentType)
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"delegator\":\"tz1c\"}\n{\"delegator\":\"tz1b\"}\n{\"delegator\":\"tz1a\"}\n", w.Body.String())
+	})
+
+	t.Run("it passes the criteria to the streamer", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations?year=2025&page=2&per_page=10&min_level=100", httpkit.NDJSONContentType)
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tezos.Year(2025), finder.criteria.Year)
+		assert.Equal(t, uint64(10), finder.criteria.ItemsToSkip())
+		assert.Equal(t, int64(100), finder.criteria.Levels.Min)
+	})
+
+	t.Run("it answers an empty page with 200 and no lines", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations", httpkit.NDJSONContentType)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("it returns a JSON error when the query fails before the first row", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{err: errors.New("connection refused")}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations", httpkit.NDJSONContentType)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("it truncates the stream when the query fails mid-stream", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations, err: errors.New("connection reset")}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations", httpkit.NDJSONContentType)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, decodeLines(t, w.Body.String()), len(delegations))
+	})
+
+	t.Run("it returns the regular JSON page without the NDJSON Accept header", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegations(finder, "/xtz/delegations", "application/json")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var resp api.DelegationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, len(delegations))
+	})
+}
+
+func TestTezosGetDelegationsPreferMinimal(t *testing.T) {
+	t.Parallel()
+
+	delegations := []tezos.Delegation{
+		{ID: 1, Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 1_000_000, Delegator: "tz1a", Level: 100},
+	}
+
+	t.Run("it answers 204 with the Link header and no body", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegationsPreferring(finder, "/xtz/delegations?page=2&per_page=1", "application/json", "return=minimal")
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, `</xtz/delegations?page=1&per_page=1>; rel="prev"`, w.Header().Get("Link"))
+		assert.Equal(t, "return=minimal", w.Header().Get("Preference-Applied"))
+	})
+
+	t.Run("it takes precedence over NDJSON streaming", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegationsPreferring(finder, "/xtz/delegations", httpkit.NDJSONContentType, "return=minimal")
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("it returns the full page for other preferences", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: delegations}
+
+		// Act
+		w := serveDelegationsPreferring(finder, "/xtz/delegations", "application/json", "return=representation")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Preference-Applied"))
+
+		var resp api.DelegationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, len(delegations))
+	})
+}
+
+func TestTezosGetDelegationsPageBase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it numbers requested and linked pages from 0 when 0-based", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &streamingFinder{delegations: []tezos.Delegation{{ID: 1, Level: 100}}}
+		mux := http.NewServeMux()
+		handler.NewTezosGetDelegations(finder, handler.WithPageBase(bind.ZeroBasedPages)).AddRoutes(mux)
+
+		r := httptest.NewRequest(http.MethodGet, "/xtz/delegations?page=1&per_page=1&echo_criteria=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		mux.ServeHTTP(w, r)
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `</xtz/delegations?echo_criteria=true&page=0&per_page=1>; rel="prev"`, w.Header().Get("Link"))
+
+		var resp api.DelegationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Applied)
+		assert.Equal(t, uint64(1), resp.Applied.Page, "The applied page should stay 0-based")
+	})
+}
+
+func TestTezosGetDelegationsJSONP(t *testing.T) {
+	t.Parallel()
+
+	serve := func(target string, opts ...handler.GetDelegationsOption) *httptest.ResponseRecorder {
+		finder := &streamingFinder{delegations: []tezos.Delegation{{ID: 1, Level: 100}}}
+		mux := http.NewServeMux()
+		handler.NewTezosGetDelegations(finder, opts...).AddRoutes(mux)
+
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("it wraps the page in a valid callback", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serve("/xtz/delegations?callback=app.onDelegations", handler.WithJSONP(true))
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpkit.JavaScriptContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+		body := w.Body.String()
+		require.True(t, strings.HasPrefix(body, "/**/app.onDelegations("), "body: %s", body)
+		require.True(t, strings.HasSuffix(body, ");"), "body: %s", body)
+
+		payload := strings.TrimSuffix(strings.TrimPrefix(body, "/**/app.onDelegations("), ");")
+		var resp api.DelegationsResponse
+		require.NoError(t, json.Unmarshal([]byte(payload), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("it rejects a callback that is not an identifier", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serve("/xtz/delegations?callback=alert(1)//", handler.WithJSONP(true))
+
+		// Assert
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.NotContains(t, w.Body.String(), "alert(1)")
+	})
+
+	t.Run("it answers plain JSON without a callback", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serve("/xtz/delegations", handler.WithJSONP(true))
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+		var resp api.DelegationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("it ignores the callback when JSONP is disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serve("/xtz/delegations?callback=alert(1)//")
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.NotContains(t, w.Body.String(), "alert(1)")
+	})
+}
+
+func TestTezosGetDelegationsErrorFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it answers {code,message} by default", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveDelegations(&streamingFinder{}, "/xtz/delegations?page=abc", "application/json")
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.InDelta(t, http.StatusBadRequest, body["code"], 0)
+		assert.NotEmpty(t, body["message"])
+	})
+
+	t.Run("it answers problem details when negotiated", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		w := serveDelegations(&streamingFinder{}, "/xtz/delegations?page=abc", httpkit.ProblemJSONContentType)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, httpkit.ProblemJSONContentType, w.Header().Get("Content-Type"))
+
+		var problem httpkit.ProblemDetails
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, "Bad Request", problem.Title)
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Contains(t, problem.Detail, "page")
+	})
+
+	t.Run("it hides internal details in problem details too", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		accept := httpkit.NDJSONContentType + ", " + httpkit.ProblemJSONContentType
+		w := serveDelegations(&streamingFinder{err: errors.New("connection refused")}, "/xtz/delegations", accept)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500}`, w.Body.String())
+	})
+}
+
+// streamingFinder yields its delegations, then fails with err if set
+type streamingFinder struct {
+	delegations []tezos.Delegation
+	err         error
+	criteria    tezos.DelegationsCriteria
+}
+
+func (f *streamingFinder) FindDelegations(_ context.Context, criteria tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+	return &tezos.DelegationsPage{Delegations: f.delegations, Number: criteria.Page, Size: criteria.Size}, nil
+}
+
+func (f *streamingFinder) FindSinceID(context.Context, int64, uint64) ([]tezos.Delegation, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) CountDistinctDelegators(context.Context, tezos.DelegationsCriteria) (int64, error) {
+	return 0, nil
+}
+
+func (f *streamingFinder) FindRecent(context.Context, uint64) ([]tezos.Delegation, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) FindTopByAmount(context.Context, tezos.DelegationsCriteria, uint64) ([]tezos.Delegation, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) AmountHistogram(context.Context, tezos.DelegationsCriteria, int) ([]tezos.Bucket, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) DistinctYears(context.Context) ([]tezos.YearCount, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) CompareYears(context.Context, tezos.Year, tezos.Year) (tezos.YearComparison, error) {
+	return tezos.YearComparison{}, nil
+}
+
+func (f *streamingFinder) DailyCounts(context.Context, tezos.Year) ([]tezos.DayCount, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) CumulativeAmountByMonth(context.Context, tezos.DelegationsCriteria) ([]tezos.MonthlyAmount, error) {
+	return nil, nil
+}
+
+func (f *streamingFinder) FindDelegationsStream(_ context.Context, criteria tezos.DelegationsCriteria, yield func(tezos.Delegation) error) error {
+	f.criteria = criteria
+	for _, d := range f.delegations {
+		if err := yield(d); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func serveDelegations(finder tezos.DelegationsFinder, target, accept string) *httptest.ResponseRecorder {
+	return serveDelegationsPreferring(finder, target, accept, "")
+}
+
+func serveDelegationsPreferring(finder tezos.DelegationsFinder, target, accept, prefer string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	handler.NewTezosGetDelegations(finder).AddRoutes(mux)
+
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set("Accept", accept)
+	if prefer != "" {
+		r.Header.Set("Prefer", prefer)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	return w
+}
+
+func decodeLines(t *testing.T, body string) []api.Delegation {
+	t.Helper()
+
+	var lines []api.Delegation
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		lines = append(lines, decodeLine(t, scanner.Text()))
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func decodeLine(t *testing.T, line string) api.Delegation {
+	t.Helper()
+
+	var d api.Delegation
+	require.NoError(t, json.Unmarshal([]byte(line), &d))
+	return d
+}
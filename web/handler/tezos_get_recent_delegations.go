@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetRecentDelegationsRoute = http.MethodGet + " " + "/xtz/delegations/recent"
+
+type TezosGetRecentDelegations struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetRecentDelegations(finder tezos.DelegationsFinder) *TezosGetRecentDelegations {
+	return &TezosGetRecentDelegations{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetRecentDelegations) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetRecentDelegationsRoute, httpkit.HandlerFunc(h.GetRecentDelegations))
+}
+
+func (h *TezosGetRecentDelegations) GetRecentDelegations(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetRecentDelegationsRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// Skip the criteria path entirely: no year, page or offset, and the limit is capped not rejected
+	delegations, err := h.finder.FindRecent(r.Context(), tezos.RecentLimit(req.Limit))
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err)))
+	}
+
+	// Return JSON response
+	resp := bind.GetDelegationsResponse(delegations)
+	return httpkit.JSON(resp)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const GetTopDelegationsRoute = http.MethodGet + " " + "/xtz/delegations/top"
+
+type TezosGetTopDelegations struct {
+	finder tezos.DelegationsFinder
+}
+
+func NewTezosGetTopDelegations(finder tezos.DelegationsFinder) *TezosGetTopDelegations {
+	return &TezosGetTopDelegations{
+		finder: finder,
+	}
+}
+
+func (h *TezosGetTopDelegations) AddRoutes(m *http.ServeMux) {
+	m.Handle(GetTopDelegationsRoute, httpkit.HandlerFunc(h.GetTopDelegations))
+}
+
+func (h *TezosGetTopDelegations) GetTopDelegations(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	// Parse query parameters using bind layer
+	req, err := bind.GetTopDelegationsRequest(r)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// Reuse delegations criteria so the year is validated the same way; pagination is ignored
+	criteria, err := tezos.NewDelegationsCriteria(req.Year, 0, 0)
+	if err != nil {
+		return httpkit.JsonError(api.BadRequest(err))
+	}
+
+	// The limit is capped rather than rejected, like the recent delegations limit
+	delegations, err := h.finder.FindTopByAmount(r.Context(), criteria, tezos.TopLimit(req.Limit))
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err)))
+	}
+
+	// Return JSON response
+	return httpkit.JSON(bind.GetDelegationsResponse(delegations))
+}
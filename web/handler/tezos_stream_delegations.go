@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/clock"
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler/bind"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const (
+	StreamDelegationsRoute = http.MethodGet + " " + "/xtz/delegations/stream"
+
+	// DefaultStreamPollInterval is how often the stream checks the store for new delegations
+	DefaultStreamPollInterval = 2 * time.Second
+
+	// StreamLatestEvent names the event opening the stream, carrying the starting cursor
+	StreamLatestEvent = "latest"
+
+	// lastEventIDHeader is sent by reconnecting EventSource clients to resume after the given id
+	lastEventIDHeader = "Last-Event-ID"
+)
+
+// StreamClock schedules the polls of the delegations stream
+type StreamClock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+// StreamOption configures the delegations stream handler
+type StreamOption func(*TezosStreamDelegations)
+
+// WithStreamPollInterval sets how often the store is polled; non-positive values are ignored
+func WithStreamPollInterval(d time.Duration) StreamOption {
+	return func(h *TezosStreamDelegations) {
+		if d > 0 {
+			h.interval = d
+		}
+	}
+}
+
+// WithStreamClock injects a custom clock (e.g., for testing)
+func WithStreamClock(c StreamClock) StreamOption {
+	return func(h *TezosStreamDelegations) { h.clock = c }
+}
+
+type TezosStreamDelegations struct {
+	finder   tezos.DelegationsTailFinder
+	interval time.Duration
+	clock    StreamClock
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewTezosStreamDelegations(finder tezos.DelegationsTailFinder, opts ...StreamOption) *TezosStreamDelegations {
+	h := &TezosStreamDelegations{
+		finder:   finder,
+		interval: DefaultStreamPollInterval,
+		clock:    clock.SystemClock{},
+		closed:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *TezosStreamDelegations) AddRoutes(m *http.ServeMux) {
+	m.Handle(StreamDelegationsRoute, httpkit.HandlerFunc(h.StreamDelegations))
+}
+
+// StreamDelegations sends Server-Sent Events: a "latest" event with the current cursor,
+// then every delegation stored after it as a plain data event whose id is the delegation ID.
+// A reconnecting client resumes after its Last-Event-ID. The stream runs until the client
+// goes away or Close is called; a store failure ends it early and is recorded for the
+// logging middleware.
+func (h *TezosStreamDelegations) StreamDelegations(w http.ResponseWriter, r *http.Request) http.HandlerFunc {
+	ctx := r.Context()
+
+	cursor, err := h.startCursor(ctx, r)
+	if err != nil {
+		return httpkit.JsonError(api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err)))
+	}
+
+	stream := httpkit.NewSSEStream(w)
+	if err := stream.Send(StreamLatestEvent, formatEventID(cursor), api.StreamCursor{ID: cursor}); err != nil {
+		return nil // the client is gone or the writer cannot stream
+	}
+
+	for {
+		delegations, err := h.finder.FindSinceID(ctx, cursor, tezos.MaxPerPage)
+		if err != nil {
+			if ctx.Err() == nil {
+				httpkit.SetError(ctx, api.InternalServerError(fmt.Errorf("%w: %w", ErrQueryFailed, err)))
+			}
+			return nil
+		}
+
+		for _, d := range delegations {
+			if err := stream.Send("", formatEventID(d.ID), bind.GetDelegation(d)); err != nil {
+				return nil
+			}
+			cursor = d.ID
+		}
+
+		// A full batch means more delegations are likely waiting: fetch them right away
+		if len(delegations) == tezos.MaxPerPage {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.closed:
+			return nil
+		case <-h.clock.After(h.interval):
+		}
+	}
+}
+
+// Close ends all open streams, so a graceful server shutdown is not held up by
+// clients that never disconnect. Register it with http.Server.RegisterOnShutdown.
+func (h *TezosStreamDelegations) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// startCursor resumes after a valid Last-Event-ID, or starts from the latest stored delegation
+func (h *TezosStreamDelegations) startCursor(ctx context.Context, r *http.Request) (int64, error) {
+	if id, err := strconv.ParseInt(r.Header.Get(lastEventIDHeader), 10, 64); err == nil && id >= 0 {
+		return id, nil
+	}
+	return h.finder.LatestID(ctx)
+}
+
+func formatEventID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
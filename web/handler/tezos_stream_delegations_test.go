@@ -0,0 +1,265 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/api"
+	"github.com/screwyprof/delegator/web/handler"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestTezosStreamDelegations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it opens with the latest id and emits delegations stored afterwards", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &tailFinder{delegations: []tezos.Delegation{{ID: 7, Delegator: "tz1old"}}}
+		ticks := manualClock(make(chan time.Time))
+		server, done := newStreamServer(t, finder, ticks)
+
+		body := openStream(t, server.URL, "")
+
+		// Act
+		latest := readEvent(t, body)
+		finder.add(tezos.Delegation{ID: 8, Timestamp: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 1_000_000, Delegator: "tz1new", Level: 300})
+		ticks <- time.Now()
+		next := readEvent(t, body)
+
+		// Assert
+		assert.Equal(t, handler.StreamLatestEvent, latest.event)
+		assert.Equal(t, "7", latest.id)
+		assert.JSONEq(t, `{"id":7}`, latest.data)
+
+		assert.Empty(t, next.event, "delegations should be plain message events")
+		assert.Equal(t, "8", next.id)
+		var delegation api.Delegation
+		require.NoError(t, json.Unmarshal([]byte(next.data), &delegation))
+		assert.Equal(t, "tz1new", delegation.Delegator)
+		assert.Equal(t, "300", delegation.Level)
+
+		assertNotDone(t, done)
+	})
+
+	t.Run("it resumes after the Last-Event-ID sent by a reconnecting client", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &tailFinder{delegations: []tezos.Delegation{{ID: 1}, {ID: 2}, {ID: 3}}}
+		server, _ := newStreamServer(t, finder, manualClock(make(chan time.Time)))
+
+		// Act
+		body := openStream(t, server.URL, "1")
+		latest := readEvent(t, body)
+		second := readEvent(t, body)
+		third := readEvent(t, body)
+
+		// Assert
+		assert.Equal(t, "1", latest.id)
+		assert.Equal(t, "2", second.id)
+		assert.Equal(t, "3", third.id)
+	})
+
+	t.Run("it stops streaming when the client goes away", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &tailFinder{}
+		server, done := newStreamServer(t, finder, manualClock(make(chan time.Time)))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/xtz/delegations/stream", nil)
+		require.NoError(t, err)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		readEvent(t, bufio.NewReader(resp.Body))
+
+		// Act
+		cancel()
+
+		// Assert
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("handler should return once the request context is cancelled")
+		}
+	})
+
+	t.Run("it ends open streams when closed", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		mux := http.NewServeMux()
+		streamHandler := handler.NewTezosStreamDelegations(&tailFinder{}, handler.WithStreamClock(manualClock(make(chan time.Time))))
+		streamHandler.AddRoutes(mux)
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+
+		body := openStream(t, server.URL, "")
+		readEvent(t, body)
+
+		// Act
+		streamHandler.Close()
+
+		// Assert
+		_, err := body.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF, "the response should end once the handler returns")
+	})
+
+	t.Run("it answers a JSON error when the latest id cannot be read", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		finder := &tailFinder{latestErr: errors.New("connection refused")}
+		mux := http.NewServeMux()
+		handler.NewTezosStreamDelegations(finder).AddRoutes(mux)
+
+		// Act
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xtz/delegations/stream", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+}
+
+// tailFinder serves delegations that tests append to while the stream is open
+type tailFinder struct {
+	tezos.DelegationsFinder
+
+	mu          sync.Mutex
+	delegations []tezos.Delegation
+	latestErr   error
+}
+
+func (f *tailFinder) add(d tezos.Delegation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delegations = append(f.delegations, d)
+}
+
+func (f *tailFinder) LatestID(context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.latestErr != nil {
+		return 0, f.latestErr
+	}
+	if len(f.delegations) == 0 {
+		return 0, nil
+	}
+	return f.delegations[len(f.delegations)-1].ID, nil
+}
+
+func (f *tailFinder) FindSinceID(ctx context.Context, id int64, limit uint64) ([]tezos.Delegation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var found []tezos.Delegation
+	for _, d := range f.delegations {
+		if d.ID > id && uint64(len(found)) < limit {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+// manualClock fires a poll each time the test sends on it
+type manualClock chan time.Time
+
+func (c manualClock) After(time.Duration) <-chan time.Time {
+	return c
+}
+
+// sseEvent is a single parsed Server-Sent Event
+type sseEvent struct {
+	event, id, data string
+}
+
+// newStreamServer serves the stream handler; done is closed once the handler returns
+func newStreamServer(t *testing.T, finder tezos.DelegationsTailFinder, clock handler.StreamClock) (*httptest.Server, <-chan struct{}) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	handler.NewTezosStreamDelegations(finder, handler.WithStreamClock(clock)).AddRoutes(mux)
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, done
+}
+
+// openStream connects to the stream, optionally resuming after lastEventID
+func openStream(t *testing.T, baseURL, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/stream", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, httpkit.EventStreamContentType, resp.Header.Get("Content-Type"))
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads fields up to the blank line ending the next event
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return ev
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "event":
+			ev.event = value
+		case "id":
+			ev.id = value
+		case "data":
+			ev.data = value
+		}
+	}
+}
+
+func assertNotDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-done:
+		t.Fatal("stream should stay open while the client is connected")
+	default:
+	}
+}
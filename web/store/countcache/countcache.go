@@ -0,0 +1,101 @@
+// Package countcache caches delegation count queries in front of a DelegationsFinder
+package countcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/clock"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+// Clock provides the current time to the cache
+type Clock interface {
+	Now() time.Time
+}
+
+// Option configures the caching Finder
+type Option func(*Finder)
+
+// WithClock injects a custom Clock (e.g., for testing)
+func WithClock(c Clock) Option {
+	return func(f *Finder) { f.clock = c }
+}
+
+// Finder decorates a DelegationsFinder, caching CountDistinctDelegators results per
+// filter (year and level range) for a TTL. Pagination does not affect the key.
+// Other queries pass straight through to the wrapped finder.
+type Finder struct {
+	tezos.DelegationsFinder
+
+	ttl   time.Duration
+	clock Clock
+
+	mu         sync.Mutex
+	entries    map[filterKey]entry
+	generation uint64 // bumped by NotifyWrite so in-flight queries cannot repopulate stale counts
+}
+
+// filterKey is the filter portion of the criteria
+type filterKey struct {
+	year   tezos.Year
+	levels tezos.LevelRange
+}
+
+type entry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// New wraps finder with a count cache whose entries live for ttl
+func New(finder tezos.DelegationsFinder, ttl time.Duration, opts ...Option) *Finder {
+	f := &Finder{
+		DelegationsFinder: finder,
+		ttl:               ttl,
+		clock:             clock.SystemClock{},
+		entries:           make(map[filterKey]entry),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// CountDistinctDelegators returns the cached count for the criteria's filters while
+// it is fresh, otherwise queries the wrapped finder and caches the result. Errors are not cached.
+func (f *Finder) CountDistinctDelegators(ctx context.Context, criteria tezos.DelegationsCriteria) (int64, error) {
+	key := filterKey{year: criteria.Year, levels: criteria.Levels}
+
+	f.mu.Lock()
+	cached, ok := f.entries[key]
+	generation := f.generation
+	f.mu.Unlock()
+
+	if ok && f.clock.Now().Before(cached.expiresAt) {
+		return cached.count, nil
+	}
+
+	count, err := f.DelegationsFinder.CountDistinctDelegators(ctx, criteria)
+	if err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	if f.generation == generation {
+		f.entries[key] = entry{count: count, expiresAt: f.clock.Now().Add(f.ttl)}
+	}
+	f.mu.Unlock()
+
+	return count, nil
+}
+
+// NotifyWrite invalidates every cached count; call it after delegations are written,
+// e.g. from pgxstore.ListenWrites
+func (f *Finder) NotifyWrite() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.generation++
+	clear(f.entries)
+}
@@ -0,0 +1,179 @@
+package countcache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/store/countcache"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const ttl = time.Minute
+
+func TestFinderCountDistinctDelegators(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it serves repeated counts from the cache within the TTL", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{}
+		store.count.Store(3)
+		clock := &stoppedClock{}
+		finder := countcache.New(store, ttl, countcache.WithClock(clock))
+
+		// Act
+		first := countFor(t, finder, criteriaFor(t, 2025, 1))
+		clock.advance(ttl - time.Second)
+		store.count.Store(4)
+		second := countFor(t, finder, criteriaFor(t, 2025, 2))
+
+		// Assert
+		assert.Equal(t, int64(3), first)
+		assert.Equal(t, int64(3), second, "Should ignore pagination and reuse the cached count")
+		assert.Equal(t, int32(1), store.calls.Load())
+	})
+
+	t.Run("it queries again once the TTL expires", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{}
+		store.count.Store(3)
+		clock := &stoppedClock{}
+		finder := countcache.New(store, ttl, countcache.WithClock(clock))
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Act
+		clock.advance(ttl)
+		store.count.Store(4)
+		count := countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(4), count)
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+
+	t.Run("it keys entries by year and level range", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{}
+		finder := countcache.New(store, ttl, countcache.WithClock(&stoppedClock{}))
+
+		// Act
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+		countFor(t, finder, criteriaFor(t, 2024, 1))
+		countFor(t, finder, criteriaFor(t, 2025, 1).WithLevelRange(tezos.LevelRange{Min: 100}))
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int32(3), store.calls.Load(), "Each distinct filter should be queried once")
+	})
+
+	t.Run("it invalidates cached counts after a write", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{}
+		store.count.Store(3)
+		finder := countcache.New(store, ttl, countcache.WithClock(&stoppedClock{}))
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Act
+		store.count.Store(4)
+		finder.NotifyWrite()
+		count := countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(4), count)
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+
+	t.Run("it does not cache a count that raced with a write", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{}
+		store.count.Store(3)
+		finder := countcache.New(store, ttl, countcache.WithClock(&stoppedClock{}))
+		store.beforeReturn = finder.NotifyWrite // a write lands while the count query runs
+
+		// Act
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+		store.beforeReturn = nil
+		countFor(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int32(2), store.calls.Load(), "The stale count should not have been cached")
+	})
+
+	t.Run("it does not cache errors", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := &countingFinder{err: errors.New("connection refused")}
+		finder := countcache.New(store, ttl, countcache.WithClock(&stoppedClock{}))
+
+		// Act
+		_, firstErr := finder.CountDistinctDelegators(t.Context(), criteriaFor(t, 2025, 1))
+		_, secondErr := finder.CountDistinctDelegators(t.Context(), criteriaFor(t, 2025, 1))
+
+		// Assert
+		require.Error(t, firstErr)
+		require.Error(t, secondErr)
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+}
+
+// countingFinder counts CountDistinctDelegators calls and returns a configurable count
+type countingFinder struct {
+	tezos.DelegationsFinder
+
+	count        atomic.Int64
+	calls        atomic.Int32
+	err          error
+	beforeReturn func()
+}
+
+func (f *countingFinder) CountDistinctDelegators(context.Context, tezos.DelegationsCriteria) (int64, error) {
+	f.calls.Add(1)
+	if f.beforeReturn != nil {
+		f.beforeReturn()
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.count.Load(), nil
+}
+
+// stoppedClock only moves when advanced
+type stoppedClock struct {
+	now time.Duration
+}
+
+func (c *stoppedClock) Now() time.Time { return time.Unix(0, 0).Add(c.now) }
+
+func (c *stoppedClock) advance(d time.Duration) { c.now += d }
+
+func criteriaFor(t *testing.T, year, page uint64) tezos.DelegationsCriteria {
+	t.Helper()
+
+	criteria, err := tezos.NewDelegationsCriteria(year, page, 0)
+	require.NoError(t, err)
+	return criteria
+}
+
+func countFor(t *testing.T, finder *countcache.Finder, criteria tezos.DelegationsCriteria) int64 {
+	t.Helper()
+
+	count, err := finder.CountDistinctDelegators(t.Context(), criteria)
+	require.NoError(t, err)
+	return count
+}
@@ -11,4 +11,5 @@ type Delegation struct {
 	Amount    int64     `db:"amount"`
 	Delegator string    `db:"delegator"`
 	Level     int64     `db:"level"`
+	CreatedAt time.Time `db:"created_at"`
 }
@@ -0,0 +1,59 @@
+// Package dedup collapses identical concurrent delegation queries into one round trip
+package dedup
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+// Finder decorates a DelegationsFinder so concurrent FindDelegations calls with equal
+// criteria share a single query to the wrapped finder. Every waiter receives the same
+// page (which callers must treat as read-only) or the same error.
+//
+// The shared query runs detached from the cancellation of the caller that started it,
+// so one client going away does not fail the others; each caller still stops waiting
+// as soon as its own context is done. Other queries pass straight through.
+type Finder struct {
+	tezos.DelegationsFinder
+
+	group singleflight.Group
+}
+
+// New wraps finder with query deduplication
+func New(finder tezos.DelegationsFinder) *Finder {
+	return &Finder{DelegationsFinder: finder}
+}
+
+// FindDelegations returns the page for criteria, joining an identical query already in flight
+func (f *Finder) FindDelegations(ctx context.Context, criteria tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+	detached := context.WithoutCancel(ctx)
+	ch := f.group.DoChan(criteriaKey(criteria), func() (any, error) {
+		return f.DelegationsFinder.FindDelegations(detached, criteria)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*tezos.DelegationsPage), nil
+	}
+}
+
+// FindDelegationsStream streams through the wrapped finder when it supports streaming,
+// so wrapping does not hide tezos.DelegationsStreamer. Otherwise it yields the
+// delegations of the shared FindDelegations page.
+func (f *Finder) FindDelegationsStream(ctx context.Context, criteria tezos.DelegationsCriteria, yield func(tezos.Delegation) error) error {
+	return tezos.StreamPage(ctx, f.DelegationsFinder, f.FindDelegations, criteria, yield)
+}
+
+// criteriaKey identifies a query by every criteria field, filters and pagination alike
+func criteriaKey(criteria tezos.DelegationsCriteria) string {
+	return fmt.Sprintf("%+v", criteria)
+}
@@ -0,0 +1,258 @@
+package dedup_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/store/dedup"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const concurrency = 10
+
+// joinDelay gives started callers time to join the query already in flight
+const joinDelay = 50 * time.Millisecond
+
+func TestFinderFindDelegations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it shares one query between identical concurrent calls", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		finder := dedup.New(store)
+
+		// Act
+		results := findConcurrently(t, finder, criteriaFor(t, 2025, 1), store)
+
+		// Assert
+		assert.Equal(t, int32(1), store.calls.Load(), "Identical queries should share one round trip")
+		for _, res := range results {
+			require.NoError(t, res.err)
+			assert.Same(t, results[0].page, res.page)
+		}
+	})
+
+	t.Run("it propagates the error to every waiter", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		errQuery := errors.New("connection refused")
+		store := newBlockingFinder(errQuery)
+		finder := dedup.New(store)
+
+		// Act
+		results := findConcurrently(t, finder, criteriaFor(t, 2025, 1), store)
+
+		// Assert
+		assert.Equal(t, int32(1), store.calls.Load())
+		for _, res := range results {
+			assert.ErrorIs(t, res.err, errQuery)
+		}
+	})
+
+	t.Run("it keys on the full criteria", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		finder := dedup.New(store)
+		criteria := []tezos.DelegationsCriteria{
+			criteriaFor(t, 2025, 1),
+			criteriaFor(t, 2025, 2),
+			criteriaFor(t, 2024, 1),
+			criteriaFor(t, 2025, 1).WithLevelRange(tezos.LevelRange{Min: 100}),
+		}
+
+		// Act
+		var wg sync.WaitGroup
+		for _, c := range criteria {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := finder.FindDelegations(t.Context(), c)
+				assert.NoError(t, err)
+			}()
+		}
+		store.waitForCalls(t, len(criteria))
+		close(store.release)
+		wg.Wait()
+
+		// Assert
+		assert.Equal(t, int32(len(criteria)), store.calls.Load(), "Distinct criteria should not share a query")
+	})
+
+	t.Run("it queries again once the shared call has finished", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		close(store.release)
+		finder := dedup.New(store)
+
+		// Act
+		_, firstErr := finder.FindDelegations(t.Context(), criteriaFor(t, 2025, 1))
+		_, secondErr := finder.FindDelegations(t.Context(), criteriaFor(t, 2025, 1))
+
+		// Assert
+		require.NoError(t, firstErr)
+		require.NoError(t, secondErr)
+		assert.Equal(t, int32(2), store.calls.Load(), "Results should not be cached")
+	})
+
+	t.Run("it stops waiting when the caller's context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		defer close(store.release)
+		finder := dedup.New(store)
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		// Act
+		_, err := finder.FindDelegations(ctx, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("it does not fail waiters when the first caller goes away", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		finder := dedup.New(store)
+		leaderCtx, cancelLeader := context.WithCancel(t.Context())
+
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := finder.FindDelegations(leaderCtx, criteriaFor(t, 2025, 1))
+			leaderErr <- err
+		}()
+		store.waitForCalls(t, 1)
+
+		followerErr := make(chan error, 1)
+		go func() {
+			_, err := finder.FindDelegations(t.Context(), criteriaFor(t, 2025, 1))
+			followerErr <- err
+		}()
+		time.Sleep(joinDelay)
+
+		// Act
+		cancelLeader()
+		assert.ErrorIs(t, <-leaderErr, context.Canceled)
+		close(store.release)
+
+		// Assert
+		assert.NoError(t, <-followerErr)
+		assert.Equal(t, int32(1), store.calls.Load())
+	})
+}
+
+func TestFinderFindDelegationsStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it yields the shared page when the wrapped finder cannot stream", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newBlockingFinder(nil)
+		close(store.release)
+		finder := dedup.New(store)
+
+		// Act
+		var ids []int64
+		err := finder.FindDelegationsStream(t.Context(), criteriaFor(t, 2025, 1), func(d tezos.Delegation) error {
+			ids = append(ids, d.ID)
+			return nil
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 1}, ids)
+	})
+}
+
+type result struct {
+	page *tezos.DelegationsPage
+	err  error
+}
+
+// blockingFinder counts FindDelegations calls, each blocking until release is closed
+type blockingFinder struct {
+	tezos.DelegationsFinder
+
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func newBlockingFinder(err error) *blockingFinder {
+	return &blockingFinder{release: make(chan struct{}), err: err}
+}
+
+func (f *blockingFinder) FindDelegations(_ context.Context, criteria tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+	f.calls.Add(1)
+	<-f.release
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &tezos.DelegationsPage{
+		Delegations: []tezos.Delegation{{ID: 2}, {ID: 1}},
+		Number:      criteria.Page,
+		Size:        criteria.Size,
+	}, nil
+}
+
+// waitForCalls waits until n queries have reached the wrapped finder
+func (f *blockingFinder) waitForCalls(t *testing.T, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return f.calls.Load() >= int32(n) }, time.Second, time.Millisecond)
+}
+
+// findConcurrently starts identical queries, lets the single underlying call finish
+// once every caller has joined it, and returns each caller's result
+func findConcurrently(t *testing.T, finder *dedup.Finder, criteria tezos.DelegationsCriteria, store *blockingFinder) []result {
+	t.Helper()
+
+	var (
+		wg      sync.WaitGroup
+		started sync.WaitGroup
+		results = make([]result, concurrency)
+	)
+	started.Add(concurrency)
+	wg.Add(concurrency)
+	for i := range concurrency {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			page, err := finder.FindDelegations(t.Context(), criteria)
+			results[i] = result{page: page, err: err}
+		}()
+	}
+	started.Wait()
+	store.waitForCalls(t, 1)
+	time.Sleep(joinDelay)
+	close(store.release)
+	wg.Wait()
+
+	return results
+}
+
+func criteriaFor(t *testing.T, year, page uint64) tezos.DelegationsCriteria {
+	t.Helper()
+
+	criteria, err := tezos.NewDelegationsCriteria(year, page, 0)
+	require.NoError(t, err)
+	return criteria
+}
@@ -0,0 +1,37 @@
+package pgxstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WriteChannel is the channel the scraper notifies whenever a saved batch of delegations commits
+const WriteChannel = "delegations_written"
+
+// ListenWrites takes a connection out of the pool, listens on WriteChannel and calls onWrite
+// for every notification until ctx is done or the connection fails. onWrite is also called
+// once listening starts, as writes made while nobody was listening were never notified.
+// The connection is closed on return rather than handed back to the pool still listening.
+func ListenWrites(ctx context.Context, pool *pgxpool.Pool, onWrite func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrListenFailed, err)
+	}
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{WriteChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("%w: %w", ErrListenFailed, err)
+	}
+	onWrite()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("%w: %w", ErrListenFailed, err)
+		}
+		onWrite()
+	}
+}
@@ -2,13 +2,34 @@ package pgxstore
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/screwyprof/delegator/web/tezos"
 )
 
 // SQL queries
 const (
-	baseDelegationsQuery = "SELECT id, timestamp, amount, delegator, level FROM delegations"
+	baseDelegationsQuery    = "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations"
+	distinctDelegatorsQuery = "SELECT count(DISTINCT delegator) FROM delegations"
+	amountHistogramQuery    = "WITH filtered AS (SELECT amount FROM delegations"
+	distinctYearsQuery      = "SELECT year, count(*) FROM delegations GROUP BY year ORDER BY year"
+	yearTotalsQuery         = "SELECT year, count(*), COALESCE(sum(amount), 0)::bigint FROM delegations"
+	latestIDQuery           = "SELECT COALESCE(MAX(id), 0) FROM delegations"
+	// Days are cut in UTC, like the year column, whatever the session time zone
+	dailyCountsQuery = "SELECT date_trunc('day', timestamp AT TIME ZONE 'UTC') AS day, count(*) FROM delegations"
+	// monthlyAmountsQuery opens a CTE summing amounts per UTC month that monthlyAmountsTail
+	// closes, adding the running total as a window over the months in order
+	monthlyAmountsQuery = "WITH monthly AS (SELECT date_trunc('month', timestamp AT TIME ZONE 'UTC') AS month, sum(amount) AS amount FROM delegations"
+	monthlyAmountsTail  = " GROUP BY month) SELECT month, amount::bigint, (sum(amount) OVER (ORDER BY month))::bigint FROM monthly ORDER BY month"
+
+	// amountHistogramTail closes the filtered CTE opened by amountHistogramQuery and counts
+	// amounts per width_bucket. The integer width is the smallest one whose %[1]d buckets
+	// cover [MIN, MAX], so every amount lands in buckets 1..n and boundaries are whole mutez.
+	amountHistogramTail = "), bounds AS (" +
+		"SELECT MIN(amount) AS lowest, (MAX(amount) - MIN(amount)) / $%[1]d::int + 1 AS width FROM filtered" +
+		") SELECT bounds.lowest, bounds.width, " +
+		"width_bucket(filtered.amount::numeric, bounds.lowest::numeric, (bounds.lowest + bounds.width * $%[1]d::int)::numeric, $%[1]d::int) AS bucket, " +
+		"count(*) FROM filtered CROSS JOIN bounds GROUP BY bounds.lowest, bounds.width, bucket ORDER BY bucket"
 )
 
 // DelegationsQueryBuilder provides a domain-specific language for building delegation queries
@@ -24,14 +45,152 @@ func NewDelegationsQuery() *DelegationsQueryBuilder {
 	}
 }
 
+// NewDistinctDelegatorsCountQuery creates a query builder counting unique delegators
+func NewDistinctDelegatorsCountQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: distinctDelegatorsQuery,
+	}
+}
+
+// NewDistinctYearsQuery creates a query builder counting delegations per year, ordered by year
+func NewDistinctYearsQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: distinctYearsQuery,
+	}
+}
+
+// NewLatestIDQuery creates a query builder selecting the highest stored delegation ID, or 0
+func NewLatestIDQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: latestIDQuery,
+	}
+}
+
+// NewYearTotalsQuery creates a query builder counting and summing delegations per year;
+// finish it with ForYears
+func NewYearTotalsQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: yearTotalsQuery,
+	}
+}
+
+// ForYears restricts the year totals to years a and b. Years without delegations have no row.
+func (q *DelegationsQueryBuilder) ForYears(a, b tezos.Year) *DelegationsQueryBuilder {
+	placeholder := q.nextPlaceholder()
+	q.sql += fmt.Sprintf(" WHERE year IN ($%d, $%d) GROUP BY year", placeholder, placeholder+1)
+	q.args = append(q.args, a.Uint64(), b.Uint64())
+	return q
+}
+
+// NewDailyCountsQuery creates a query builder counting delegations per UTC day;
+// finish it with ForDailyCounts
+func NewDailyCountsQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: dailyCountsQuery,
+	}
+}
+
+// ForDailyCounts restricts the daily counts to year, ordered by day. Days without delegations have no row.
+func (q *DelegationsQueryBuilder) ForDailyCounts(year tezos.Year) *DelegationsQueryBuilder {
+	q.addWhereCondition("year = $%d", year.Uint64())
+	q.sql += " GROUP BY day ORDER BY day"
+	return q
+}
+
+// NewMonthlyAmountsQuery creates a query builder summing delegated amounts per UTC month;
+// finish it with ForMonthlyAmounts
+func NewMonthlyAmountsQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: monthlyAmountsQuery,
+	}
+}
+
+// ForMonthlyAmounts filters the monthly sums by the criteria. Each row holds the month, its
+// sum and the running total, ordered by month; months without delegations have no row.
+func (q *DelegationsQueryBuilder) ForMonthlyAmounts(criteria tezos.DelegationsCriteria) *DelegationsQueryBuilder {
+	q.ForFilters(criteria)
+	q.sql += monthlyAmountsTail
+	return q
+}
+
+// NewAmountHistogramQuery creates a query builder counting delegations per amount bucket;
+// finish it with ForHistogram
+func NewAmountHistogramQuery() *DelegationsQueryBuilder {
+	return &DelegationsQueryBuilder{
+		sql: amountHistogramQuery,
+	}
+}
+
+// ForHistogram filters the histogram by the criteria and splits the matching amounts
+// into the given number of equal-width buckets. Each row holds the lowest amount, the
+// bucket width, the 1-based bucket number and its count; empty buckets have no row.
+func (q *DelegationsQueryBuilder) ForHistogram(criteria tezos.DelegationsCriteria, buckets int) *DelegationsQueryBuilder {
+	q.ForFilters(criteria)
+
+	placeholder := q.nextPlaceholder()
+	q.sql += fmt.Sprintf(amountHistogramTail, placeholder)
+	q.args = append(q.args, buckets)
+	return q
+}
+
+// ForFilters applies only the filtering part of the criteria (no ordering or pagination)
+func (q *DelegationsQueryBuilder) ForFilters(criteria tezos.DelegationsCriteria) *DelegationsQueryBuilder {
+	return q.
+		filterByYear(criteria.Year).
+		filterByLevelRange(criteria.Levels)
+}
+
 // ForCriteria applies the delegation criteria to the query in one fluent call
 func (q *DelegationsQueryBuilder) ForCriteria(criteria tezos.DelegationsCriteria) *DelegationsQueryBuilder {
 	return q.
 		filterByYear(criteria.Year).
+		filterByLevelRange(criteria.Levels).
 		orderByTimestampDesc().
 		paginateWithDetection(criteria)
 }
 
+// ForPage applies the delegation criteria like ForCriteria, but selects exactly the
+// page's items without the extra "has more" row, for streaming
+func (q *DelegationsQueryBuilder) ForPage(criteria tezos.DelegationsCriteria) *DelegationsQueryBuilder {
+	q.
+		filterByYear(criteria.Year).
+		filterByLevelRange(criteria.Levels).
+		orderByTimestampDesc()
+
+	q.addParameter("LIMIT $%d", criteria.ItemsPerPage())
+	if offset := criteria.ItemsToSkip(); offset > 0 {
+		q.addParameter("OFFSET $%d", offset)
+	}
+	return q
+}
+
+// ForKeyset selects up to limit delegations of the given year (0 = any) with ID greater
+// than afterID, ordered by ID ascending. The highest returned ID is the next cursor.
+func (q *DelegationsQueryBuilder) ForKeyset(year tezos.Year, afterID int64, limit uint64) *DelegationsQueryBuilder {
+	q.filterByYear(year)
+	q.addWhereCondition("id > $%d", afterID)
+	q.sql += " ORDER BY id ASC"
+	q.addParameter("LIMIT $%d", limit)
+	return q
+}
+
+// ForRecent selects the limit most recent delegations, breaking timestamp ties by ID.
+// There is no filter or offset, so the (timestamp DESC, id DESC) index serves it directly.
+func (q *DelegationsQueryBuilder) ForRecent(limit uint64) *DelegationsQueryBuilder {
+	q.sql += " ORDER BY timestamp DESC, id DESC"
+	q.addParameter("LIMIT $%d", limit)
+	return q
+}
+
+// ForTopByAmount selects the limit largest delegations matching the criteria filters,
+// breaking amount ties by ID so equal amounts keep a stable order
+func (q *DelegationsQueryBuilder) ForTopByAmount(criteria tezos.DelegationsCriteria, limit uint64) *DelegationsQueryBuilder {
+	q.ForFilters(criteria)
+	q.sql += " ORDER BY amount DESC, id DESC"
+	q.addParameter("LIMIT $%d", limit)
+	return q
+}
+
 // filterByYear adds year filtering if the year is specified
 func (q *DelegationsQueryBuilder) filterByYear(year tezos.Year) *DelegationsQueryBuilder {
 	if year.Uint64() > 0 {
@@ -40,6 +199,17 @@ func (q *DelegationsQueryBuilder) filterByYear(year tezos.Year) *DelegationsQuer
 	return q
 }
 
+// filterByLevelRange adds inclusive block level bounds for each bound that is set
+func (q *DelegationsQueryBuilder) filterByLevelRange(levels tezos.LevelRange) *DelegationsQueryBuilder {
+	if levels.HasMin() {
+		q.addWhereCondition("level >= $%d", levels.Min)
+	}
+	if levels.HasMax() {
+		q.addWhereCondition("level <= $%d", levels.Max)
+	}
+	return q
+}
+
 // orderByTimestampDesc adds timestamp ordering (most recent first)
 func (q *DelegationsQueryBuilder) orderByTimestampDesc() *DelegationsQueryBuilder {
 	q.sql += " ORDER BY timestamp DESC"
@@ -68,13 +238,15 @@ func (q *DelegationsQueryBuilder) Build() (string, []any) {
 
 // Helper methods for building SQL
 
-// addWhereCondition adds a WHERE condition (assumes only one condition)
-// NOTE: If multiple WHERE conditions are needed in the future, implement AND logic:
-//
-//	if strings.Contains(q.sql, " WHERE ") { q.sql += " AND " } else { q.sql += " WHERE " }
+// addWhereCondition adds a WHERE condition, joining it to an existing one with AND
 func (q *DelegationsQueryBuilder) addWhereCondition(sqlClause string, value any) {
 	placeholder := q.nextPlaceholder()
-	q.sql += " WHERE " + fmt.Sprintf(sqlClause, placeholder)
+	if strings.Contains(q.sql, " WHERE ") {
+		q.sql += " AND "
+	} else {
+		q.sql += " WHERE "
+	}
+	q.sql += fmt.Sprintf(sqlClause, placeholder)
 	q.args = append(q.args, value)
 }
 
@@ -0,0 +1,283 @@
+package pgxstore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/store/pgxstore"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestDelegationsQueryBuilderForKeyset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it pages by ID across all years", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForKeyset(0, 10, 100).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE id > $1 ORDER BY id ASC LIMIT $2", query)
+		assert.Equal(t, []any{int64(10), uint64(100)}, args)
+	})
+
+	t.Run("it combines the year filter and the cursor", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForKeyset(tezos.Year(2025), 10, 100).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE year = $1 AND id > $2 ORDER BY id ASC LIMIT $3", query)
+		assert.Equal(t, []any{uint64(2025), int64(10), uint64(100)}, args)
+	})
+}
+
+func TestDelegationsQueryBuilderForRecent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it orders by timestamp then ID, most recent first, without offset", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForRecent(20).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations ORDER BY timestamp DESC, id DESC LIMIT $1", query)
+		assert.Equal(t, []any{uint64(20)}, args)
+	})
+}
+
+func TestDelegationsQueryBuilderForTopByAmount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it orders by amount then ID, largest first, within the year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(2024, 0, 0)
+		require.NoError(t, err)
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForTopByAmount(criteria, 10).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE year = $1 ORDER BY amount DESC, id DESC LIMIT $2", query)
+		assert.Equal(t, []any{uint64(2024), uint64(10)}, args)
+	})
+
+	t.Run("it omits the year filter when no year is set", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForTopByAmount(tezos.DelegationsCriteria{}, 5).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations ORDER BY amount DESC, id DESC LIMIT $1", query)
+		assert.Equal(t, []any{uint64(5)}, args)
+	})
+}
+
+func TestDelegationsQueryBuilderLevelRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it filters by both level bounds after the year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(2025, 2, 10)
+		require.NoError(t, err)
+		criteria = criteria.WithLevelRange(tezos.LevelRange{Min: 100, Max: 200})
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForCriteria(criteria).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE year = $1 AND level >= $2 AND level <= $3 ORDER BY timestamp DESC LIMIT $4 OFFSET $5", query)
+		assert.Equal(t, []any{uint64(2025), int64(100), int64(200), uint64(11), uint64(10)}, args)
+	})
+
+	t.Run("it adds only the bounds that are set", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(0, 1, 10)
+		require.NoError(t, err)
+		criteria = criteria.WithLevelRange(tezos.LevelRange{Max: 200})
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForCriteria(criteria).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE level <= $1 ORDER BY timestamp DESC LIMIT $2", query)
+		assert.Equal(t, []any{int64(200), uint64(11)}, args)
+	})
+}
+
+func TestDelegationsQueryBuilderForPage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it selects exactly the page without the extra row", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(2025, 2, 10)
+		require.NoError(t, err)
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForPage(criteria).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations WHERE year = $1 ORDER BY timestamp DESC LIMIT $2 OFFSET $3", query)
+		assert.Equal(t, []any{uint64(2025), uint64(10), uint64(10)}, args)
+	})
+
+	t.Run("it omits the offset on the first page", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(0, 1, 10)
+		require.NoError(t, err)
+
+		// Act
+		query, args := pgxstore.NewDelegationsQuery().ForPage(criteria).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT id, timestamp, amount, delegator, level, created_at FROM delegations ORDER BY timestamp DESC LIMIT $1", query)
+		assert.Equal(t, []any{uint64(10)}, args)
+	})
+}
+
+func TestDistinctYearsQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts delegations per year in ascending order", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDistinctYearsQuery().Build()
+
+		// Assert
+		assert.Equal(t, "SELECT year, count(*) FROM delegations GROUP BY year ORDER BY year", query)
+		assert.Empty(t, args)
+	})
+}
+
+func TestDailyCountsQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts delegations of the year per UTC day in ascending order", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewDailyCountsQuery().ForDailyCounts(2024).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT date_trunc('day', timestamp AT TIME ZONE 'UTC') AS day, count(*) FROM delegations WHERE year = $1 GROUP BY day ORDER BY day", query)
+		assert.Equal(t, []any{uint64(2024)}, args)
+	})
+}
+
+func TestMonthlyAmountsQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it sums amounts per UTC month with a running total", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewMonthlyAmountsQuery().ForMonthlyAmounts(tezos.DelegationsCriteria{}).Build()
+
+		// Assert
+		assert.Equal(t, "WITH monthly AS (SELECT date_trunc('month', timestamp AT TIME ZONE 'UTC') AS month, sum(amount) AS amount FROM delegations"+
+			" GROUP BY month) SELECT month, amount::bigint, (sum(amount) OVER (ORDER BY month))::bigint FROM monthly ORDER BY month", query)
+		assert.Empty(t, args)
+	})
+
+	t.Run("it filters the summed delegations by year and levels", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria := tezos.DelegationsCriteria{Year: 2024, Levels: tezos.LevelRange{Min: 100, Max: 200}}
+
+		// Act
+		query, args := pgxstore.NewMonthlyAmountsQuery().ForMonthlyAmounts(criteria).Build()
+
+		// Assert
+		assert.Contains(t, query, "FROM delegations WHERE year = $1 AND level >= $2 AND level <= $3 GROUP BY month)")
+		assert.Equal(t, []any{uint64(2024), int64(100), int64(200)}, args)
+	})
+}
+
+func TestLatestIDQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it selects the highest ID, falling back to 0", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewLatestIDQuery().Build()
+
+		// Assert
+		assert.Equal(t, "SELECT COALESCE(MAX(id), 0) FROM delegations", query)
+		assert.Empty(t, args)
+	})
+}
+
+func TestYearTotalsQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts and sums delegations of both years", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		query, args := pgxstore.NewYearTotalsQuery().ForYears(2023, 2024).Build()
+
+		// Assert
+		assert.Equal(t, "SELECT year, count(*), COALESCE(sum(amount), 0)::bigint FROM delegations WHERE year IN ($1, $2) GROUP BY year", query)
+		assert.Equal(t, []any{uint64(2023), uint64(2024)}, args)
+	})
+}
+
+func TestAmountHistogramQueryBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it buckets all amounts without filters", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(0, 1, 0)
+		require.NoError(t, err)
+
+		// Act
+		query, args := pgxstore.NewAmountHistogramQuery().ForHistogram(criteria, 10).Build()
+
+		// Assert
+		assert.Equal(t, "WITH filtered AS (SELECT amount FROM delegations), bounds AS ("+
+			"SELECT MIN(amount) AS lowest, (MAX(amount) - MIN(amount)) / $1::int + 1 AS width FROM filtered"+
+			") SELECT bounds.lowest, bounds.width, "+
+			"width_bucket(filtered.amount::numeric, bounds.lowest::numeric, (bounds.lowest + bounds.width * $1::int)::numeric, $1::int) AS bucket, "+
+			"count(*) FROM filtered CROSS JOIN bounds GROUP BY bounds.lowest, bounds.width, bucket ORDER BY bucket", query)
+		assert.Equal(t, []any{10}, args)
+	})
+
+	t.Run("it filters inside the CTE and numbers the bucket count after the filters", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		criteria, err := tezos.NewDelegationsCriteria(2025, 3, 10)
+		require.NoError(t, err)
+		criteria = criteria.WithLevelRange(tezos.LevelRange{Min: 100})
+
+		// Act
+		query, args := pgxstore.NewAmountHistogramQuery().ForHistogram(criteria, 5).Build()
+
+		// Assert
+		assert.Contains(t, query, "WITH filtered AS (SELECT amount FROM delegations WHERE year = $1 AND level >= $2), bounds AS (")
+		assert.Contains(t, query, "/ $3::int + 1 AS width")
+		assert.NotContains(t, query, "LIMIT", "Pagination should be ignored")
+		assert.Equal(t, []any{uint64(2025), int64(100), 5}, args)
+	})
+}
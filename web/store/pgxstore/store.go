@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	pgxc "github.com/zolstein/pgx-collect"
 
@@ -14,7 +16,8 @@ import (
 
 // Sentinel errors for store operations
 var (
-	ErrQueryFailed = errors.New("delegation query failed")
+	ErrQueryFailed  = errors.New("delegation query failed")
+	ErrListenFailed = errors.New("listening for writes failed")
 )
 
 // DelegationsFinder implements delegation querying using pgx
@@ -39,30 +42,13 @@ func (f *DelegationsFinder) FindDelegations(ctx context.Context, criteria tezos.
 		ForCriteria(criteria).
 		Build()
 
-	rows, err := f.pool.Query(ctx, query, args...)
+	dbDelegations, err := f.queryDelegations(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
-	}
-	defer rows.Close()
-
-	// Use pgx-collect for efficient row collection
-	dbDelegations, err := pgxc.CollectRows(rows, pgxc.RowToStructByName[dbrow.Delegation])
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		return nil, err
 	}
 
 	// Convert database rows to domain models
-	delegations := make([]tezos.Delegation, 0, len(dbDelegations))
-	for _, dbRow := range dbDelegations {
-		delegation := tezos.Delegation{
-			ID:        dbRow.ID,
-			Timestamp: dbRow.Timestamp,
-			Amount:    dbRow.Amount,
-			Delegator: dbRow.Delegator,
-			Level:     dbRow.Level,
-		}
-		delegations = append(delegations, delegation)
-	}
+	delegations := toDomainDelegations(dbDelegations)
 
 	// Determine if there are more pages using LIMIT n+1 technique
 	hasMore := len(delegations) > int(criteria.ItemsPerPage())
@@ -78,3 +64,316 @@ func (f *DelegationsFinder) FindDelegations(ctx context.Context, criteria tezos.
 		Size:        criteria.Size,
 	}, nil
 }
+
+// FindDelegationsStream streams the criteria's page of delegations row by row, calling
+// yield for each without collecting them, so memory use does not grow with the page size
+func (f *DelegationsFinder) FindDelegationsStream(ctx context.Context, criteria tezos.DelegationsCriteria, yield func(tezos.Delegation) error) error {
+	query, args := NewDelegationsQuery().
+		ForPage(criteria).
+		Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dbRow, err := pgx.RowToStructByName[dbrow.Delegation](rows)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		if err := yield(toDomainDelegation(dbRow)); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return nil
+}
+
+// FindSinceID returns up to limit delegations with ID greater than id, ordered by ID ascending
+// Used by clients for incremental sync: the highest returned ID becomes the next cursor
+func (f *DelegationsFinder) FindSinceID(ctx context.Context, id int64, limit uint64) ([]tezos.Delegation, error) {
+	return f.FindSinceIDInYear(ctx, 0, id, limit)
+}
+
+// FindSinceIDInYear is FindSinceID restricted to a year (0 = any year).
+// Used by exports to stream a year's delegations with keyset pagination.
+func (f *DelegationsFinder) FindSinceIDInYear(ctx context.Context, year tezos.Year, id int64, limit uint64) ([]tezos.Delegation, error) {
+	query, args := NewDelegationsQuery().
+		ForKeyset(year, id, limit).
+		Build()
+
+	dbDelegations, err := f.queryDelegations(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainDelegations(dbDelegations), nil
+}
+
+// FindRecent returns up to limit most recent delegations ordered by timestamp, then ID, descending
+func (f *DelegationsFinder) FindRecent(ctx context.Context, limit uint64) ([]tezos.Delegation, error) {
+	query, args := NewDelegationsQuery().
+		ForRecent(limit).
+		Build()
+
+	dbDelegations, err := f.queryDelegations(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainDelegations(dbDelegations), nil
+}
+
+// LatestID returns the highest stored delegation ID, or 0 when there are none
+func (f *DelegationsFinder) LatestID(ctx context.Context) (int64, error) {
+	query, args := NewLatestIDQuery().Build()
+
+	var id int64
+	if err := f.pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return id, nil
+}
+
+// FindTopByAmount returns up to limit delegations matching the criteria filters ordered by
+// amount, then ID, descending
+func (f *DelegationsFinder) FindTopByAmount(ctx context.Context, criteria tezos.DelegationsCriteria, limit uint64) ([]tezos.Delegation, error) {
+	query, args := NewDelegationsQuery().
+		ForTopByAmount(criteria, limit).
+		Build()
+
+	dbDelegations, err := f.queryDelegations(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainDelegations(dbDelegations), nil
+}
+
+// CountDistinctDelegators counts unique delegators matching the criteria filters
+func (f *DelegationsFinder) CountDistinctDelegators(ctx context.Context, criteria tezos.DelegationsCriteria) (int64, error) {
+	query, args := NewDistinctDelegatorsCountQuery().
+		ForFilters(criteria).
+		Build()
+
+	var count int64
+	if err := f.pool.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return count, nil
+}
+
+// AmountHistogram counts delegations matching the criteria filters per equal-width amount
+// bucket spanning the smallest to the largest matching amount. It returns no buckets
+// when nothing matches; pagination is ignored.
+func (f *DelegationsFinder) AmountHistogram(ctx context.Context, criteria tezos.DelegationsCriteria, buckets int) ([]tezos.Bucket, error) {
+	query, args := NewAmountHistogramQuery().
+		ForHistogram(criteria, buckets).
+		Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	var lowest, width int64
+	counts := make(map[int]int64)
+	for rows.Next() {
+		var (
+			bucket int
+			count  int64
+		)
+		if err := rows.Scan(&lowest, &width, &bucket, &count); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		counts[bucket] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	if len(counts) == 0 {
+		return []tezos.Bucket{}, nil
+	}
+	return tezos.NewHistogram(tezos.Amount(lowest), tezos.Amount(width), buckets, counts), nil
+}
+
+// DistinctYears returns every year with at least one delegation and its count, ordered by year ascending
+func (f *DelegationsFinder) DistinctYears(ctx context.Context) ([]tezos.YearCount, error) {
+	query, args := NewDistinctYearsQuery().Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	years := []tezos.YearCount{}
+	for rows.Next() {
+		var (
+			year  int64
+			count int64
+		)
+		if err := rows.Scan(&year, &count); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		years = append(years, tezos.YearCount{Year: tezos.Year(year), Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return years, nil
+}
+
+// CompareYears returns the delegation count and total amount of years a and b in one query
+func (f *DelegationsFinder) CompareYears(ctx context.Context, a, b tezos.Year) (tezos.YearComparison, error) {
+	query, args := NewYearTotalsQuery().
+		ForYears(a, b).
+		Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return tezos.YearComparison{}, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	comparison := tezos.YearComparison{
+		A: tezos.YearTotals{Year: a},
+		B: tezos.YearTotals{Year: b},
+	}
+	for rows.Next() {
+		var (
+			year   int64
+			count  int64
+			amount int64
+		)
+		if err := rows.Scan(&year, &count, &amount); err != nil {
+			return tezos.YearComparison{}, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		totals := tezos.YearTotals{Year: tezos.Year(year), Count: count, Amount: tezos.Amount(amount)}
+		if totals.Year == a {
+			comparison.A = totals
+		}
+		if totals.Year == b {
+			comparison.B = totals
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return tezos.YearComparison{}, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return comparison, nil
+}
+
+// DailyCounts returns the number of delegations per UTC day of year, ordered by day ascending
+func (f *DelegationsFinder) DailyCounts(ctx context.Context, year tezos.Year) ([]tezos.DayCount, error) {
+	query, args := NewDailyCountsQuery().
+		ForDailyCounts(year).
+		Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	days := []tezos.DayCount{}
+	for rows.Next() {
+		var (
+			day   time.Time
+			count int64
+		)
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		days = append(days, tezos.DayCount{Day: day.UTC(), Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return days, nil
+}
+
+// CumulativeAmountByMonth sums the amounts of delegations matching the criteria filters per
+// UTC month, with a running total, ordered by month ascending; pagination is ignored
+func (f *DelegationsFinder) CumulativeAmountByMonth(ctx context.Context, criteria tezos.DelegationsCriteria) ([]tezos.MonthlyAmount, error) {
+	query, args := NewMonthlyAmountsQuery().
+		ForMonthlyAmounts(criteria).
+		Build()
+
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	months := []tezos.MonthlyAmount{}
+	for rows.Next() {
+		var (
+			month      time.Time
+			amount     int64
+			cumulative int64
+		)
+		if err := rows.Scan(&month, &amount, &cumulative); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+		}
+		months = append(months, tezos.MonthlyAmount{
+			Month:      month.UTC(),
+			Amount:     tezos.Amount(amount),
+			Cumulative: tezos.Amount(cumulative),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return months, nil
+}
+
+// queryDelegations runs a delegation query and collects the rows
+func (f *DelegationsFinder) queryDelegations(ctx context.Context, query string, args ...any) ([]dbrow.Delegation, error) {
+	rows, err := f.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	// Use pgx-collect for efficient row collection
+	dbDelegations, err := pgxc.CollectRows(rows, pgxc.RowToStructByName[dbrow.Delegation])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+
+	return dbDelegations, nil
+}
+
+// toDomainDelegations converts database rows to domain models
+func toDomainDelegations(dbDelegations []dbrow.Delegation) []tezos.Delegation {
+	delegations := make([]tezos.Delegation, 0, len(dbDelegations))
+	for _, dbRow := range dbDelegations {
+		delegations = append(delegations, toDomainDelegation(dbRow))
+	}
+	return delegations
+}
+
+// toDomainDelegation converts a database row to the domain model
+func toDomainDelegation(dbRow dbrow.Delegation) tezos.Delegation {
+	return tezos.Delegation{
+		ID:        dbRow.ID,
+		Timestamp: dbRow.Timestamp,
+		Amount:    tezos.Amount(dbRow.Amount),
+		Delegator: dbRow.Delegator,
+		Level:     dbRow.Level,
+		CreatedAt: dbRow.CreatedAt,
+	}
+}
@@ -0,0 +1,162 @@
+//go:build acceptance
+
+package pgxstore_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/migrator/migratortest"
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/pkg/pgxdb"
+	"github.com/screwyprof/delegator/web/store/pgxstore"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const (
+	migrationsDir = "../../../migrator/migrations"
+	// Enough rows across several years for the planner to prefer an index over a seq scan
+	seededDelegations = 50000
+)
+
+// TestDelegationsQueryPlan verifies the listing query is served by an index
+func TestDelegationsQueryPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it uses an index for a year-filtered listing", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+		seedDelegationsAcrossYears(t, db, seededDelegations)
+
+		criteria, err := tezos.NewDelegationsCriteria(2021, 1, 50)
+		require.NoError(t, err)
+		query, args := pgxstore.NewDelegationsQuery().ForCriteria(criteria).Build()
+
+		// Act
+		plan := explain(t, db, query, args...)
+
+		// Assert
+		assert.NotContains(t, plan, "Seq Scan on delegations", "Year-filtered listing should not scan the whole table")
+		assert.Contains(t, plan, "Index", "Year-filtered listing should use an index:\n%s", plan)
+	})
+}
+
+// TestQueryTracerAcceptance verifies the tracer installed on the pool logs finder queries
+func TestQueryTracerAcceptance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it logs finder queries with the request id", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+
+		var logBuffer bytes.Buffer
+		tracer := pgxstore.NewQueryTracer(newDebugLogger(&logBuffer))
+		pool, err := pgxdb.NewConnection(t.Context(), db.Config().ConnString(), pgxdb.WithTracer(tracer))
+		require.NoError(t, err)
+
+		finder, closer := pgxstore.New(pool)
+		defer closer()
+
+		ctx := httpkit.WithRequestID(t.Context(), "req-789")
+
+		// Act
+		_, err = finder.LatestID(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		entry := parseTracedQuery(t, &logBuffer)
+		assert.Equal(t, "req-789", entry.RequestID)
+		assert.Contains(t, entry.SQL, "delegations")
+		assert.Empty(t, entry.Error)
+	})
+}
+
+// TestListenWrites verifies write notifications reach the callback
+func TestListenWrites(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it calls back once listening and for every notification until cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := migratortest.CreateScraperTestDatabase(t, migrationsDir, 0)
+		defer db.Close()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		writes := make(chan struct{}, 1)
+		done := make(chan error, 1)
+		go func() {
+			done <- pgxstore.ListenWrites(ctx, db, func() { writes <- struct{}{} })
+		}()
+		awaitWrite(t, writes) // listening started
+
+		// Act
+		_, err := db.Exec(t.Context(), "SELECT pg_notify($1, '')", pgxstore.WriteChannel)
+
+		// Assert
+		require.NoError(t, err)
+		awaitWrite(t, writes)
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+}
+
+// awaitWrite fails the test unless a write callback arrives within a few seconds
+func awaitWrite(t *testing.T, writes <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-writes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Should have been notified of the write")
+	}
+}
+
+// seedDelegationsAcrossYears inserts n hourly delegations starting mid-2018 and refreshes statistics
+func seedDelegationsAcrossYears(t *testing.T, db *pgxpool.Pool, n int) {
+	t.Helper()
+
+	_, err := db.Exec(t.Context(), `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		SELECT g, ts, 1000000 + g, 'tz1' || lpad((g % 5000)::text, 5, '0'), g, EXTRACT(YEAR FROM ts AT TIME ZONE 'UTC')::int
+		FROM generate_series(1, $1::int) AS g
+		CROSS JOIN LATERAL (SELECT TIMESTAMPTZ '2018-07-01 00:00:00+00' + g * INTERVAL '1 hour' AS ts) AS t
+	`, n)
+	require.NoError(t, err)
+
+	_, err = db.Exec(t.Context(), "ANALYZE delegations")
+	require.NoError(t, err)
+}
+
+// explain returns the textual query plan for query
+func explain(t *testing.T, db *pgxpool.Pool, query string, args ...any) string {
+	t.Helper()
+
+	rows, err := db.Query(t.Context(), "EXPLAIN "+query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, rows.Err())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,57 @@
+package pgxstore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/screwyprof/delegator/pkg/ctxkit"
+	"github.com/screwyprof/delegator/pkg/httpkit"
+)
+
+// QueryTracer implements pgx.QueryTracer and logs SQL timing with the request id
+// taken from the query context, tying HTTP and DB logs together
+type QueryTracer struct {
+	logger *slog.Logger
+}
+
+// NewQueryTracer creates a query tracer that logs to the given logger
+func NewQueryTracer(logger *slog.Logger) *QueryTracer {
+	return &QueryTracer{logger: logger}
+}
+
+var queryTraceKey = ctxkit.NewKey[queryTrace]("query-trace")
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+}
+
+// TraceQueryStart records the query and its start time in the context
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return queryTraceKey.With(ctx, queryTrace{
+		sql:   data.SQL,
+		start: time.Now(),
+	})
+}
+
+// TraceQueryEnd logs the query duration and outcome along with the request id
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, _ := queryTraceKey.Value(ctx)
+
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("request_id", httpkit.RequestID(ctx)),
+		slog.String("sql", trace.sql),
+		slog.Duration("duration", time.Since(trace.start)),
+	}
+
+	if data.Err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+	}
+
+	t.logger.LogAttrs(ctx, level, "SQL", attrs...)
+}
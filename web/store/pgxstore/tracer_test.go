@@ -0,0 +1,85 @@
+package pgxstore_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/pkg/httpkit"
+	"github.com/screwyprof/delegator/web/store/pgxstore"
+)
+
+// tracedQuery represents a parsed SQL log entry for testing
+type tracedQuery struct {
+	Level     string  `json:"level"`
+	Msg       string  `json:"msg"`
+	RequestID string  `json:"request_id"`
+	SQL       string  `json:"sql"`
+	Duration  float64 `json:"duration"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func TestQueryTracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it logs the request id from the query context", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		tracer := pgxstore.NewQueryTracer(newDebugLogger(&logBuffer))
+		ctx := httpkit.WithRequestID(t.Context(), "req-123")
+
+		// Act
+		ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+		// Assert
+		entry := parseTracedQuery(t, &logBuffer)
+		assert.Equal(t, "DEBUG", entry.Level)
+		assert.Equal(t, "SQL", entry.Msg)
+		assert.Equal(t, "req-123", entry.RequestID)
+		assert.Equal(t, "SELECT 1", entry.SQL)
+		assert.GreaterOrEqual(t, entry.Duration, 0.0)
+		assert.Empty(t, entry.Error)
+	})
+
+	t.Run("it logs failed queries at error level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		var logBuffer bytes.Buffer
+		tracer := pgxstore.NewQueryTracer(newDebugLogger(&logBuffer))
+		ctx := httpkit.WithRequestID(t.Context(), "req-456")
+
+		// Act
+		ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+
+		// Assert
+		entry := parseTracedQuery(t, &logBuffer)
+		assert.Equal(t, "ERROR", entry.Level)
+		assert.Equal(t, "req-456", entry.RequestID)
+		assert.Equal(t, "connection reset", entry.Error)
+	})
+}
+
+func newDebugLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func parseTracedQuery(t *testing.T, buf *bytes.Buffer) tracedQuery {
+	t.Helper()
+
+	var entry tracedQuery
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	require.NoError(t, err, "Should parse log entry as JSON")
+
+	return entry
+}
@@ -0,0 +1,144 @@
+// Package swrcache serves the first page of delegation listings from a
+// stale-while-revalidate cache in front of a DelegationsFinder
+package swrcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/delegator/pkg/clock"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+// Clock provides the current time to the cache
+type Clock interface {
+	Now() time.Time
+}
+
+// Option configures the caching Finder
+type Option func(*Finder)
+
+// WithClock injects a custom Clock (e.g., for testing)
+func WithClock(c Clock) Option {
+	return func(f *Finder) { f.clock = c }
+}
+
+// Finder decorates a DelegationsFinder, caching FindDelegations results for page 1 per
+// filter and page size. A cached page younger than freshness is served as is. Within
+// the following staleness window it is still served right away, while a single
+// background query per key refreshes it. Older pages, and every other page number,
+// are queried synchronously. Cached pages are shared, so callers must treat them as
+// read-only. Errors are not cached; a failed refresh keeps the stale page.
+// Other queries pass straight through.
+type Finder struct {
+	tezos.DelegationsFinder
+
+	freshness time.Duration
+	staleness time.Duration
+	clock     Clock
+
+	mu      sync.Mutex
+	entries map[pageKey]*entry
+	pending sync.WaitGroup
+}
+
+// pageKey is the filter and page size portion of page-1 criteria
+type pageKey struct {
+	year   tezos.Year
+	levels tezos.LevelRange
+	size   tezos.PerPage
+}
+
+type entry struct {
+	page       *tezos.DelegationsPage
+	storedAt   time.Time
+	refreshing bool
+}
+
+// New wraps finder with a page-1 cache whose entries are fresh for freshness and may
+// be served stale, while refreshing, for a further staleness
+func New(finder tezos.DelegationsFinder, freshness, staleness time.Duration, opts ...Option) *Finder {
+	f := &Finder{
+		DelegationsFinder: finder,
+		freshness:         freshness,
+		staleness:         staleness,
+		clock:             clock.SystemClock{},
+		entries:           make(map[pageKey]*entry),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// FindDelegations returns the cached first page while it is fresh or stale, refreshing
+// a stale one in the background; otherwise it queries the wrapped finder
+func (f *Finder) FindDelegations(ctx context.Context, criteria tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+	if criteria.Page.Uint64() != tezos.DefaultPage {
+		return f.DelegationsFinder.FindDelegations(ctx, criteria)
+	}
+	key := pageKey{year: criteria.Year, levels: criteria.Levels, size: criteria.Size}
+
+	f.mu.Lock()
+	cached, ok := f.entries[key]
+	if ok {
+		age := f.clock.Now().Sub(cached.storedAt)
+		switch {
+		case age < f.freshness:
+			f.mu.Unlock()
+			return cached.page, nil
+		case age < f.freshness+f.staleness:
+			if !cached.refreshing {
+				cached.refreshing = true
+				f.pending.Add(1)
+				go f.refresh(context.WithoutCancel(ctx), key, criteria)
+			}
+			f.mu.Unlock()
+			return cached.page, nil
+		}
+	}
+	f.mu.Unlock()
+
+	page, err := f.DelegationsFinder.FindDelegations(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+	f.store(key, page)
+	return page, nil
+}
+
+// FindDelegationsStream streams through the wrapped finder when it supports streaming,
+// so wrapping does not hide tezos.DelegationsStreamer. Otherwise it yields the
+// delegations of the (possibly cached) FindDelegations page.
+func (f *Finder) FindDelegationsStream(ctx context.Context, criteria tezos.DelegationsCriteria, yield func(tezos.Delegation) error) error {
+	return tezos.StreamPage(ctx, f.DelegationsFinder, f.FindDelegations, criteria, yield)
+}
+
+// Wait blocks until every background refresh has finished
+func (f *Finder) Wait() {
+	f.pending.Wait()
+}
+
+// refresh re-queries a stale page and replaces the cached one on success
+func (f *Finder) refresh(ctx context.Context, key pageKey, criteria tezos.DelegationsCriteria) {
+	defer f.pending.Done()
+
+	page, err := f.DelegationsFinder.FindDelegations(ctx, criteria)
+	if err != nil {
+		f.mu.Lock()
+		if cached, ok := f.entries[key]; ok {
+			cached.refreshing = false
+		}
+		f.mu.Unlock()
+		return
+	}
+	f.store(key, page)
+}
+
+func (f *Finder) store(key pageKey, page *tezos.DelegationsPage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.entries[key] = &entry{page: page, storedAt: f.clock.Now()}
+}
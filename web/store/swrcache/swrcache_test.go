@@ -0,0 +1,281 @@
+package swrcache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/store/swrcache"
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+const (
+	freshness = time.Minute
+	staleness = 5 * time.Minute
+)
+
+func TestFinderFindDelegations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it serves a fresh page from the cache", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		clock := &stoppedClock{}
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(clock))
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Act
+		clock.advance(freshness - time.Second)
+		store.version.Store(2)
+		page := findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(1), versionOf(page))
+		assert.Equal(t, int32(1), store.calls.Load())
+	})
+
+	t.Run("it serves a stale page while refreshing it, then the refreshed one", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		clock := &stoppedClock{}
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(clock))
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		clock.advance(freshness)
+		store.version.Store(2)
+		release := store.block()
+
+		// Act
+		duringRefresh := findPage(t, finder, criteriaFor(t, 2025, 1))
+		store.waitForCalls(t, 2)
+		stillRefreshing := findPage(t, finder, criteriaFor(t, 2025, 1))
+		release()
+		finder.Wait()
+		afterRefresh := findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(1), versionOf(duringRefresh), "The stale page should be served without waiting")
+		assert.Equal(t, int64(1), versionOf(stillRefreshing))
+		assert.Equal(t, int64(2), versionOf(afterRefresh), "The refreshed page should replace the stale one")
+		assert.Equal(t, int32(2), store.calls.Load(), "Only one refresh should run per stale page")
+	})
+
+	t.Run("it queries synchronously once the page is past the staleness window", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		clock := &stoppedClock{}
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(clock))
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Act
+		clock.advance(freshness + staleness)
+		store.version.Store(2)
+		page := findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(2), versionOf(page))
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+
+	t.Run("it keeps the stale page when the refresh fails", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		clock := &stoppedClock{}
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(clock))
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		clock.advance(freshness)
+		store.fail(errors.New("connection refused"))
+
+		// Act
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+		finder.Wait()
+		store.fail(nil)
+		store.version.Store(2)
+		stale := findPage(t, finder, criteriaFor(t, 2025, 1))
+		finder.Wait()
+		refreshed := findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int64(1), versionOf(stale), "A failed refresh should keep the stale page")
+		assert.Equal(t, int64(2), versionOf(refreshed), "A later stale read should retry the refresh")
+		assert.Equal(t, int32(3), store.calls.Load())
+	})
+
+	t.Run("it keys pages by filters and page size", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(&stoppedClock{}))
+		smallPage, err := tezos.NewDelegationsCriteria(2025, 1, 10)
+		require.NoError(t, err)
+
+		// Act
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+		findPage(t, finder, criteriaFor(t, 2024, 1))
+		findPage(t, finder, criteriaFor(t, 2025, 1).WithLevelRange(tezos.LevelRange{Min: 100}))
+		findPage(t, finder, smallPage)
+		findPage(t, finder, criteriaFor(t, 2025, 1))
+
+		// Assert
+		assert.Equal(t, int32(4), store.calls.Load(), "Each distinct query should be run once")
+	})
+
+	t.Run("it does not cache pages beyond the first", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(&stoppedClock{}))
+
+		// Act
+		findPage(t, finder, criteriaFor(t, 2025, 2))
+		findPage(t, finder, criteriaFor(t, 2025, 2))
+
+		// Assert
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+
+	t.Run("it does not cache errors", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		store := newVersionedFinder(1)
+		store.fail(errors.New("connection refused"))
+		finder := swrcache.New(store, freshness, staleness, swrcache.WithClock(&stoppedClock{}))
+
+		// Act
+		_, firstErr := finder.FindDelegations(t.Context(), criteriaFor(t, 2025, 1))
+		_, secondErr := finder.FindDelegations(t.Context(), criteriaFor(t, 2025, 1))
+
+		// Assert
+		require.Error(t, firstErr)
+		require.Error(t, secondErr)
+		assert.Equal(t, int32(2), store.calls.Load())
+	})
+}
+
+// versionedFinder answers FindDelegations with a page holding one delegation whose ID
+// is the current version. Queries can be made to fail or to wait until released.
+type versionedFinder struct {
+	tezos.DelegationsFinder
+
+	version atomic.Int64
+	calls   atomic.Int32
+
+	mu   sync.Mutex
+	err  error
+	gate chan struct{}
+}
+
+func newVersionedFinder(version int64) *versionedFinder {
+	f := &versionedFinder{}
+	f.version.Store(version)
+	return f
+}
+
+func (f *versionedFinder) FindDelegations(ctx context.Context, criteria tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+	f.calls.Add(1)
+
+	f.mu.Lock()
+	err, gate := f.err, f.gate
+	f.mu.Unlock()
+
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tezos.DelegationsPage{
+		Delegations: []tezos.Delegation{{ID: f.version.Load()}},
+		Number:      criteria.Page,
+		Size:        criteria.Size,
+	}, nil
+}
+
+// fail makes later queries return err; nil makes them succeed again
+func (f *versionedFinder) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// block holds later queries until the returned release is called
+func (f *versionedFinder) block() (release func()) {
+	gate := make(chan struct{})
+	f.mu.Lock()
+	f.gate = gate
+	f.mu.Unlock()
+
+	return func() {
+		f.mu.Lock()
+		f.gate = nil
+		f.mu.Unlock()
+		close(gate)
+	}
+}
+
+func (f *versionedFinder) waitForCalls(t *testing.T, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return f.calls.Load() >= int32(n) }, time.Second, time.Millisecond,
+		"the background refresh should have started")
+}
+
+// stoppedClock only moves when advanced
+type stoppedClock struct {
+	mu  sync.Mutex
+	now time.Duration
+}
+
+func (c *stoppedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Unix(0, 0).Add(c.now)
+}
+
+func (c *stoppedClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now += d
+}
+
+func criteriaFor(t *testing.T, year, page uint64) tezos.DelegationsCriteria {
+	t.Helper()
+
+	criteria, err := tezos.NewDelegationsCriteria(year, page, 0)
+	require.NoError(t, err)
+	return criteria
+}
+
+func findPage(t *testing.T, finder *swrcache.Finder, criteria tezos.DelegationsCriteria) *tezos.DelegationsPage {
+	t.Helper()
+
+	page, err := finder.FindDelegations(t.Context(), criteria)
+	require.NoError(t, err)
+	return page
+}
+
+func versionOf(page *tezos.DelegationsPage) int64 {
+	return page.Delegations[0].ID
+}
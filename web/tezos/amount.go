@@ -0,0 +1,44 @@
+package tezos
+
+import (
+	"fmt"
+	"time"
+)
+
+// MutezPerTez is the number of mutez in one tez
+const MutezPerTez = 1_000_000
+
+// Amount represents a delegated balance in mutez (1 tez = 1 000 000 mutez)
+type Amount int64
+
+// Mutez returns the raw amount in mutez
+func (a Amount) Mutez() int64 {
+	return int64(a)
+}
+
+// Tez returns the amount in tez. Precision is lost above 2^53 mutez;
+// use String for exact rendering.
+func (a Amount) Tez() float64 {
+	return float64(a) / MutezPerTez
+}
+
+// String renders the amount in tez with exactly 6 decimals, e.g. "25079.312620".
+// Integer arithmetic keeps it exact for every int64 value.
+func (a Amount) String() string {
+	sign := ""
+	magnitude := uint64(a)
+	if a < 0 {
+		sign = "-"
+		// Negate in unsigned space so math.MinInt64 does not overflow
+		magnitude = -uint64(a)
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, magnitude/MutezPerTez, magnitude%MutezPerTez)
+}
+
+// MonthlyAmount is the amount delegated during a UTC calendar month and the running
+// total up to and including it
+type MonthlyAmount struct {
+	Month      time.Time // Midnight UTC on the first day of the month
+	Amount     Amount
+	Cumulative Amount
+}
@@ -0,0 +1,64 @@
+package tezos_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestAmount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders tez with six decimals", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			amount   tezos.Amount
+			expected string
+		}{
+			{name: "zero", amount: 0, expected: "0.000000"},
+			{name: "one mutez", amount: 1, expected: "0.000001"},
+			{name: "just below one tez", amount: 999_999, expected: "0.999999"},
+			{name: "one tez", amount: tezos.MutezPerTez, expected: "1.000000"},
+			{name: "fractional tez", amount: 25_079_312_620, expected: "25079.312620"},
+			{name: "negative amount", amount: -1_500_000, expected: "-1.500000"},
+			{name: "largest amount", amount: math.MaxInt64, expected: "9223372036854.775807"},
+			{name: "smallest amount", amount: math.MinInt64, expected: "-9223372036854.775808"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				// Act & Assert
+				assert.Equal(t, tc.expected, tc.amount.String())
+			})
+		}
+	})
+
+	t.Run("it converts to tez", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		amount := tezos.Amount(25_079_312_620)
+
+		// Act & Assert
+		assert.Equal(t, int64(25_079_312_620), amount.Mutez())
+		assert.InDelta(t, 25079.31262, amount.Tez(), 1e-9)
+	})
+
+	t.Run("it keeps mutez precision in tez up to 2^53", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		amount := tezos.Amount(1 << 53)
+
+		// Act & Assert
+		assert.Equal(t, float64(1<<53)/tezos.MutezPerTez, amount.Tez())
+		assert.Equal(t, "9007199254.740992", amount.String())
+	})
+}
@@ -16,22 +16,101 @@ var (
 // DelegationsFinder defines the interface for querying delegations
 type DelegationsFinder interface {
 	FindDelegations(ctx context.Context, criteria DelegationsCriteria) (*DelegationsPage, error)
+	// FindSinceID returns up to limit delegations with ID greater than id, ordered by ID ascending
+	FindSinceID(ctx context.Context, id int64, limit uint64) ([]Delegation, error)
+	// CountDistinctDelegators counts unique delegators matching the criteria filters; pagination is ignored
+	CountDistinctDelegators(ctx context.Context, criteria DelegationsCriteria) (int64, error)
+	// FindRecent returns up to limit most recent delegations ordered by timestamp, then ID, descending
+	FindRecent(ctx context.Context, limit uint64) ([]Delegation, error)
+	// FindTopByAmount returns up to limit delegations matching the criteria filters ordered by amount,
+	// then ID, descending; pagination is ignored
+	FindTopByAmount(ctx context.Context, criteria DelegationsCriteria, limit uint64) ([]Delegation, error)
+	// AmountHistogram counts delegations matching the criteria filters per equal-width amount bucket
+	AmountHistogram(ctx context.Context, criteria DelegationsCriteria, buckets int) ([]Bucket, error)
+	// DistinctYears returns every year with at least one delegation and its count, ordered by year ascending
+	DistinctYears(ctx context.Context) ([]YearCount, error)
+	// CompareYears returns the delegation totals of years a and b; a year without delegations has zero totals
+	CompareYears(ctx context.Context, a, b Year) (YearComparison, error)
+	// DailyCounts returns the number of delegations per UTC day of year, ordered by day ascending;
+	// days without delegations are omitted
+	DailyCounts(ctx context.Context, year Year) ([]DayCount, error)
+	// CumulativeAmountByMonth sums the amounts of delegations matching the criteria filters per UTC
+	// month, with a running total, ordered by month ascending; months without delegations are
+	// omitted and pagination is ignored
+	CumulativeAmountByMonth(ctx context.Context, criteria DelegationsCriteria) ([]MonthlyAmount, error)
+}
+
+// DelegationsStreamer is implemented by finders that can stream a page of delegations
+// row by row instead of materialising it
+type DelegationsStreamer interface {
+	// FindDelegationsStream calls yield for each delegation of the criteria's page, in
+	// FindDelegations order, stopping at and returning the first error yield returns
+	FindDelegationsStream(ctx context.Context, criteria DelegationsCriteria, yield func(Delegation) error) error
+}
+
+// StreamPage lets a finder decorator implement DelegationsStreamer. It streams through the
+// wrapped finder when that supports streaming, so wrapping does not hide it; otherwise it
+// yields the delegations of the page returned by find, typically the decorator's own FindDelegations.
+func StreamPage(ctx context.Context, wrapped DelegationsFinder, find func(context.Context, DelegationsCriteria) (*DelegationsPage, error), criteria DelegationsCriteria, yield func(Delegation) error) error {
+	if streamer, ok := wrapped.(DelegationsStreamer); ok {
+		return streamer.FindDelegationsStream(ctx, criteria, yield)
+	}
+
+	page, err := find(ctx, criteria)
+	if err != nil {
+		return err
+	}
+	for _, d := range page.Delegations {
+		if err := yield(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DelegationsTailFinder follows delegations as they are stored, for live streams
+type DelegationsTailFinder interface {
+	DelegationsFinder
+	// LatestID returns the highest stored delegation ID, or 0 when there are none
+	LatestID(ctx context.Context) (int64, error)
+}
+
+// DelegationsKeysetFinder pages through delegations by ascending ID, optionally within a year
+type DelegationsKeysetFinder interface {
+	// FindSinceIDInYear returns up to limit delegations of year (0 = any) with ID greater than id, ordered by ID ascending
+	FindSinceIDInYear(ctx context.Context, year Year, id int64, limit uint64) ([]Delegation, error)
 }
 
 // Delegation represents a delegation in the Tezos blockchain
 type Delegation struct {
 	ID        int64
 	Timestamp time.Time
-	Amount    int64
+	Amount    Amount
 	Delegator string
 	Level     int64
+	CreatedAt time.Time // When the delegation was stored
 }
 
 // DelegationsCriteria specifies criteria for querying delegations using domain Value Objects
 type DelegationsCriteria struct {
-	Year Year    // Year filter (YYYY format). 0 means no year filtering
-	Page Page    // 1-based page number
-	Size PerPage // Items per page
+	Year   Year       // Year filter (YYYY format). 0 means no year filtering
+	Levels LevelRange // Block level filter. The zero range means no level filtering
+	Page   Page       // 1-based page number
+	Size   PerPage    // Items per page
+}
+
+// WithLevelRange returns a copy of the criteria restricted to the given block levels
+func (c DelegationsCriteria) WithLevelRange(levels LevelRange) DelegationsCriteria {
+	c.Levels = levels
+	return c
+}
+
+// SortNewestFirst orders delegations by timestamp, most recent first
+const SortNewestFirst = "-timestamp"
+
+// Sort returns the ordering applied to matching delegations (always newest first)
+func (c DelegationsCriteria) Sort() string {
+	return SortNewestFirst
 }
 
 // ItemsPerPage returns the number of items requested per page
@@ -1,6 +1,8 @@
 package tezos_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -332,3 +334,94 @@ func TestDelegationsCriteria_Integration(t *testing.T) {
 		assert.Equal(t, uint64(0), criteria.ItemsToSkip(), "First page skips 0 items")
 	})
 }
+
+func TestStreamPage(t *testing.T) {
+	t.Parallel()
+
+	page := &tezos.DelegationsPage{Delegations: []tezos.Delegation{{ID: 2}, {ID: 1}}}
+	findPage := func(context.Context, tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) { return page, nil }
+
+	t.Run("it streams through a wrapped finder that supports streaming", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		wrapped := &streamingFinder{delegations: []tezos.Delegation{{ID: 3}}}
+
+		// Act
+		ids, err := collectStreamedIDs(wrapped, findPage)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, ids)
+	})
+
+	t.Run("it yields the found page when the wrapped finder cannot stream", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		ids, err := collectStreamedIDs(nil, findPage)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 1}, ids)
+	})
+
+	t.Run("it returns the error of finding the page", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		errFind := errors.New("query failed")
+
+		// Act
+		_, err := collectStreamedIDs(nil, func(context.Context, tezos.DelegationsCriteria) (*tezos.DelegationsPage, error) {
+			return nil, errFind
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errFind)
+	})
+
+	t.Run("it stops at the first error returned by yield", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		errStop := errors.New("client gone")
+		var ids []int64
+
+		// Act
+		err := tezos.StreamPage(t.Context(), nil, findPage, tezos.DelegationsCriteria{}, func(d tezos.Delegation) error {
+			ids = append(ids, d.ID)
+			return errStop
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, []int64{2}, ids)
+	})
+}
+
+// streamingFinder streams a fixed list of delegations
+type streamingFinder struct {
+	tezos.DelegationsFinder
+
+	delegations []tezos.Delegation
+}
+
+func (f *streamingFinder) FindDelegationsStream(_ context.Context, _ tezos.DelegationsCriteria, yield func(tezos.Delegation) error) error {
+	for _, d := range f.delegations {
+		if err := yield(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectStreamedIDs streams a page through StreamPage and returns the yielded IDs
+func collectStreamedIDs(wrapped tezos.DelegationsFinder, find func(context.Context, tezos.DelegationsCriteria) (*tezos.DelegationsPage, error)) ([]int64, error) {
+	var ids []int64
+	err := tezos.StreamPage(context.Background(), wrapped, find, tezos.DelegationsCriteria{}, func(d tezos.Delegation) error {
+		ids = append(ids, d.ID)
+		return nil
+	})
+	return ids, err
+}
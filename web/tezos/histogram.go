@@ -0,0 +1,51 @@
+package tezos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Amount histogram bucket counts
+const (
+	DefaultHistogramBuckets = 10  // Default number of amount buckets
+	MaxHistogramBuckets     = 100 // Upper bound on amount buckets
+)
+
+// ErrBucketsOutOfRange is returned for a bucket count above MaxHistogramBuckets
+var ErrBucketsOutOfRange = errors.New("bucket count out of range")
+
+// Bucket counts the delegations whose amount falls within [Min, Max], both inclusive
+type Bucket struct {
+	Min   Amount
+	Max   Amount
+	Count int64
+}
+
+// HistogramBuckets resolves a requested bucket count with domain validation.
+// Zero means the default; counts above MaxHistogramBuckets are rejected.
+func HistogramBuckets(buckets uint64) (int, error) {
+	if buckets == 0 {
+		return DefaultHistogramBuckets, nil
+	}
+
+	if buckets > MaxHistogramBuckets {
+		return 0, fmt.Errorf("%w: must be between 1 and %d", ErrBucketsOutOfRange, MaxHistogramBuckets)
+	}
+
+	return int(buckets), nil
+}
+
+// NewHistogram lays out buckets equal-width buckets of integer width starting at lowest,
+// filling in the counts per 1-based bucket number; buckets without a count are empty
+func NewHistogram(lowest, width Amount, buckets int, counts map[int]int64) []Bucket {
+	histogram := make([]Bucket, buckets)
+	for i := range histogram {
+		start := lowest + Amount(i)*width
+		histogram[i] = Bucket{
+			Min:   start,
+			Max:   start + width - 1,
+			Count: counts[i+1],
+		}
+	}
+	return histogram
+}
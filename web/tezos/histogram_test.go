@@ -0,0 +1,65 @@
+package tezos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    uint64
+		expected int
+	}{
+		{name: "zero uses the default", input: 0, expected: tezos.DefaultHistogramBuckets},
+		{name: "a single bucket is kept", input: 1, expected: 1},
+		{name: "maximum is kept", input: tezos.MaxHistogramBuckets, expected: tezos.MaxHistogramBuckets},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			buckets, err := tezos.HistogramBuckets(tc.input)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buckets)
+		})
+	}
+
+	t.Run("it rejects more than the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		_, err := tezos.HistogramBuckets(tezos.MaxHistogramBuckets + 1)
+
+		// Assert
+		assert.ErrorIs(t, err, tezos.ErrBucketsOutOfRange)
+	})
+}
+
+func TestNewHistogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it lays out contiguous inclusive buckets with their counts", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		histogram := tezos.NewHistogram(100, 50, 3, map[int]int64{1: 2, 3: 5})
+
+		// Assert
+		assert.Equal(t, []tezos.Bucket{
+			{Min: 100, Max: 149, Count: 2},
+			{Min: 150, Max: 199, Count: 0},
+			{Min: 200, Max: 249, Count: 5},
+		}, histogram)
+	})
+}
@@ -0,0 +1,44 @@
+package tezos
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LevelRange validation errors
+var (
+	ErrLevelOutOfRange    = errors.New("level out of valid range")
+	ErrLevelRangeInverted = errors.New("min level exceeds max level")
+)
+
+// LevelRange filters delegations by block level (height), both bounds inclusive.
+// A zero bound is open, so the zero LevelRange matches every level.
+type LevelRange struct {
+	Min int64
+	Max int64
+}
+
+// NewLevelRange creates a LevelRange from uint64 bounds with domain validation.
+// Zero means no bound; set bounds must fit in int64 and satisfy min <= max.
+func NewLevelRange(minLevel, maxLevel uint64) (LevelRange, error) {
+	if minLevel > math.MaxInt64 || maxLevel > math.MaxInt64 {
+		return LevelRange{}, fmt.Errorf("%w: must be between 1 and %d", ErrLevelOutOfRange, int64(math.MaxInt64))
+	}
+
+	if minLevel > 0 && maxLevel > 0 && minLevel > maxLevel {
+		return LevelRange{}, fmt.Errorf("%w: %d > %d", ErrLevelRangeInverted, minLevel, maxLevel)
+	}
+
+	return LevelRange{Min: int64(minLevel), Max: int64(maxLevel)}, nil
+}
+
+// HasMin reports whether the range has a lower bound
+func (r LevelRange) HasMin() bool {
+	return r.Min > 0
+}
+
+// HasMax reports whether the range has an upper bound
+func (r LevelRange) HasMax() bool {
+	return r.Max > 0
+}
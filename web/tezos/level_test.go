@@ -0,0 +1,71 @@
+package tezos_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestNewLevelRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it accepts valid ranges", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			min, max uint64
+			expected tezos.LevelRange
+			hasMin   bool
+			hasMax   bool
+		}{
+			{name: "unbounded", expected: tezos.LevelRange{}},
+			{name: "min only", min: 100, expected: tezos.LevelRange{Min: 100}, hasMin: true},
+			{name: "max only", max: 200, expected: tezos.LevelRange{Max: 200}, hasMax: true},
+			{name: "both bounds", min: 100, max: 200, expected: tezos.LevelRange{Min: 100, Max: 200}, hasMin: true, hasMax: true},
+			{name: "single level", min: 150, max: 150, expected: tezos.LevelRange{Min: 150, Max: 150}, hasMin: true, hasMax: true},
+			{name: "largest level", min: math.MaxInt64, expected: tezos.LevelRange{Min: math.MaxInt64}, hasMin: true},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				// Act
+				levels, err := tezos.NewLevelRange(tc.min, tc.max)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, levels)
+				assert.Equal(t, tc.hasMin, levels.HasMin())
+				assert.Equal(t, tc.hasMax, levels.HasMax())
+			})
+		}
+	})
+
+	t.Run("it rejects a min above the max", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		_, err := tezos.NewLevelRange(200, 100)
+
+		// Assert
+		require.ErrorIs(t, err, tezos.ErrLevelRangeInverted)
+	})
+
+	t.Run("it rejects levels beyond int64", func(t *testing.T) {
+		t.Parallel()
+
+		for _, bounds := range [][2]uint64{{math.MaxInt64 + 1, 0}, {0, math.MaxUint64}} {
+			// Act
+			_, err := tezos.NewLevelRange(bounds[0], bounds[1])
+
+			// Assert
+			require.ErrorIs(t, err, tezos.ErrLevelOutOfRange, "bounds %v", bounds)
+		}
+	})
+}
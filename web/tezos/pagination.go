@@ -12,6 +12,18 @@ const (
 	MaxPerPage     = 100 // Maximum items per page
 )
 
+// Recent delegations limits
+const (
+	DefaultRecentLimit = 20  // Default number of recent delegations
+	MaxRecentLimit     = 100 // Upper bound on recent delegations; larger limits are capped
+)
+
+// Top delegations by amount limits
+const (
+	DefaultTopLimit = 10  // Default number of largest delegations
+	MaxTopLimit     = 100 // Upper bound on largest delegations; larger limits are capped
+)
+
 // Page represents a page number for pagination
 type Page uint64
 
@@ -48,6 +60,24 @@ func ParsePerPageFromUint64(perPage uint64) (PerPage, error) {
 	return PerPage(perPage), nil
 }
 
+// RecentLimit resolves a requested recent delegations limit: zero means the default,
+// anything above MaxRecentLimit is capped rather than rejected
+func RecentLimit(limit uint64) uint64 {
+	if limit == 0 {
+		return DefaultRecentLimit
+	}
+	return min(limit, MaxRecentLimit)
+}
+
+// TopLimit resolves a requested top delegations limit: zero means the default,
+// anything above MaxTopLimit is capped rather than rejected
+func TopLimit(limit uint64) uint64 {
+	if limit == 0 {
+		return DefaultTopLimit
+	}
+	return min(limit, MaxTopLimit)
+}
+
 // Uint64 returns the underlying uint64 value
 func (p Page) Uint64() uint64 {
 	return uint64(p)
@@ -64,8 +94,27 @@ type DelegationsPage struct {
 	HasMore     bool    // True if there are more pages after this one
 	Number      Page    // Current page number
 	Size        PerPage // Page size
+	Total       int64   // Total matching delegations; zero when not counted
 }
 
 // Helper methods for pagination state
 func (p *DelegationsPage) HasNext() bool     { return p.HasMore }
 func (p *DelegationsPage) HasPrevious() bool { return p.Number > 1 }
+
+// TotalPages returns the number of pages holding all matching delegations,
+// or 0 when the total was not counted
+func (p *DelegationsPage) TotalPages() uint64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return NewPaginator(uint64(p.Total), p.Size).TotalPages()
+}
+
+// IsLast reports whether this is the last page according to the counted total.
+// It is always false when the total is zero, i.e. not counted; use HasNext instead.
+func (p *DelegationsPage) IsLast() bool {
+	if p.Total <= 0 {
+		return false
+	}
+	return p.Number >= NewPaginator(uint64(p.Total), p.Size).LastPage()
+}
@@ -226,6 +226,60 @@ func TestParsePerPageFromUint64(t *testing.T) {
 	})
 }
 
+func TestRecentLimit(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    uint64
+		expected uint64
+	}{
+		{name: "zero uses the default", input: 0, expected: tezos.DefaultRecentLimit},
+		{name: "within bounds is kept", input: 7, expected: 7},
+		{name: "maximum is kept", input: tezos.MaxRecentLimit, expected: tezos.MaxRecentLimit},
+		{name: "above maximum is capped", input: tezos.MaxRecentLimit + 1, expected: tezos.MaxRecentLimit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			limit := tezos.RecentLimit(tc.input)
+
+			// Assert
+			assert.Equal(t, tc.expected, limit)
+		})
+	}
+}
+
+func TestTopLimit(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    uint64
+		expected uint64
+	}{
+		{name: "zero uses the default", input: 0, expected: tezos.DefaultTopLimit},
+		{name: "within bounds is kept", input: 3, expected: 3},
+		{name: "maximum is kept", input: tezos.MaxTopLimit, expected: tezos.MaxTopLimit},
+		{name: "above maximum is capped", input: tezos.MaxTopLimit + 1, expected: tezos.MaxTopLimit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			limit := tezos.TopLimit(tc.input)
+
+			// Assert
+			assert.Equal(t, tc.expected, limit)
+		})
+	}
+}
+
 func TestPage_Uint64(t *testing.T) {
 	t.Parallel()
 
@@ -393,3 +447,66 @@ func TestDelegationsPage_HasPrevious(t *testing.T) {
 		})
 	}
 }
+
+func TestDelegationsPage_TotalPages(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		total       int64
+		size        tezos.PerPage
+		expectedVal uint64
+	}{
+		{name: "exact division", total: 100, size: 50, expectedVal: 2},
+		{name: "remainder adds a page", total: 101, size: 50, expectedVal: 3},
+		{name: "fewer items than a page", total: 7, size: 50, expectedVal: 1},
+		{name: "zero total", total: 0, size: 50, expectedVal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			page := &tezos.DelegationsPage{Number: 1, Size: tc.size, Total: tc.total}
+
+			// Act
+			result := page.TotalPages()
+
+			// Assert
+			assert.Equal(t, tc.expectedVal, result)
+		})
+	}
+}
+
+func TestDelegationsPage_IsLast(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		pageNumber  tezos.Page
+		total       int64
+		expectedVal bool
+	}{
+		{name: "page before the last", pageNumber: 1, total: 101, expectedVal: false},
+		{name: "last page of a remainder", pageNumber: 3, total: 101, expectedVal: true},
+		{name: "last page of an exact division", pageNumber: 2, total: 100, expectedVal: true},
+		{name: "page beyond the last", pageNumber: 5, total: 100, expectedVal: true},
+		{name: "zero total", pageNumber: 1, total: 0, expectedVal: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			page := &tezos.DelegationsPage{Number: tc.pageNumber, Size: 50, Total: tc.total}
+
+			// Act
+			result := page.IsLast()
+
+			// Assert
+			assert.Equal(t, tc.expectedVal, result)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package tezos
+
+// Paginator computes page navigation when the total number of items is known
+type Paginator struct {
+	total   uint64
+	perPage PerPage
+}
+
+// NewPaginator creates a Paginator for total items split into pages of perPage items.
+// A zero perPage falls back to DefaultPerPage.
+func NewPaginator(total uint64, perPage PerPage) Paginator {
+	if perPage == 0 {
+		perPage = PerPage(DefaultPerPage)
+	}
+	return Paginator{total: total, perPage: perPage}
+}
+
+// Total returns the total number of items
+func (p Paginator) Total() uint64 {
+	return p.total
+}
+
+// TotalPages returns the number of pages needed to hold all items (0 when there are none)
+func (p Paginator) TotalPages() uint64 {
+	size := p.perPage.Uint64()
+	return p.total/size + min(p.total%size, 1)
+}
+
+// Clamp moves a requested page into [1, TotalPages]. With no items, page 1 is returned.
+func (p Paginator) Clamp(page Page) Page {
+	last := Page(max(p.TotalPages(), 1))
+	return min(max(page, 1), last)
+}
+
+// PrevPage returns the page before the given one, if any
+func (p Paginator) PrevPage(page Page) (Page, bool) {
+	page = p.Clamp(page)
+	if page <= 1 {
+		return 0, false
+	}
+	return page - 1, true
+}
+
+// NextPage returns the page after the given one, if any
+func (p Paginator) NextPage(page Page) (Page, bool) {
+	page = p.Clamp(page)
+	if page.Uint64() >= p.TotalPages() {
+		return 0, false
+	}
+	return page + 1, true
+}
+
+// FirstPage returns the first page number
+func (p Paginator) FirstPage() Page {
+	return Page(DefaultPage)
+}
+
+// LastPage returns the last page number (page 1 when there are no items)
+func (p Paginator) LastPage() Page {
+	return Page(max(p.TotalPages(), 1))
+}
@@ -0,0 +1,142 @@
+package tezos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/delegator/web/tezos"
+)
+
+func TestPaginator_TotalPages(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		total    uint64
+		perPage  tezos.PerPage
+		expected uint64
+	}{
+		{name: "no items", total: 0, perPage: 10, expected: 0},
+		{name: "single item", total: 1, perPage: 10, expected: 1},
+		{name: "exactly one full page", total: 10, perPage: 10, expected: 1},
+		{name: "one item over a full page", total: 11, perPage: 10, expected: 2},
+		{name: "several full pages", total: 100, perPage: 25, expected: 4},
+		{name: "page size of 1", total: 7, perPage: 1, expected: 7},
+		{name: "zero per_page uses default", total: 120, perPage: 0, expected: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			paginator := tezos.NewPaginator(tc.total, tc.perPage)
+
+			// Act
+			result := paginator.TotalPages()
+
+			// Assert
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPaginator_Clamp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		total    uint64
+		page     tezos.Page
+		expected tezos.Page
+	}{
+		{name: "zero page clamps to first", total: 30, page: 0, expected: 1},
+		{name: "first page is kept", total: 30, page: 1, expected: 1},
+		{name: "middle page is kept", total: 30, page: 2, expected: 2},
+		{name: "last page is kept", total: 30, page: 3, expected: 3},
+		{name: "page beyond last clamps to last", total: 30, page: 99, expected: 3},
+		{name: "empty results clamp to first", total: 0, page: 5, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			paginator := tezos.NewPaginator(tc.total, tezos.PerPage(10))
+
+			// Act
+			result := paginator.Clamp(tc.page)
+
+			// Assert
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPaginator_Navigation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		total        uint64
+		page         tezos.Page
+		expectedPrev tezos.Page
+		hasPrev      bool
+		expectedNext tezos.Page
+		hasNext      bool
+	}{
+		{name: "first of many pages", total: 30, page: 1, hasPrev: false, expectedNext: 2, hasNext: true},
+		{name: "middle page", total: 30, page: 2, expectedPrev: 1, hasPrev: true, expectedNext: 3, hasNext: true},
+		{name: "last page", total: 30, page: 3, expectedPrev: 2, hasPrev: true, hasNext: false},
+		{name: "page beyond last navigates from last", total: 30, page: 10, expectedPrev: 2, hasPrev: true, hasNext: false},
+		{name: "single page", total: 5, page: 1, hasPrev: false, hasNext: false},
+		{name: "empty results", total: 0, page: 1, hasPrev: false, hasNext: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			paginator := tezos.NewPaginator(tc.total, tezos.PerPage(10))
+
+			// Act
+			prev, hasPrev := paginator.PrevPage(tc.page)
+			next, hasNext := paginator.NextPage(tc.page)
+
+			// Assert
+			assert.Equal(t, tc.hasPrev, hasPrev)
+			assert.Equal(t, tc.expectedPrev, prev)
+			assert.Equal(t, tc.hasNext, hasNext)
+			assert.Equal(t, tc.expectedNext, next)
+		})
+	}
+}
+
+func TestPaginator_FirstAndLastPage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it spans all pages when items exist", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		paginator := tezos.NewPaginator(95, tezos.PerPage(10))
+
+		// Act & Assert
+		assert.Equal(t, tezos.Page(1), paginator.FirstPage())
+		assert.Equal(t, tezos.Page(10), paginator.LastPage())
+	})
+
+	t.Run("it collapses to page 1 for empty results", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		paginator := tezos.NewPaginator(0, tezos.PerPage(10))
+
+		// Act & Assert
+		assert.Equal(t, tezos.Page(1), paginator.FirstPage())
+		assert.Equal(t, tezos.Page(1), paginator.LastPage())
+	})
+}
@@ -17,6 +17,7 @@ type Year uint64
 // Year validation errors
 var (
 	ErrYearOutOfRange = errors.New("year out of valid range")
+	ErrYearRequired   = errors.New("year is required")
 )
 
 // ParseYearFromUint64 creates a Year from uint64 with domain validation
@@ -37,6 +38,49 @@ func ParseYearFromUint64(year uint64) (Year, error) {
 	return Year(year), nil
 }
 
+// ParseRequiredYear is ParseYearFromUint64 for places where a specific year must be given
+func ParseRequiredYear(year uint64) (Year, error) {
+	if year == 0 {
+		return 0, ErrYearRequired
+	}
+	return ParseYearFromUint64(year)
+}
+
+// YearCount is the number of delegations filed under a year
+type YearCount struct {
+	Year  Year
+	Count int64
+}
+
+// DayCount is the number of delegations made on a UTC calendar day
+type DayCount struct {
+	Day   time.Time // Midnight UTC at the start of the day
+	Count int64
+}
+
+// YearTotals is the number and summed amount of delegations filed under a year
+type YearTotals struct {
+	Year   Year
+	Count  int64
+	Amount Amount
+}
+
+// YearComparison puts the totals of two years side by side
+type YearComparison struct {
+	A YearTotals
+	B YearTotals
+}
+
+// CountDelta is how many more delegations B has than A; negative when B has fewer
+func (c YearComparison) CountDelta() int64 {
+	return c.B.Count - c.A.Count
+}
+
+// AmountDelta is how much more was delegated in B than in A; negative when B has less
+func (c YearComparison) AmountDelta() Amount {
+	return c.B.Amount - c.A.Amount
+}
+
 // Uint64 returns the underlying uint64 value
 func (y Year) Uint64() uint64 {
 	return uint64(y)
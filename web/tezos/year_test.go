@@ -237,3 +237,56 @@ func TestYear_Uint64(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequiredYear(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it rejects a missing year", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		_, err := tezos.ParseRequiredYear(0)
+
+		// Assert
+		assert.ErrorIs(t, err, tezos.ErrYearRequired)
+	})
+
+	t.Run("it validates the range like ParseYearFromUint64", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		_, err := tezos.ParseRequiredYear(tezos.MinValidYear - 1)
+
+		// Assert
+		assert.ErrorIs(t, err, tezos.ErrYearOutOfRange)
+	})
+
+	t.Run("it accepts a valid year", func(t *testing.T) {
+		t.Parallel()
+
+		// Act
+		year, err := tezos.ParseRequiredYear(2024)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, tezos.Year(2024), year)
+	})
+}
+
+func TestYearComparisonDeltas(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it subtracts year A from year B", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		comparison := tezos.YearComparison{
+			A: tezos.YearTotals{Year: 2023, Count: 5, Amount: 9_000_000},
+			B: tezos.YearTotals{Year: 2024, Count: 3, Amount: 12_000_000},
+		}
+
+		// Act & Assert
+		assert.Equal(t, int64(-2), comparison.CountDelta())
+		assert.Equal(t, tezos.Amount(3_000_000), comparison.AmountDelta())
+	})
+}
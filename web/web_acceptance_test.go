@@ -3,11 +3,13 @@
 package web_test
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,6 +84,23 @@ func TestWebAPIAcceptanceBehavior(t *testing.T) {
 		t.Logf("✅ Year filtering test completed successfully")
 	})
 
+	t.Run("it echoes the applied criteria when requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerUsingSeededDatabase(t, dbConnString)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithEchoCriteria(t, client, server.URL)
+		delegationsResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertAppliedDefaultCriteria(t, delegationsResp)
+	})
+
 	t.Run("it provides GitHub-style pagination Link headers", func(t *testing.T) {
 		t.Parallel()
 
@@ -165,36 +184,1111 @@ func TestWebAPIAcceptanceBehavior(t *testing.T) {
 	})
 }
 
-// =============================================================================
-// Arrange Phase Helpers - Factory functions for test setup
-// =============================================================================
+// TestWebAPIDelegationsSinceAcceptanceBehavior tests incremental sync via GET /xtz/delegations/since
+func TestWebAPIDelegationsSinceAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
 
-// createTestAPIClient creates an HTTP client for API testing
-func createTestAPIClient(t *testing.T) *http.Client {
-	t.Helper()
-	return http.DefaultClient
+	t.Run("it advances the cursor until no new delegations remain", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		first := makeGetDelegationsSinceRequest(t, client, server.URL, 0, 1)
+		firstResp := parseJSONResponse[api.DelegationsResponse](t, first)
+
+		second := makeGetDelegationsSinceRequest(t, client, server.URL, maxIDFromHeader(t, first), 1)
+		secondResp := parseJSONResponse[api.DelegationsResponse](t, second)
+
+		third := makeGetDelegationsSinceRequest(t, client, server.URL, maxIDFromHeader(t, second), 1)
+		thirdResp := parseJSONResponse[api.DelegationsResponse](t, third)
+
+		// Assert
+		assertSuccessfulResponse(t, first)
+		assertExactDelegationCount(t, firstResp, 1)
+		assertMaxIDHeader(t, first, 1)
+
+		assertSuccessfulResponse(t, second)
+		assertExactDelegationCount(t, secondResp, 1)
+		assertMaxIDHeader(t, second, 2)
+
+		assertSuccessfulResponse(t, third)
+		assertExactDelegationCount(t, thirdResp, 0)
+		assertMaxIDHeader(t, third, 2)
+	})
+
+	t.Run("it rejects a limit above the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsSinceRequest(t, client, server.URL, 0, tezos.MaxPerPage+1)
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
+}
+
+// TestWebAPILevelRangeAcceptanceBehavior tests GET /xtz/delegations?min_level=&max_level=
+func TestWebAPILevelRangeAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns only delegations within the inclusive level range", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "min_level=200&max_level=400")
+		delegationsResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertLevels(t, delegationsResp.Data, "400", "300", "200")
+	})
+
+	t.Run("it composes with the year filter", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "year=2024&min_level=200")
+		delegationsResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertLevels(t, delegationsResp.Data, "200")
+	})
+
+	t.Run("it composes with pagination and keeps the range in links", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		first := makeGetDelegationsWithQuery(t, client, server.URL, "min_level=200&max_level=400&per_page=2")
+		firstResp := parseJSONResponse[api.DelegationsResponse](t, first)
+
+		second := makeGetDelegationsWithQuery(t, client, server.URL, "min_level=200&max_level=400&per_page=2&page=2")
+		secondResp := parseJSONResponse[api.DelegationsResponse](t, second)
+
+		// Assert
+		assertSuccessfulResponse(t, first)
+		assertLevels(t, firstResp.Data, "400", "300")
+		assertContainsNextLink(t, first)
+		assertPreservesQueryParameters(t, first, map[string]string{"min_level": "200", "max_level": "400"})
+
+		assertSuccessfulResponse(t, second)
+		assertLevels(t, secondResp.Data, "200")
+	})
+
+	t.Run("it rejects an inverted range", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "min_level=400&max_level=200")
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
+}
+
+// TestWebAPICreatedAtAcceptanceBehavior tests the include_created_at parameter of GET /xtz/delegations
+func TestWebAPICreatedAtAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it omits created_at unless requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "")
+		delegationsResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertReturnsNonEmptyResults(t, delegationsResp)
+		for i, d := range delegationsResp.Data {
+			assert.Empty(t, d.CreatedAt, "Delegation %d should not include created_at", i)
+		}
+	})
+
+	t.Run("it returns the insertion time when requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		insertedAfter := time.Now().Add(-time.Minute)
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+		insertedBefore := time.Now().Add(time.Minute)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "include_created_at=true")
+		delegationsResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertReturnsNonEmptyResults(t, delegationsResp)
+		assertCreatedWithin(t, delegationsResp.Data, insertedAfter, insertedBefore)
+	})
+
+	t.Run("it rejects a non-boolean value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationsWithQuery(t, client, server.URL, "include_created_at=maybe")
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
+}
+
+// TestWebAPIRecentDelegationsAcceptanceBehavior tests GET /xtz/delegations/recent
+func TestWebAPIRecentDelegationsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the most recent delegations, breaking timestamp ties by ID", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithTimestampTies(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetRecentDelegationsRequest(t, client, server.URL, "limit=3")
+		recentResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertDelegators(t, recentResp.Data, "tz1TieHigherID", "tz1TieLowerID", "tz1Middle")
+	})
+
+	t.Run("it applies the default limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetRecentDelegationsRequest(t, client, server.URL, "")
+		recentResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertExactDelegationCount(t, recentResp, tezos.DefaultRecentLimit)
+		assertDelegationsOrderedMostRecentFirst(t, recentResp.Data)
+	})
+
+	t.Run("it caps a limit above the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetRecentDelegationsRequest(t, client, server.URL, fmt.Sprintf("limit=%d", tezos.MaxRecentLimit*10))
+		recentResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertExactDelegationCount(t, recentResp, tezos.MaxRecentLimit)
+		assertDelegationsOrderedMostRecentFirst(t, recentResp.Data)
+	})
+
+	t.Run("it rejects a non-numeric limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetRecentDelegationsRequest(t, client, server.URL, "limit=many")
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
+}
+
+// TestWebAPITopDelegationsAcceptanceBehavior tests GET /xtz/delegations/top
+func TestWebAPITopDelegationsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the largest delegations first", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetTopDelegationsRequest(t, client, server.URL, "limit=3")
+		topResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertDelegators(t, topResp.Data, "tz1Highest", "tz1LastBucket", "tz1JustAboveEdge")
+	})
+
+	t.Run("it ranks only delegations from the requested year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetTopDelegationsRequest(t, client, server.URL, "limit=10&year=2024")
+		topResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertDelegators(t, topResp.Data, "tz1JustAboveEdge", "tz1Lowest")
+	})
+
+	t.Run("it caps a limit above the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetTopDelegationsRequest(t, client, server.URL, fmt.Sprintf("limit=%d", tezos.MaxTopLimit*10))
+		topResp := parseJSONResponse[api.DelegationsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertExactDelegationCount(t, topResp, tezos.MaxTopLimit)
+		assertDelegationsOrderedLargestFirst(t, topResp.Data)
+	})
+
+	t.Run("it rejects a non-numeric limit", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMinimalData(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetTopDelegationsRequest(t, client, server.URL, "limit=all")
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
 }
 
-// createTestServerUsingSeededDatabase creates a test server that connects to an already-seeded database
-func createTestServerUsingSeededDatabase(t *testing.T, dbConnString string) (*httptest.Server, func()) {
-	t.Helper()
-	return createTestServerWithIsolatedConnection(t, dbConnString)
+// TestWebAPIStreamDelegationsAcceptanceBehavior tests GET /xtz/delegations/stream
+func TestWebAPIStreamDelegationsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it opens the stream at the latest stored delegation", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := openDelegationsStream(t, client, server.URL, "")
+		defer response.Body.Close()
+		latest := readStreamEvent(t, bufio.NewReader(response.Body))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, handler.StreamLatestEvent, latest["event"])
+		assert.Equal(t, "5", latest["id"], "Should start after the highest stored ID")
+	})
+
+	t.Run("it replays delegations after the Last-Event-ID", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := openDelegationsStream(t, client, server.URL, "3")
+		defer response.Body.Close()
+		events := bufio.NewReader(response.Body)
+		latest := readStreamEvent(t, events)
+		fourth := readStreamEvent(t, events)
+		fifth := readStreamEvent(t, events)
+
+		// Assert
+		assert.Equal(t, "3", latest["id"])
+		assert.Equal(t, "4", fourth["id"])
+		assert.Contains(t, fourth["data"], "tz1LastBucket")
+		assert.Equal(t, "5", fifth["id"])
+		assert.Contains(t, fifth["data"], "tz1Highest")
+	})
+}
+
+// TestWebAPIAmountHistogramAcceptanceBehavior tests GET /xtz/delegations/histogram
+func TestWebAPIAmountHistogramAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts amounts per equal-width bucket, boundaries inclusive", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetAmountHistogramRequest(t, client, server.URL, "buckets=4")
+		histogramResp := parseJSONResponse[api.AmountHistogramResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, []api.AmountBucket{
+			{Min: "1000000", Max: "1249999", Count: 2},
+			{Min: "1250000", Max: "1499999", Count: 1},
+			{Min: "1500000", Max: "1749999", Count: 0},
+			{Min: "1750000", Max: "1999999", Count: 2},
+		}, histogramResp.Buckets)
+	})
+
+	t.Run("it uses the default bucket count", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetAmountHistogramRequest(t, client, server.URL, "")
+		histogramResp := parseJSONResponse[api.AmountHistogramResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		require.Len(t, histogramResp.Buckets, tezos.DefaultHistogramBuckets)
+		assert.Equal(t, "1000000", histogramResp.Buckets[0].Min)
+		assert.Equal(t, "1999999", histogramResp.Buckets[tezos.DefaultHistogramBuckets-1].Max)
+		assertBucketCountsSum(t, histogramResp.Buckets, 5)
+	})
+
+	t.Run("it applies the year filter before bucketing", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetAmountHistogramRequest(t, client, server.URL, "buckets=2&year=2024")
+		histogramResp := parseJSONResponse[api.AmountHistogramResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, []api.AmountBucket{
+			{Min: "1000000", Max: "1125000", Count: 1},
+			{Min: "1125001", Max: "1250001", Count: 1},
+		}, histogramResp.Buckets)
+	})
+
+	t.Run("it returns no buckets when nothing matches", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetAmountHistogramRequest(t, client, server.URL, "year=2020")
+		histogramResp := parseJSONResponse[api.AmountHistogramResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Empty(t, histogramResp.Buckets)
+	})
+
+	t.Run("it rejects a bucket count above the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithKnownAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetAmountHistogramRequest(t, client, server.URL, fmt.Sprintf("buckets=%d", tezos.MaxHistogramBuckets+1))
+		defer response.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, "Should return HTTP 400 Bad Request")
+	})
+}
+
+// TestWebAPIDelegationYearsAcceptanceBehavior tests GET /xtz/delegations/years
+func TestWebAPIDelegationYearsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it lists every year with its count in ascending order", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationYearsRequest(t, client, server.URL)
+		yearsResp := parseJSONResponse[api.DelegationYearsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertYearCounts(t, seededDB, yearsResp.Years)
+	})
+
+	t.Run("it counts each year of known data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDuplicateDelegators(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationYearsRequest(t, client, server.URL)
+		yearsResp := parseJSONResponse[api.DelegationYearsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, []api.YearCount{{Year: 2024, Count: 2}, {Year: 2025, Count: 3}}, yearsResp.Years)
+	})
+
+	t.Run("it returns no years for an empty database", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		emptyDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+		defer emptyDB.Close()
+
+		server, cleanup := createTestServerWithIsolatedConnection(t, emptyDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegationYearsRequest(t, client, server.URL)
+		yearsResp := parseJSONResponse[api.DelegationYearsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Empty(t, yearsResp.Years)
+	})
+}
+
+// TestWebAPICompareYearsAcceptanceBehavior tests GET /xtz/delegations/compare
+func TestWebAPICompareYearsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it compares the totals of two years of known data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCompareYearsRequest(t, client, server.URL, "year_a=2024&year_b=2025")
+		comparison := parseJSONResponse[api.YearComparisonResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, api.YearComparisonResponse{
+			YearA:       api.YearTotals{Year: 2024, Count: 2, Amount: "3000000"},
+			YearB:       api.YearTotals{Year: 2025, Count: 3, Amount: "12000000"},
+			CountDelta:  1,
+			AmountDelta: "9000000",
+		}, comparison)
+	})
+
+	t.Run("it computes deltas matching the seeded data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+		first, last := seededYearBounds(t, seededDB)
+
+		// Act
+		response := makeCompareYearsRequest(t, client, server.URL, fmt.Sprintf("year_a=%d&year_b=%d", first, last))
+		comparison := parseJSONResponse[api.YearComparisonResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertYearComparison(t, seededDB, comparison, first, last)
+	})
+
+	t.Run("it reports zero totals for a year without delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCompareYearsRequest(t, client, server.URL, "year_a=2019&year_b=2024")
+		comparison := parseJSONResponse[api.YearComparisonResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, api.YearTotals{Year: 2019, Count: 0, Amount: "0"}, comparison.YearA)
+		assert.Equal(t, int64(2), comparison.CountDelta)
+		assert.Equal(t, "3000000", comparison.AmountDelta)
+	})
+
+	t.Run("it rejects missing or out-of-range years", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithVariedLevels(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		for _, rawQuery := range []string{"year_a=2024", "year_b=2024", "year_a=2024&year_b=1999", "year_a=abc&year_b=2024"} {
+			// Act
+			response := makeCompareYearsRequest(t, client, server.URL, rawQuery)
+			response.Body.Close()
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, rawQuery)
+		}
+	})
+}
+
+// TestWebAPIDailyCountsAcceptanceBehavior tests GET /xtz/delegations/daily
+func TestWebAPIDailyCountsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts delegations per UTC day of the year", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDailyActivity(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeDailyCountsRequest(t, client, server.URL, "year=2024")
+		daily := parseJSONResponse[api.DailyCountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, api.DailyCountsResponse{
+			Year: 2024,
+			Days: []api.DayCount{
+				{Date: "2024-03-01", Count: 2},
+				{Date: "2024-03-02", Count: 3},
+				{Date: "2024-03-05", Count: 1},
+			},
+		}, daily)
+	})
+
+	t.Run("it matches per-day counts of the seeded data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+		_, last := seededYearBounds(t, seededDB)
+
+		// Act
+		response := makeDailyCountsRequest(t, client, server.URL, fmt.Sprintf("year=%d", last))
+		daily := parseJSONResponse[api.DailyCountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertDailyCounts(t, seededDB, daily, last)
+	})
+
+	t.Run("it returns no days for a year without delegations", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDailyActivity(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeDailyCountsRequest(t, client, server.URL, "year=2019")
+		daily := parseJSONResponse[api.DailyCountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, uint64(2019), daily.Year)
+		assert.NotNil(t, daily.Days, "Days should render as an empty list, not null")
+		assert.Empty(t, daily.Days)
+	})
+
+	t.Run("it rejects missing or out-of-range years", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDailyActivity(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		for _, rawQuery := range []string{"", "year=1999", "year=abc"} {
+			// Act
+			response := makeDailyCountsRequest(t, client, server.URL, rawQuery)
+			response.Body.Close()
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, rawQuery)
+		}
+	})
+}
+
+// TestWebAPICumulativeAmountsAcceptanceBehavior tests GET /xtz/delegations/tvl
+func TestWebAPICumulativeAmountsAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it sums amounts per UTC month with a running total", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMonthlyAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCumulativeAmountsRequest(t, client, server.URL, "")
+		tvl := parseJSONResponse[api.CumulativeAmountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, []api.MonthlyAmount{
+			{Month: "2024-01", Amount: "3000000", Cumulative: "3000000"},
+			{Month: "2024-02", Amount: "4000000", Cumulative: "7000000"},
+			{Month: "2024-04", Amount: "5000000", Cumulative: "12000000"},
+			{Month: "2025-01", Amount: "6000000", Cumulative: "18000000"},
+		}, tvl.Months)
+	})
+
+	t.Run("it starts the running total at the first filtered month", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMonthlyAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCumulativeAmountsRequest(t, client, server.URL, "year=2024&min_level=102")
+		tvl := parseJSONResponse[api.CumulativeAmountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, []api.MonthlyAmount{
+			{Month: "2024-02", Amount: "4000000", Cumulative: "4000000"},
+			{Month: "2024-04", Amount: "5000000", Cumulative: "9000000"},
+		}, tvl.Months)
+	})
+
+	t.Run("it matches monthly sums and the running total of the seeded data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCumulativeAmountsRequest(t, client, server.URL, "")
+		tvl := parseJSONResponse[api.CumulativeAmountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertCumulativeAmounts(t, seededDB, tvl)
+	})
+
+	t.Run("it returns no months when nothing matches", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMonthlyAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeCumulativeAmountsRequest(t, client, server.URL, "year=2019")
+		tvl := parseJSONResponse[api.CumulativeAmountsResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.NotNil(t, tvl.Months, "Months should render as an empty list, not null")
+		assert.Empty(t, tvl.Months)
+	})
+
+	t.Run("it rejects invalid filters", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithMonthlyAmounts(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		for _, rawQuery := range []string{"year=1999", "year=abc", "min_level=200&max_level=100"} {
+			// Act
+			response := makeCumulativeAmountsRequest(t, client, server.URL, rawQuery)
+			response.Body.Close()
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, rawQuery)
+		}
+	})
+}
+
+// TestWebAPIDelegatorsCountAcceptanceBehavior tests GET /xtz/delegators/count
+func TestWebAPIDelegatorsCountAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it counts each delegator once", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDuplicateDelegators(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegatorsCountRequest(t, client, server.URL, "")
+		countResp := parseJSONResponse[api.DelegatorsCountResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, int64(3), countResp.Count, "Should count distinct delegators across all years")
+	})
+
+	t.Run("it applies the year filter", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDuplicateDelegators(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegatorsCountRequest(t, client, server.URL, "year=2025")
+		countResp := parseJSONResponse[api.DelegatorsCountResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, int64(2), countResp.Count, "Should count distinct delegators within the year")
+	})
+
+	t.Run("it matches a direct count against seeded data", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		seededDB := migratortest.CreateSeededTestDatabase(t, "../migrator/migrations")
+		defer seededDB.Close()
+
+		server, cleanup := createTestServerUsingSeededDatabase(t, seededDB.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		// Act
+		response := makeGetDelegatorsCountRequest(t, client, server.URL, "")
+		countResp := parseJSONResponse[api.DelegatorsCountResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assertDistinctDelegatorCount(t, seededDB, countResp.Count)
+	})
+}
+
+// TestWebAPIDelegatorsCountConditionalGetAcceptanceBehavior tests ETag revalidation of GET /xtz/delegators/count
+func TestWebAPIDelegatorsCountConditionalGetAcceptanceBehavior(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns 304 on a matching ETag", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		server, cleanup := createTestServerWithDuplicateDelegators(t)
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		first := makeGetDelegatorsCountRequest(t, client, server.URL, "")
+		_ = first.Body.Close()
+		etag := first.Header.Get("ETag")
+		require.NotEmpty(t, etag, "Should tag the aggregate response")
+
+		// Act
+		response := makeConditionalGetDelegatorsCountRequest(t, client, server.URL, etag)
+		defer func() { _ = response.Body.Close() }()
+
+		// Assert
+		assert.Equal(t, http.StatusNotModified, response.StatusCode, "Should not resend an unchanged aggregate")
+		assert.Equal(t, etag, response.Header.Get("ETag"), "Should repeat the current ETag")
+	})
+
+	t.Run("it returns 200 with a fresh ETag after data changes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		db := createDatabaseWithDuplicateDelegators(t)
+		server, cleanup := createTestServerWithIsolatedConnection(t, db.Config().ConnString())
+		defer cleanup()
+		client := createTestAPIClient(t)
+
+		first := makeGetDelegatorsCountRequest(t, client, server.URL, "")
+		_ = first.Body.Close()
+		staleETag := first.Header.Get("ETag")
+
+		_, err := db.Exec(t.Context(), `
+			INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+			VALUES (6, '2025-02-01T10:30:00Z', 6000000, 'tz1Dave', 4600000, 2025)
+		`)
+		require.NoError(t, err, "Should insert a new delegator")
+
+		// Act
+		response := makeConditionalGetDelegatorsCountRequest(t, client, server.URL, staleETag)
+		countResp := parseJSONResponse[api.DelegatorsCountResponse](t, response)
+
+		// Assert
+		assertSuccessfulResponse(t, response)
+		assert.Equal(t, int64(4), countResp.Count, "Should reflect the new delegator")
+		assert.NotEqual(t, staleETag, response.Header.Get("ETag"), "Should issue a fresh ETag")
+	})
+}
+
+// =============================================================================
+// Arrange Phase Helpers - Factory functions for test setup
+// =============================================================================
+
+// createTestAPIClient creates an HTTP client for API testing
+func createTestAPIClient(t *testing.T) *http.Client {
+	t.Helper()
+	return http.DefaultClient
+}
+
+// createTestServerUsingSeededDatabase creates a test server that connects to an already-seeded database
+func createTestServerUsingSeededDatabase(t *testing.T, dbConnString string) (*httptest.Server, func()) {
+	t.Helper()
+	return createTestServerWithIsolatedConnection(t, dbConnString)
+}
+
+// createTestServerWithMinimalData creates a test server with minimal test data
+func createTestServerWithMinimalData(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	// Create clean database with only schema (no seeded data)
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	// Manually insert just 2 test delegations (fits in one page)
+	insertTestDelegations(t, cleanTestDB)
+
+	// Create server with clean database
+	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
+}
+
+// createTestServerWithVariedLevels creates a test server with one delegation per level 100..500, newest at the highest level
+func createTestServerWithVariedLevels(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		VALUES
+			(1, '2024-06-01T10:30:00Z', 1000000, 'tz1Level100', 100, 2024),
+			(2, '2024-07-01T10:30:00Z', 2000000, 'tz1Level200', 200, 2024),
+			(3, '2025-01-01T10:30:00Z', 3000000, 'tz1Level300', 300, 2025),
+			(4, '2025-02-01T10:30:00Z', 4000000, 'tz1Level400', 400, 2025),
+			(5, '2025-03-01T10:30:00Z', 5000000, 'tz1Level500', 500, 2025)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
+
+	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
+}
+
+// createTestServerWithDailyActivity creates a test server whose delegations span several
+// days of 2024, including both ends of a UTC day, plus one in 2025
+func createTestServerWithDailyActivity(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		VALUES
+			(1, '2024-03-01T00:00:00Z', 1000000, 'tz1DayStart', 100, 2024),
+			(2, '2024-03-01T23:59:59Z', 1000000, 'tz1DayEnd', 101, 2024),
+			(3, '2024-03-02T00:00:00Z', 1000000, 'tz1NextDay', 102, 2024),
+			(4, '2024-03-02T12:00:00Z', 1000000, 'tz1Noon', 103, 2024),
+			(5, '2024-03-02T18:00:00Z', 1000000, 'tz1Evening', 104, 2024),
+			(6, '2024-03-05T08:00:00Z', 1000000, 'tz1Later', 105, 2024),
+			(7, '2025-01-01T00:00:00Z', 1000000, 'tz1NextYear', 200, 2025)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
+
+	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
+}
+
+// createTestServerWithMonthlyAmounts creates a test server whose delegations span several
+// months of 2024, including both ends of a UTC month and a month without any, plus one in 2025
+func createTestServerWithMonthlyAmounts(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		VALUES
+			(1, '2024-01-01T00:00:00Z', 1000000, 'tz1MonthStart', 100, 2024),
+			(2, '2024-01-31T23:59:59Z', 2000000, 'tz1MonthEnd', 101, 2024),
+			(3, '2024-02-01T00:00:00Z', 1500000, 'tz1NextMonth', 102, 2024),
+			(4, '2024-02-15T12:00:00Z', 2500000, 'tz1MidMonth', 103, 2024),
+			(5, '2024-04-10T08:00:00Z', 5000000, 'tz1AfterGap', 104, 2024),
+			(6, '2025-01-05T00:00:00Z', 6000000, 'tz1NextYear', 200, 2025)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
+
+	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
+}
+
+// createTestServerWithTimestampTies creates a test server where two delegations share the latest timestamp
+func createTestServerWithTimestampTies(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		VALUES
+			(1, '2025-01-10T10:30:00Z', 1000000, 'tz1Oldest', 4500000, 2025),
+			(2, '2025-01-12T10:30:00Z', 2000000, 'tz1TieLowerID', 4500200, 2025),
+			(3, '2025-01-12T10:30:00Z', 3000000, 'tz1TieHigherID', 4500200, 2025),
+			(4, '2025-01-11T10:30:00Z', 4000000, 'tz1Middle', 4500100, 2025)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
+
+	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
 }
 
-// createTestServerWithMinimalData creates a test server with minimal test data
-func createTestServerWithMinimalData(t *testing.T) (*httptest.Server, func()) {
+// createTestServerWithKnownAmounts creates a test server whose amounts sit on and around
+// the boundaries of four 250 000 mutez buckets spanning 1 000 000..1 999 999
+func createTestServerWithKnownAmounts(t *testing.T) (*httptest.Server, func()) {
 	t.Helper()
 
-	// Create clean database with only schema (no seeded data)
 	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
 	t.Cleanup(func() {
 		cleanTestDB.Close()
 	})
 
-	// Manually insert just 2 test delegations (fits in one page)
-	insertTestDelegations(t, cleanTestDB)
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year)
+		VALUES
+			(1, '2024-06-01T10:30:00Z', 1000000, 'tz1Lowest', 100, 2024),
+			(2, '2024-07-01T10:30:00Z', 1250001, 'tz1JustAboveEdge', 200, 2024),
+			(3, '2025-01-01T10:30:00Z', 1249999, 'tz1FirstBucketTop', 300, 2025),
+			(4, '2025-02-01T10:30:00Z', 1800000, 'tz1LastBucket', 400, 2025),
+			(5, '2025-03-01T10:30:00Z', 1999999, 'tz1Highest', 500, 2025)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
 
-	// Create server with clean database
 	return createTestServerWithIsolatedConnection(t, cleanTestDB.Config().ConnString())
 }
 
@@ -257,6 +1351,214 @@ func makeGetDelegationsWithYearAndPagination(t *testing.T, client *http.Client,
 	return resp
 }
 
+// makeGetDelegationsWithEchoCriteria performs GET /xtz/delegations asking the server to echo its criteria
+func makeGetDelegationsWithEchoCriteria(t *testing.T, client *http.Client, baseURL string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations?echo_criteria=true", nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetDelegatorsCountRequest performs GET /xtz/delegators/count with an optional raw query
+func makeGetDelegatorsCountRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	url := baseURL + "/xtz/delegators/count"
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeConditionalGetDelegatorsCountRequest performs GET /xtz/delegators/count with If-None-Match
+func makeConditionalGetDelegatorsCountRequest(t *testing.T, client *http.Client, baseURL, etag string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegators/count", nil)
+	require.NoError(t, err, "Should create HTTP request")
+	req.Header.Set("If-None-Match", etag)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetDelegationsWithQuery performs GET /xtz/delegations with a raw query
+func makeGetDelegationsWithQuery(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations?"+rawQuery, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetRecentDelegationsRequest performs GET /xtz/delegations/recent with an optional raw query
+func makeGetRecentDelegationsRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	url := baseURL + "/xtz/delegations/recent"
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// openDelegationsStream connects to GET /xtz/delegations/stream, optionally resuming after lastEventID
+func openDelegationsStream(t *testing.T, client *http.Client, baseURL, lastEventID string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/stream", nil)
+	require.NoError(t, err, "Should create HTTP request")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// readStreamEvent reads the next Server-Sent Event as a map of its fields
+func readStreamEvent(t *testing.T, events *bufio.Reader) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	for {
+		line, err := events.ReadString('\n')
+		require.NoError(t, err, "Should read the next event")
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return fields
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		fields[name] = value
+	}
+}
+
+// makeGetTopDelegationsRequest performs GET /xtz/delegations/top with an optional raw query
+func makeGetTopDelegationsRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	url := baseURL + "/xtz/delegations/top"
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetAmountHistogramRequest performs GET /xtz/delegations/histogram with an optional raw query
+func makeGetAmountHistogramRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	url := baseURL + "/xtz/delegations/histogram"
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetDelegationYearsRequest performs GET /xtz/delegations/years
+func makeGetDelegationYearsRequest(t *testing.T, client *http.Client, baseURL string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/years", nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeCompareYearsRequest performs GET /xtz/delegations/compare with the given raw query
+func makeCompareYearsRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/compare?"+rawQuery, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeDailyCountsRequest performs GET /xtz/delegations/daily with the given raw query
+func makeDailyCountsRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/daily?"+rawQuery, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeCumulativeAmountsRequest performs GET /xtz/delegations/tvl with the given raw query
+func makeCumulativeAmountsRequest(t *testing.T, client *http.Client, baseURL, rawQuery string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, baseURL+"/xtz/delegations/tvl?"+rawQuery, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
+// makeGetDelegationsSinceRequest performs GET /xtz/delegations/since with id cursor and limit
+func makeGetDelegationsSinceRequest(t *testing.T, client *http.Client, baseURL string, id int64, limit int) *http.Response {
+	t.Helper()
+
+	url := fmt.Sprintf("%s/xtz/delegations/since?id=%d&limit=%d", baseURL, id, limit)
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err, "Should create HTTP request")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "HTTP request should succeed")
+
+	return resp
+}
+
 // =============================================================================
 // Named Domain Assertions - Business rule assertions
 // =============================================================================
@@ -273,6 +1575,131 @@ func assertReturnsDefaultPagination(t *testing.T, response api.DelegationsRespon
 	assert.Equal(t, tezos.DefaultPerPage, len(response.Data), "Should return exactly %d delegations (default pagination limit)", tezos.DefaultPerPage)
 }
 
+// assertAppliedDefaultCriteria verifies the echoed criteria contain the server defaults
+func assertAppliedDefaultCriteria(t *testing.T, response api.DelegationsResponse) {
+	t.Helper()
+	require.NotNil(t, response.Applied, "Should echo applied criteria")
+	assert.Equal(t, uint64(0), response.Applied.Year, "Should apply no year filter")
+	assert.Equal(t, uint64(tezos.DefaultPage), response.Applied.Page, "Should apply the default page")
+	assert.Equal(t, uint64(tezos.DefaultPerPage), response.Applied.PerPage, "Should apply the default per_page")
+	assert.Equal(t, tezos.SortNewestFirst, response.Applied.Sort, "Should apply newest-first ordering")
+}
+
+// assertDistinctDelegatorCount verifies the API count matches a direct database count
+func assertDistinctDelegatorCount(t *testing.T, db *pgxpool.Pool, actual int64) {
+	t.Helper()
+
+	var expected int64
+	err := db.QueryRow(t.Context(), "SELECT count(DISTINCT delegator) FROM delegations").Scan(&expected)
+	require.NoError(t, err)
+	assert.Positive(t, expected, "Seeded data should contain delegators")
+	assert.Equal(t, expected, actual, "Should match the distinct delegator count in the database")
+}
+
+// assertYearCounts verifies the API year counts match a direct database aggregation
+func assertYearCounts(t *testing.T, db *pgxpool.Pool, actual []api.YearCount) {
+	t.Helper()
+
+	rows, err := db.Query(t.Context(), "SELECT year, count(*) FROM delegations GROUP BY year ORDER BY year")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var expected []api.YearCount
+	for rows.Next() {
+		var y api.YearCount
+		require.NoError(t, rows.Scan(&y.Year, &y.Count))
+		expected = append(expected, y)
+	}
+	require.NoError(t, rows.Err())
+
+	require.NotEmpty(t, expected, "Seeded data should span at least one year")
+	assert.Equal(t, expected, actual, "Should match the per-year counts in the database")
+}
+
+// seededYearBounds returns the earliest and latest year with stored delegations
+func seededYearBounds(t *testing.T, db *pgxpool.Pool) (first, last uint64) {
+	t.Helper()
+
+	err := db.QueryRow(t.Context(), "SELECT MIN(year), MAX(year) FROM delegations").Scan(&first, &last)
+	require.NoError(t, err, "Seeded data should span at least one year")
+	return first, last
+}
+
+// assertYearComparison verifies the API comparison matches direct per-year database totals
+func assertYearComparison(t *testing.T, db *pgxpool.Pool, actual api.YearComparisonResponse, yearA, yearB uint64) {
+	t.Helper()
+
+	totalsOf := func(year uint64) (count, amount int64) {
+		err := db.QueryRow(t.Context(), "SELECT count(*), COALESCE(sum(amount), 0)::bigint FROM delegations WHERE year = $1", year).Scan(&count, &amount)
+		require.NoError(t, err)
+		return count, amount
+	}
+	countA, amountA := totalsOf(yearA)
+	countB, amountB := totalsOf(yearB)
+
+	require.Positive(t, countA, "Seeded data should contain delegations in %d", yearA)
+	assert.Equal(t, api.YearTotals{Year: yearA, Count: countA, Amount: strconv.FormatInt(amountA, 10)}, actual.YearA)
+	assert.Equal(t, api.YearTotals{Year: yearB, Count: countB, Amount: strconv.FormatInt(amountB, 10)}, actual.YearB)
+	assert.Equal(t, countB-countA, actual.CountDelta, "Should report year_b minus year_a delegations")
+	assert.Equal(t, strconv.FormatInt(amountB-amountA, 10), actual.AmountDelta, "Should report year_b minus year_a amount")
+}
+
+// assertDailyCounts verifies the API day counts match direct per-day database counts of year
+func assertDailyCounts(t *testing.T, db *pgxpool.Pool, actual api.DailyCountsResponse, year uint64) {
+	t.Helper()
+
+	rows, err := db.Query(t.Context(), `
+		SELECT to_char(timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date, count(*)
+		FROM delegations WHERE year = $1 GROUP BY date ORDER BY date`, year)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var expected []api.DayCount
+	for rows.Next() {
+		var day api.DayCount
+		require.NoError(t, rows.Scan(&day.Date, &day.Count))
+		expected = append(expected, day)
+	}
+	require.NoError(t, rows.Err())
+
+	require.NotEmpty(t, expected, "Seeded data should contain delegations in %d", year)
+	assert.Equal(t, api.DailyCountsResponse{Year: year, Days: expected}, actual)
+}
+
+// assertCumulativeAmounts verifies the API monthly sums match direct per-month database sums,
+// and that each running total adds the month to the previous one
+func assertCumulativeAmounts(t *testing.T, db *pgxpool.Pool, actual api.CumulativeAmountsResponse) {
+	t.Helper()
+
+	rows, err := db.Query(t.Context(), `
+		SELECT to_char(timestamp AT TIME ZONE 'UTC', 'YYYY-MM') AS month, sum(amount)::bigint
+		FROM delegations GROUP BY month ORDER BY month`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var (
+		expected []api.MonthlyAmount
+		total    int64
+	)
+	for rows.Next() {
+		var (
+			month  string
+			amount int64
+		)
+		require.NoError(t, rows.Scan(&month, &amount))
+		total += amount
+		expected = append(expected, api.MonthlyAmount{
+			Month:      month,
+			Amount:     strconv.FormatInt(amount, 10),
+			Cumulative: strconv.FormatInt(total, 10),
+		})
+	}
+	require.NoError(t, rows.Err())
+
+	require.Greater(t, len(expected), 1, "Seeded data should span several months")
+	assert.Equal(t, expected, actual.Months)
+}
+
 // assertReturnsNonEmptyResults verifies response contains at least one delegation
 func assertReturnsNonEmptyResults(t *testing.T, response api.DelegationsResponse) {
 	t.Helper()
@@ -304,6 +1731,65 @@ func assertDelegationsOrderedMostRecentFirst(t *testing.T, delegations []api.Del
 	t.Logf("✅ Ordering verified: most recent first")
 }
 
+// assertDelegationsOrderedLargestFirst verifies delegations are ordered by amount descending
+func assertDelegationsOrderedLargestFirst(t *testing.T, delegations []api.Delegation) {
+	t.Helper()
+
+	for i := 0; i < len(delegations)-1; i++ {
+		current, err := strconv.ParseInt(delegations[i].Amount, 10, 64)
+		require.NoError(t, err)
+		next, err := strconv.ParseInt(delegations[i+1].Amount, 10, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, current, next,
+			"Delegations should be ordered largest first (index %d: %d should be >= %d)", i, current, next)
+	}
+}
+
+// assertBucketCountsSum verifies every matching delegation landed in exactly one bucket
+func assertBucketCountsSum(t *testing.T, buckets []api.AmountBucket, expected int64) {
+	t.Helper()
+
+	var total int64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	assert.Equal(t, expected, total, "Bucket counts should add up to the matching delegations")
+}
+
+// assertLevels verifies the delegations have exactly the expected levels, in order
+func assertLevels(t *testing.T, delegations []api.Delegation, expected ...string) {
+	t.Helper()
+
+	actual := make([]string, len(delegations))
+	for i, d := range delegations {
+		actual[i] = d.Level
+	}
+	assert.Equal(t, expected, actual, "Should return only in-range levels, most recent first")
+}
+
+// assertCreatedWithin verifies every delegation carries a created_at inside the given window.
+// The window allows for clock skew between the test and the database.
+func assertCreatedWithin(t *testing.T, delegations []api.Delegation, after, before time.Time) {
+	t.Helper()
+
+	for i, d := range delegations {
+		createdAt, err := time.Parse(time.RFC3339, d.CreatedAt)
+		require.NoError(t, err, "Delegation %d should have an RFC3339 created_at", i)
+		assert.WithinRange(t, createdAt, after, before, "Delegation %d should be created at insertion time", i)
+	}
+}
+
+// assertDelegators verifies the delegations belong to the expected delegators, in order
+func assertDelegators(t *testing.T, delegations []api.Delegation, expected ...string) {
+	t.Helper()
+
+	actual := make([]string, len(delegations))
+	for i, d := range delegations {
+		actual[i] = d.Delegator
+	}
+	assert.Equal(t, expected, actual, "Should return exactly these delegators in this order")
+}
+
 // assertAllDelegationsFromYear verifies all delegations are from the specified year
 func assertAllDelegationsFromYear(t *testing.T, delegations []api.Delegation, year int) {
 	t.Helper()
@@ -398,10 +1884,26 @@ func assertPreservesQueryParameters(t *testing.T, resp *http.Response, expectedP
 	}
 }
 
+// assertMaxIDHeader verifies the response carries the expected next cursor
+func assertMaxIDHeader(t *testing.T, resp *http.Response, expected int64) {
+	t.Helper()
+	assert.Equal(t, strconv.FormatInt(expected, 10), resp.Header.Get(handler.MaxIDHeader), "Should return max id %d as the next cursor", expected)
+}
+
 // =============================================================================
 // Utility Functions
 // =============================================================================
 
+// maxIDFromHeader reads the next cursor from the response headers
+func maxIDFromHeader(t *testing.T, resp *http.Response) int64 {
+	t.Helper()
+
+	id, err := strconv.ParseInt(resp.Header.Get(handler.MaxIDHeader), 10, 64)
+	require.NoError(t, err, "Max id header should be a valid integer")
+
+	return id
+}
+
 // parseJSONResponse parses HTTP response body as JSON into the specified type
 func parseJSONResponse[T any](t *testing.T, resp *http.Response) T {
 	t.Helper()
@@ -433,6 +1935,40 @@ func insertTestDelegations(t *testing.T, db *pgxpool.Pool) {
 	require.NoError(t, err, "Should insert test delegations")
 }
 
+// createTestServerWithDuplicateDelegators creates a test server whose data repeats delegators across rows and years
+func createTestServerWithDuplicateDelegators(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	db := createDatabaseWithDuplicateDelegators(t)
+
+	return createTestServerWithIsolatedConnection(t, db.Config().ConnString())
+}
+
+// createDatabaseWithDuplicateDelegators creates a database whose data repeats delegators across rows and years
+func createDatabaseWithDuplicateDelegators(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	cleanTestDB := migratortest.CreateScraperTestDatabase(t, "../migrator/migrations", 0)
+	t.Cleanup(func() {
+		cleanTestDB.Close()
+	})
+
+	// 3 distinct delegators overall, 2 of them in 2025
+	insertSQL := `
+		INSERT INTO delegations (id, timestamp, amount, delegator, level, year) 
+		VALUES 
+			(1, '2025-01-15T10:30:00Z', 1000000, 'tz1Alice', 4500000, 2025),
+			(2, '2025-01-16T10:30:00Z', 2000000, 'tz1Alice', 4500100, 2025),
+			(3, '2025-01-17T10:30:00Z', 3000000, 'tz1Bob', 4500200, 2025),
+			(4, '2024-06-01T10:30:00Z', 4000000, 'tz1Bob', 4000000, 2024),
+			(5, '2024-06-02T10:30:00Z', 5000000, 'tz1Carol', 4000100, 2024)
+	`
+	_, err := cleanTestDB.Exec(t.Context(), insertSQL)
+	require.NoError(t, err, "Should insert test delegations")
+
+	return cleanTestDB
+}
+
 // createTestServerWithIsolatedConnection creates a test server with its own connection pool
 // to the provided database. Each test gets isolated connection resources but shares the read-only database.
 func createTestServerWithIsolatedConnection(t *testing.T, dbConnString string) (*httptest.Server, func()) {
@@ -449,6 +1985,26 @@ func createTestServerWithIsolatedConnection(t *testing.T, dbConnString string) (
 	mux := http.NewServeMux()
 	tezosHandler := handler.NewTezosGetDelegations(store)
 	tezosHandler.AddRoutes(mux)
+	sinceHandler := handler.NewTezosGetDelegationsSince(store)
+	sinceHandler.AddRoutes(mux)
+	countHandler := handler.NewTezosCountDelegators(store)
+	countHandler.AddRoutes(mux)
+	recentHandler := handler.NewTezosGetRecentDelegations(store)
+	recentHandler.AddRoutes(mux)
+	histogramHandler := handler.NewTezosGetAmountHistogram(store)
+	histogramHandler.AddRoutes(mux)
+	yearsHandler := handler.NewTezosGetDelegationYears(store)
+	yearsHandler.AddRoutes(mux)
+	compareHandler := handler.NewTezosCompareYears(store)
+	compareHandler.AddRoutes(mux)
+	dailyHandler := handler.NewTezosGetDailyCounts(store)
+	dailyHandler.AddRoutes(mux)
+	tvlHandler := handler.NewTezosGetCumulativeAmounts(store)
+	tvlHandler.AddRoutes(mux)
+	topHandler := handler.NewTezosGetTopDelegations(store)
+	topHandler.AddRoutes(mux)
+	streamHandler := handler.NewTezosStreamDelegations(store, handler.WithStreamPollInterval(100*time.Millisecond))
+	streamHandler.AddRoutes(mux)
 
 	// Add logging middleware for SUT observability (like production)
 	testCfg := testcfg.New()